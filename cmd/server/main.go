@@ -69,7 +69,7 @@ func main() {
 	router := gin.Default()
 
 	// Configurar rutas
-	routes.SetupRoutes(router, db)
+	routes.SetupRoutes(router, db, cfg)
 
 	// Mostrar información de versión al iniciar
 	log.Printf("TradeOptix Backend %s (Build: %s, Commit: %s)", Version, BuildDate, GitCommit)
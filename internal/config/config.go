@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -13,6 +16,78 @@ type Config struct {
 	Environment string
 	DatabaseURL string
 	JWTSecret   string
+
+	// Similitud mínima (0-1) para registrar coincidencias contra listas de sanciones/PEP
+	ScreeningMatchThreshold float64
+
+	// Motor de riesgo: países de alto riesgo y umbrales de nivel (puntaje 0-100)
+	RiskHighRiskCountries []string
+	RiskMediumThreshold   int
+	RiskHighThreshold     int
+
+	// Vencimiento de documentos KYC y re-verificación periódica
+	KYCExpiryLeadDays         int
+	KYCExpiryReminderDays     []int
+	ReKYCMonthsLow            int
+	ReKYCMonthsMedium         int
+	ReKYCMonthsHigh           int
+	KYCReverificationInterval time.Duration
+
+	// Retención de archivos KYC en días por categoría (0 = no purgar) y frecuencia de la purga
+	RetentionRejectedDays  int
+	RetentionAbandonedDays int
+	RetentionClosedDays    int
+	RetentionPurgeInterval time.Duration
+
+	// Antivirus para archivos subidos: dirección de clamd (vacía = sin análisis) y directorio de cuarentena
+	ClamdAddress  string
+	ClamdTimeout  time.Duration
+	QuarantineDir string
+
+	// Cargas reanudables: directorio de archivos parciales, vencimiento sin actividad y frecuencia de limpieza
+	KYCUploadPartialDir      string
+	KYCUploadSessionTTL      time.Duration
+	KYCUploadCleanupInterval time.Duration
+
+	// Biblioteca de imágenes de noticias: directorio servido públicamente y su URL (ruta o CDN)
+	MediaDir     string
+	MediaBaseURL string
+
+	// Feeds públicos RSS/Atom: sitio web al que enlazan las noticias, URL pública de la API con la
+	// que se arman el id y el enlace self del feed (vacía = la del sitio), cantidad de noticias y caché HTTP
+	NewsFeedSiteURL   string
+	NewsFeedPublicURL string
+	NewsFeedItemLimit int
+	NewsFeedMaxAge    time.Duration
+
+	// Importación de noticias desde fuentes RSS/Atom externas: frecuencia (0 = deshabilitada) y timeout HTTP
+	NewsIngestionInterval time.Duration
+	NewsIngestionTimeout  time.Duration
+
+	// Comentarios y reacciones: nivel KYC requerido para participar y palabras bloqueadas
+	// adicionales a la lista por defecto (los comentarios que las usan quedan en moderación)
+	NewsEngagementKYCLevel  int
+	NewsCommentBlockedWords []string
+
+	// Alertas de noticias publicadas a los suscriptores de la categoría: frecuencia del job
+	// (0 = deshabilitadas), prioridad mínima o categorías que alertan, límite de alertas por
+	// usuario en la ventana indicada y prefijo del enlace a la noticia en la app
+	NewsAlertInterval       time.Duration
+	NewsAlertMinPriority    int
+	NewsAlertCategories     []string
+	NewsAlertMaxPerUser     int
+	NewsAlertThrottleWindow time.Duration
+	NewsAlertDeepLinkBase   string
+
+	// Verificación de identidad externa: proveedor ("" = deshabilitada, "fake" = simulado),
+	// secreto de firma de webhooks y confianza mínima para decidir sin revisión manual
+	IDVProvider              string
+	IDVWebhookSecret         string
+	IDVAutoApproveConfidence float64
+	IDVAutoRejectConfidence  float64
+	IDVFakeCallbackURL       string
+	IDVFakeDecision          string
+	IDVFakeConfidence        float64
 }
 
 func Load() *Config {
@@ -47,11 +122,68 @@ func Load() *Config {
 			dbUser, dbPassword, dbHost, dbPort, dbName)
 	}
 
+	kycExpiryLeadDays := getEnvInt("KYC_EXPIRY_LEAD_DAYS", 7)
+
 	return &Config{
 		Port:        getEnv("PORT", "8080"),
 		Environment: getEnv("ENVIRONMENT", "development"),
 		DatabaseURL: databaseURL,
 		JWTSecret:   getEnv("JWT_SECRET", "your-super-secret-key-change-in-production"),
+
+		ScreeningMatchThreshold: getEnvFloat("SCREENING_MATCH_THRESHOLD", 0.9),
+
+		RiskHighRiskCountries: getEnvList("RISK_HIGH_RISK_COUNTRIES", "Corea del Norte,Irán,Myanmar,Siria,Afganistán,Yemen"),
+		RiskMediumThreshold:   getEnvInt("RISK_MEDIUM_THRESHOLD", 30),
+		RiskHighThreshold:     getEnvInt("RISK_HIGH_THRESHOLD", 60),
+
+		KYCExpiryLeadDays:         kycExpiryLeadDays,
+		KYCExpiryReminderDays:     getExpiryReminderDays(kycExpiryLeadDays),
+		ReKYCMonthsLow:            getEnvInt("REKYC_MONTHS_LOW", 36),
+		ReKYCMonthsMedium:         getEnvInt("REKYC_MONTHS_MEDIUM", 24),
+		ReKYCMonthsHigh:           getEnvInt("REKYC_MONTHS_HIGH", 12),
+		KYCReverificationInterval: getEnvDuration("KYC_REVERIFICATION_INTERVAL", 24*time.Hour),
+
+		RetentionRejectedDays:  getEnvInt("RETENTION_REJECTED_DAYS", 365),
+		RetentionAbandonedDays: getEnvInt("RETENTION_ABANDONED_DAYS", 180),
+		RetentionClosedDays:    getEnvInt("RETENTION_CLOSED_DAYS", 1825),
+		RetentionPurgeInterval: getEnvDuration("RETENTION_PURGE_INTERVAL", 24*time.Hour),
+
+		ClamdAddress:  getEnv("CLAMD_ADDRESS", ""),
+		ClamdTimeout:  getEnvDuration("CLAMD_TIMEOUT", 30*time.Second),
+		QuarantineDir: getEnv("QUARANTINE_DIR", "quarantine"),
+
+		KYCUploadPartialDir:      getEnv("KYC_UPLOAD_PARTIAL_DIR", "uploads_partial"),
+		KYCUploadSessionTTL:      getEnvDuration("KYC_UPLOAD_SESSION_TTL", 24*time.Hour),
+		KYCUploadCleanupInterval: getEnvDuration("KYC_UPLOAD_CLEANUP_INTERVAL", time.Hour),
+
+		MediaDir:     getEnv("MEDIA_DIR", "media"),
+		MediaBaseURL: getEnv("MEDIA_BASE_URL", "/media"),
+
+		NewsFeedSiteURL:   getEnv("NEWS_FEED_SITE_URL", "https://tradeoptix.app"),
+		NewsFeedPublicURL: getEnv("NEWS_FEED_PUBLIC_URL", ""),
+		NewsFeedItemLimit: getEnvInt("NEWS_FEED_ITEM_LIMIT", 20),
+		NewsFeedMaxAge:    getEnvDuration("NEWS_FEED_MAX_AGE", 5*time.Minute),
+
+		NewsIngestionInterval: getEnvDuration("NEWS_INGESTION_INTERVAL", 15*time.Minute),
+		NewsIngestionTimeout:  getEnvDuration("NEWS_INGESTION_TIMEOUT", 30*time.Second),
+
+		NewsEngagementKYCLevel:  getEnvInt("NEWS_ENGAGEMENT_KYC_LEVEL", 1),
+		NewsCommentBlockedWords: getEnvList("NEWS_COMMENT_BLOCKED_WORDS", ""),
+
+		NewsAlertInterval:       getEnvDuration("NEWS_ALERT_INTERVAL", time.Minute),
+		NewsAlertMinPriority:    getEnvInt("NEWS_ALERT_MIN_PRIORITY", 3),
+		NewsAlertCategories:     getEnvList("NEWS_ALERT_CATEGORIES", ""),
+		NewsAlertMaxPerUser:     getEnvInt("NEWS_ALERT_MAX_PER_USER", 3),
+		NewsAlertThrottleWindow: getEnvDuration("NEWS_ALERT_THROTTLE_WINDOW", time.Hour),
+		NewsAlertDeepLinkBase:   getEnv("NEWS_ALERT_DEEP_LINK_BASE", "tradeoptix://news/"),
+
+		IDVProvider:              getEnv("IDV_PROVIDER", ""),
+		IDVWebhookSecret:         getEnv("IDV_WEBHOOK_SECRET", ""),
+		IDVAutoApproveConfidence: getEnvFloat("IDV_AUTO_APPROVE_CONFIDENCE", 0.95),
+		IDVAutoRejectConfidence:  getEnvFloat("IDV_AUTO_REJECT_CONFIDENCE", 0.95),
+		IDVFakeCallbackURL:       getEnv("IDV_FAKE_CALLBACK_URL", "http://localhost:8080/api/v1/webhooks/idv/fake"),
+		IDVFakeDecision:          getEnv("IDV_FAKE_DECISION", "approved"),
+		IDVFakeConfidence:        getEnvFloat("IDV_FAKE_CONFIDENCE", 0.99),
 	}
 }
 
@@ -61,3 +193,73 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+		log.Printf("Advertencia: valor inválido para %s (%s), usando %v\n", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+		log.Printf("Advertencia: valor inválido para %s (%s), usando %v\n", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
+// getEnvList lee una lista separada por comas
+func getEnvList(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+	return values
+}
+
+// getEnvIntList lee una lista de enteros separada por comas, ignorando valores inválidos
+func getEnvIntList(key, defaultValue string) []int {
+	var values []int
+	for _, value := range getEnvList(key, defaultValue) {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			log.Printf("Advertencia: valor inválido en %s (%s), se ignora\n", key, value)
+			continue
+		}
+		values = append(values, parsed)
+	}
+	return values
+}
+
+// getExpiryReminderDays lee los días de recordatorio de vencimiento. Los documentos se marcan
+// vencidos KYC_EXPIRY_LEAD_DAYS antes de la fecha, así que un recordatorio más tardío nunca se enviaría.
+func getExpiryReminderDays(leadDays int) []int {
+	var values []int
+	for _, offset := range getEnvIntList("KYC_EXPIRY_REMINDER_DAYS", "30,7") {
+		if offset < leadDays {
+			log.Printf("Advertencia: KYC_EXPIRY_REMINDER_DAYS incluye %d, menor que KYC_EXPIRY_LEAD_DAYS (%d), se ignora\n", offset, leadDays)
+			continue
+		}
+		values = append(values, offset)
+	}
+	return values
+}
+
+// getEnvDuration lee una duración en formato Go ("24h", "30m")
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+		log.Printf("Advertencia: valor inválido para %s (%s), usando %v\n", key, value, defaultValue)
+	}
+	return defaultValue
+}
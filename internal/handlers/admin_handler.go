@@ -1,7 +1,12 @@
 package handlers
 
 import (
+	"database/sql"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"tradeoptix-back/internal/models"
 	"tradeoptix-back/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -49,15 +54,100 @@ func (h *AdminHandler) ApproveDocument(c *gin.Context) {
 		return
 	}
 
+	// El cuerpo es opcional: permite capturar la fecha de vencimiento del documento
+	var expiresAt *time.Time
+	if c.Request.ContentLength > 0 {
+		var req models.ApproveDocumentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+			return
+		}
+		if req.ExpiresAt != nil {
+			parsed, err := time.Parse("2006-01-02", *req.ExpiresAt)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Fecha de vencimiento inválida, use YYYY-MM-DD"})
+				return
+			}
+			expiresAt = &parsed
+		}
+	}
+
 	err = h.KYCService.ApproveDocument(docID, adminID.(uuid.UUID))
 	if err != nil {
+		if err.Error() == "documento no encontrado" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Documento no encontrado"})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "aprobación bloqueada") || err.Error() == "el documento no está pendiente" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error aprobando documento"})
 		return
 	}
 
+	if expiresAt != nil {
+		if err := h.KYCService.SetDocumentExpiry(docID, *expiresAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Documento aprobado, pero no se pudo guardar el vencimiento", "details": err.Error()})
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Documento aprobado exitosamente"})
 }
 
+// SetDocumentExpiry registra o corrige la fecha de vencimiento de un documento
+func (h *AdminHandler) SetDocumentExpiry(c *gin.Context) {
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de documento inválido"})
+		return
+	}
+
+	var req models.SetDocumentExpiryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	expiresAt, err := time.Parse("2006-01-02", req.ExpiresAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Fecha de vencimiento inválida, use YYYY-MM-DD"})
+		return
+	}
+
+	if err := h.KYCService.SetDocumentExpiry(docID, expiresAt); err != nil {
+		if err.Error() == "documento no encontrado" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error guardando vencimiento", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Vencimiento actualizado exitosamente"})
+}
+
+// RequireReverification obliga a un usuario aprobado a volver a verificar su identidad
+func (h *AdminHandler) RequireReverification(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	if err := h.KYCService.RequireReverification(userID); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Usuario no encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error solicitando re-verificación", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Re-verificación solicitada exitosamente"})
+}
+
 func (h *AdminHandler) RejectDocument(c *gin.Context) {
 	adminID, exists := c.Get("user_id")
 	if !exists {
@@ -72,17 +162,26 @@ func (h *AdminHandler) RejectDocument(c *gin.Context) {
 		return
 	}
 
-	var requestBody struct {
-		Reason string `json:"reason" binding:"required"`
-	}
-
-	if err := c.ShouldBindJSON(&requestBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Razón de rechazo requerida"})
+	var req models.RejectDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Se requiere al menos un código de motivo de rechazo", "details": err.Error()})
 		return
 	}
 
-	err = h.KYCService.RejectDocument(docID, requestBody.Reason, adminID.(uuid.UUID))
+	err = h.KYCService.RejectDocument(docID, req, adminID.(uuid.UUID))
 	if err != nil {
+		if err.Error() == "motivo de rechazo inválido o inactivo" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "documento no encontrado" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Documento no encontrado"})
+			return
+		}
+		if err.Error() == "el documento no está pendiente" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error rechazando documento"})
 		return
 	}
@@ -102,8 +201,9 @@ func (h *AdminHandler) GetDashboardStats(c *gin.Context) {
 
 func (h *AdminHandler) GetUsersByKYCStatus(c *gin.Context) {
 	status := c.Query("status")
+	sortBy := c.Query("sort")
 
-	users, err := h.UserService.GetUsersByKYCStatus(status)
+	users, err := h.UserService.GetUsersByKYCStatus(status, sortBy)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo usuarios"})
 		return
@@ -148,8 +248,214 @@ func (h *AdminHandler) ServeDocument(c *gin.Context) {
 		return
 	}
 
+	if document.PurgedAt != nil {
+		c.JSON(http.StatusGone, gin.H{"error": "Archivo eliminado por la política de retención"})
+		return
+	}
+
+	// Los archivos infectados nunca se sirven
+	if document.Status == models.KYCStatusQuarantined {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Documento en cuarentena", "signature": document.ScanSignature})
+		return
+	}
+
 	// Servir el archivo para vista previa
 	c.Header("Content-Type", document.MimeType)
 	c.Header("Cache-Control", "max-age=3600")
 	c.File(document.FilePath)
 }
+
+// GetKYCTiers obtiene los niveles KYC y los documentos que exige cada uno
+func (h *AdminHandler) GetKYCTiers(c *gin.Context) {
+	tiers, err := h.KYCService.GetTiers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo niveles KYC", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  tiers,
+		"total": len(tiers),
+	})
+}
+
+// UpdateKYCTier cambia los requisitos de un nivel KYC y recalcula el nivel de los usuarios
+func (h *AdminHandler) UpdateKYCTier(c *gin.Context) {
+	level, err := strconv.Atoi(c.Param("level"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nivel KYC inválido"})
+		return
+	}
+
+	var req models.UpdateKYCTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	tier, err := h.KYCService.UpdateTier(level, req)
+	if err != nil {
+		if err.Error() == "nivel KYC no encontrado" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Nivel KYC no encontrado"})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "tipo de documento inválido") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error actualizando nivel KYC", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tier)
+}
+
+func (h *AdminHandler) GetRejectionReasons(c *gin.Context) {
+	activeOnly := c.DefaultQuery("active_only", "false") == "true"
+
+	reasons, err := h.KYCService.GetRejectionReasons(activeOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo motivos de rechazo"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  reasons,
+		"total": len(reasons),
+	})
+}
+
+func (h *AdminHandler) CreateRejectionReason(c *gin.Context) {
+	var req models.CreateRejectionReasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	reason, err := h.KYCService.CreateRejectionReason(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error creando motivo de rechazo", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, reason)
+}
+
+func (h *AdminHandler) UpdateRejectionReason(c *gin.Context) {
+	var req models.UpdateRejectionReasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	err := h.KYCService.UpdateRejectionReason(c.Param("code"), req)
+	if err != nil {
+		if err.Error() == "motivo de rechazo no encontrado" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Motivo de rechazo no encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error actualizando motivo de rechazo", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Motivo de rechazo actualizado exitosamente"})
+}
+
+func (h *AdminHandler) DeleteRejectionReason(c *gin.Context) {
+	err := h.KYCService.DeactivateRejectionReason(c.Param("code"))
+	if err != nil {
+		if err.Error() == "motivo de rechazo no encontrado" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Motivo de rechazo no encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error desactivando motivo de rechazo", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Motivo de rechazo desactivado exitosamente"})
+}
+
+// CloseAccount cierra la cuenta de un usuario
+func (h *AdminHandler) CloseAccount(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	if err := h.UserService.CloseAccount(userID); err != nil {
+		if err.Error() == "usuario no encontrado" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Usuario no encontrado o cuenta ya cerrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error cerrando cuenta", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Cuenta cerrada exitosamente"})
+}
+
+// GetQuarantinedDocuments obtiene los documentos aislados por el antivirus
+func (h *AdminHandler) GetQuarantinedDocuments(c *gin.Context) {
+	documents, err := h.KYCService.GetQuarantinedDocuments()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo documentos en cuarentena", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  documents,
+		"total": len(documents),
+	})
+}
+
+// BulkApproveDocuments aprueba en lote documentos pendientes, por ID de documento o de usuario
+func (h *AdminHandler) BulkApproveDocuments(c *gin.Context) {
+	var req models.BulkKYCRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	result, err := h.KYCService.BulkApprove(req)
+	if err != nil {
+		respondBulkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// BulkRejectDocuments rechaza en lote documentos pendientes con los mismos motivos
+func (h *AdminHandler) BulkRejectDocuments(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	var req models.BulkRejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Se requiere al menos un código de motivo de rechazo", "details": err.Error()})
+		return
+	}
+
+	result, err := h.KYCService.BulkReject(req, adminID.(uuid.UUID))
+	if err != nil {
+		respondBulkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func respondBulkError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "debe indicar document_ids o user_ids",
+		err.Error() == "motivo de rechazo inválido o inactivo",
+		strings.HasPrefix(err.Error(), "demasiados documentos"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error procesando el lote", "details": err.Error()})
+	}
+}
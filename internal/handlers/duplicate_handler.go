@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tradeoptix-back/internal/models"
+	"tradeoptix-back/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DuplicateHandler struct {
+	DuplicateService *services.DuplicateService
+}
+
+func NewDuplicateHandler(duplicateService *services.DuplicateService) *DuplicateHandler {
+	return &DuplicateHandler{
+		DuplicateService: duplicateService,
+	}
+}
+
+// GetDuplicateMatches obtiene el reporte de posibles cuentas duplicadas (solo admins)
+func (h *DuplicateHandler) GetDuplicateMatches(c *gin.Context) {
+	status := c.DefaultQuery("status", string(models.DuplicateMatchOpen))
+	if status == "all" {
+		status = ""
+	}
+
+	matches, err := h.DuplicateService.GetDuplicateMatches(status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo posibles duplicados", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  matches,
+		"total": len(matches),
+	})
+}
+
+// ResolveDuplicateMatch descarta o confirma una coincidencia (solo admins)
+func (h *DuplicateHandler) ResolveDuplicateMatch(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de coincidencia inválido"})
+		return
+	}
+
+	var req models.ResolveDuplicateMatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	err = h.DuplicateService.ResolveDuplicateMatch(id, req, adminID.(uuid.UUID))
+	if err != nil {
+		if err.Error() == "coincidencia no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Coincidencia no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error actualizando coincidencia", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Coincidencia actualizada exitosamente"})
+}
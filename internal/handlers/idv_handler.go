@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"tradeoptix-back/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxWebhookBodySize tamaño máximo aceptado para el cuerpo de un webhook
+const maxWebhookBodySize = 1 << 20
+
+type IDVHandler struct {
+	IDVService *services.IdentityVerificationService
+}
+
+func NewIDVHandler(idvService *services.IdentityVerificationService) *IDVHandler {
+	return &IDVHandler{
+		IDVService: idvService,
+	}
+}
+
+// HandleWebhook recibe el veredicto firmado de un proveedor de verificación de identidad
+func (h *IDVHandler) HandleWebhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error leyendo el cuerpo"})
+		return
+	}
+
+	err = h.IDVService.HandleWebhook(c.Param("provider"), c.Request.Header, body)
+	if err != nil {
+		switch {
+		case err.Error() == "proveedor no encontrado", err.Error() == "verificación no encontrada":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case strings.HasPrefix(err.Error(), "firma inválida"):
+			log.Printf("Webhook IDV rechazado (%s): %v", c.Param("provider"), err)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case strings.HasPrefix(err.Error(), "payload inválido"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case err.Error() == "veredicto en proceso":
+			c.Header("Retry-After", "60")
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error procesando webhook", "details": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook procesado"})
+}
+
+// GetVerifications obtiene los envíos a verificación externa de un usuario (solo admins)
+func (h *IDVHandler) GetVerifications(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	verifications, err := h.IDVService.GetVerifications(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo verificaciones", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  verifications,
+		"total": len(verifications),
+	})
+}
+
+// Submit reenvía los documentos pendientes de un usuario al proveedor externo (solo admins)
+func (h *IDVHandler) Submit(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	verification, err := h.IDVService.Submit(userID)
+	if err != nil {
+		switch err.Error() {
+		case "usuario no encontrado":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "el usuario no tiene documentos pendientes", "verificación externa no configurada":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Error enviando documentos al proveedor", "details": err.Error(), "verification": verification})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, verification)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
 	"path/filepath"
 	"tradeoptix-back/internal/models"
@@ -11,12 +12,14 @@ import (
 )
 
 type KYCHandler struct {
-	KYCService *services.KYCService
+	KYCService  *services.KYCService
+	UserService *services.UserService
 }
 
-func NewKYCHandler(kycService *services.KYCService) *KYCHandler {
+func NewKYCHandler(kycService *services.KYCService, userService *services.UserService) *KYCHandler {
 	return &KYCHandler{
-		KYCService: kycService,
+		KYCService:  kycService,
+		UserService: userService,
 	}
 }
 
@@ -27,7 +30,7 @@ func NewKYCHandler(kycService *services.KYCService) *KYCHandler {
 // @Accept multipart/form-data
 // @Produce json
 // @Security BearerAuth
-// @Param document_type formData string true "Tipo de documento" Enums(cedula_front, cedula_back, face_photo)
+// @Param document_type formData string true "Tipo de documento" Enums(cedula_front, cedula_back, face_photo, proof_of_address)
 // @Param file formData file true "Archivo de imagen"
 // @Success 201 {object} models.KYCDocument
 // @Failure 400 {object} map[string]string
@@ -70,6 +73,7 @@ func (h *KYCHandler) UploadDocument(c *gin.Context) {
 // @Tags KYC
 // @Produce json
 // @Security BearerAuth
+// @Param Accept-Language header string false "Idioma de los motivos de rechazo (es, en)"
 // @Success 200 {array} models.KYCDocument
 // @Failure 401 {object} map[string]string
 // @Router /kyc/documents [get]
@@ -86,6 +90,12 @@ func (h *KYCHandler) GetUserDocuments(c *gin.Context) {
 		return
 	}
 
+	// Mostrar los motivos de rechazo en el idioma del usuario
+	c.Header("Vary", "Accept-Language")
+	if err := h.KYCService.LocalizeRejections(documents, requestLanguages(c, h.UserService)); err != nil {
+		log.Printf("Error traduciendo motivos de rechazo: %v", err)
+	}
+
 	c.JSON(http.StatusOK, documents)
 }
 
@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"tradeoptix-back/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Versión del protocolo tus implementada por las cargas reanudables
+const tusVersion = "1.0.0"
+
+// CreateUpload godoc
+// @Summary Iniciar carga reanudable
+// @Description Abre una carga reanudable (protocolo tus 1.0.0) de un documento KYC. Upload-Metadata debe incluir document_type, filename y filetype codificados en base64
+// @Tags KYC
+// @Produce json
+// @Security BearerAuth
+// @Param Tus-Resumable header string true "Versión del protocolo" Enums(1.0.0)
+// @Param Upload-Length header int true "Tamaño total del archivo en bytes"
+// @Param Upload-Metadata header string true "document_type, filename y filetype en base64"
+// @Success 201 {object} models.KYCUploadSession
+// @Failure 400 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Router /kyc/uploads [post]
+func (h *KYCHandler) CreateUpload(c *gin.Context) {
+	if !checkTusVersion(c) {
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload-Length inválido"})
+		return
+	}
+
+	metadata := parseUploadMetadata(c.GetHeader("Upload-Metadata"))
+	if metadata["document_type"] == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tipo de documento requerido"})
+		return
+	}
+
+	session, err := h.KYCService.CreateUploadSession(userID.(uuid.UUID), metadata["document_type"],
+		metadata["filename"], metadata["filetype"], length)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "archivo demasiado grande") {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Location", strings.TrimSuffix(c.Request.URL.Path, "/")+"/"+session.ID.String())
+	setUploadHeaders(c, session)
+	c.JSON(http.StatusCreated, session)
+}
+
+// GetUploadOffset godoc
+// @Summary Consultar offset de carga
+// @Description Devuelve en Upload-Offset los bytes ya recibidos para reanudar la carga
+// @Tags KYC
+// @Security BearerAuth
+// @Param id path string true "ID de la carga"
+// @Param Tus-Resumable header string true "Versión del protocolo" Enums(1.0.0)
+// @Success 200
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Router /kyc/uploads/{id} [head]
+func (h *KYCHandler) GetUploadOffset(c *gin.Context) {
+	if !checkTusVersion(c) {
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	session, err := h.KYCService.GetUploadSession(userID.(uuid.UUID), sessionID)
+	if err != nil {
+		c.Status(uploadErrorStatus(err))
+		return
+	}
+
+	setUploadHeaders(c, session)
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+}
+
+// GetUpload godoc
+// @Summary Obtener carga reanudable
+// @Description Obtiene el estado de una carga; al completarse incluye el ID del documento KYC creado
+// @Tags KYC
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la carga"
+// @Success 200 {object} models.KYCUploadSession
+// @Failure 404 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Router /kyc/uploads/{id} [get]
+func (h *KYCHandler) GetUpload(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de carga inválido"})
+		return
+	}
+
+	session, err := h.KYCService.GetUploadSession(userID.(uuid.UUID), sessionID)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// PatchUpload godoc
+// @Summary Enviar parte de una carga
+// @Description Agrega bytes a la carga a partir de Upload-Offset. Al recibir el último byte el documento queda registrado
+// @Tags KYC
+// @Accept application/offset+octet-stream
+// @Security BearerAuth
+// @Param id path string true "ID de la carga"
+// @Param Tus-Resumable header string true "Versión del protocolo" Enums(1.0.0)
+// @Param Upload-Offset header int true "Offset actual de la carga"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 410 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 423 {object} map[string]string
+// @Router /kyc/uploads/{id} [patch]
+func (h *KYCHandler) PatchUpload(c *gin.Context) {
+	if !checkTusVersion(c) {
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	if c.ContentType() != "application/offset+octet-stream" {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type debe ser application/offset+octet-stream"})
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "sesión de carga no encontrada"})
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload-Offset inválido"})
+		return
+	}
+
+	session, _, err := h.KYCService.AppendUploadChunk(userID.(uuid.UUID), sessionID, offset, c.Request.Body)
+	if session != nil {
+		setUploadHeaders(c, session)
+	}
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CancelUpload godoc
+// @Summary Cancelar carga reanudable
+// @Description Descarta una carga incompleta y los bytes recibidos
+// @Tags KYC
+// @Security BearerAuth
+// @Param id path string true "ID de la carga"
+// @Param Tus-Resumable header string true "Versión del protocolo" Enums(1.0.0)
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /kyc/uploads/{id} [delete]
+func (h *KYCHandler) CancelUpload(c *gin.Context) {
+	if !checkTusVersion(c) {
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "sesión de carga no encontrada"})
+		return
+	}
+
+	if err := h.KYCService.CancelUploadSession(userID.(uuid.UUID), sessionID); err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// checkTusVersion valida el encabezado Tus-Resumable y lo incluye en la respuesta
+func checkTusVersion(c *gin.Context) bool {
+	c.Header("Tus-Resumable", tusVersion)
+	if c.GetHeader("Tus-Resumable") != tusVersion {
+		c.Header("Tus-Version", tusVersion)
+		c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{"error": "Versión de protocolo tus no soportada"})
+		return false
+	}
+	return true
+}
+
+// setUploadHeaders informa el progreso de la carga en los encabezados tus
+func setUploadHeaders(c *gin.Context, session *models.KYCUploadSession) {
+	c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(session.Length, 10))
+	if session.CompletedAt == nil {
+		c.Header("Upload-Expires", session.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+// parseUploadMetadata decodifica Upload-Metadata: pares "clave valor-base64" separados por coma
+func parseUploadMetadata(header string) map[string]string {
+	metadata := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		parts := strings.Fields(pair)
+		if len(parts) == 0 {
+			continue
+		}
+		value := ""
+		if len(parts) > 1 {
+			decoded, err := base64.StdEncoding.DecodeString(parts[1])
+			if err != nil {
+				continue
+			}
+			value = string(decoded)
+		}
+		metadata[parts[0]] = value
+	}
+	return metadata
+}
+
+// uploadErrorStatus traduce los errores de carga reanudable a códigos HTTP
+func uploadErrorStatus(err error) int {
+	message := err.Error()
+	switch {
+	case message == "sesión de carga no encontrada":
+		return http.StatusNotFound
+	case message == "sesión de carga vencida":
+		return http.StatusGone
+	case message == "carga en curso":
+		return http.StatusLocked
+	case message == "la carga ya fue completada", strings.HasPrefix(message, "offset no coincide"):
+		return http.StatusConflict
+	case strings.HasPrefix(message, "carga interrumpida"), strings.HasPrefix(message, "error "):
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"tradeoptix-back/internal/models"
+	"tradeoptix-back/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// requestLanguages idiomas en que el usuario prefiere recibir el contenido, en orden: el parámetro
+// "lang", el idioma guardado en su perfil (si está autenticado) y los del header Accept-Language
+func requestLanguages(c *gin.Context, userService *services.UserService) []string {
+	var languages []string
+	if lang := c.Query("lang"); lang != "" {
+		languages = append(languages, normalizeLanguage(lang))
+	}
+
+	if userID, exists := c.Get("user_id"); exists && userService != nil {
+		locale, err := userService.GetLocale(userID.(uuid.UUID))
+		if err != nil {
+			log.Printf("Error obteniendo idioma del usuario %s: %v", userID, err)
+		} else if locale != nil {
+			languages = appendLanguage(languages, *locale)
+		}
+	}
+
+	for _, language := range acceptedLanguages(c.GetHeader("Accept-Language")) {
+		languages = appendLanguage(languages, language)
+	}
+	return languages
+}
+
+// normalizeLanguage reduce una etiqueta de idioma a su código base ("en-US" -> "en")
+func normalizeLanguage(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i > 0 {
+		tag = tag[:i]
+	}
+	if tag == "" || tag == "*" {
+		return models.DefaultLanguage
+	}
+	return tag
+}
+
+// acceptedLanguages obtiene los idiomas del header Accept-Language ordenados por preferencia (q),
+// normalizados y sin repetir ("en-US,en;q=0.9,pt;q=0.8" -> en, pt). Los de q=0 se descartan.
+func acceptedLanguages(header string) []string {
+	type weighted struct {
+		language string
+		q        float64
+	}
+
+	var candidates []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, weighted{normalizeLanguage(tag), q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	var languages []string
+	for _, candidate := range candidates {
+		languages = appendLanguage(languages, candidate.language)
+	}
+	return languages
+}
+
+// appendLanguage agrega el idioma a la lista si no está
+func appendLanguage(languages []string, language string) []string {
+	for _, existing := range languages {
+		if existing == language {
+			return languages
+		}
+	}
+	return append(languages, language)
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tradeoptix-back/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type MediaHandler struct {
+	MediaService *services.MediaService
+}
+
+func NewMediaHandler(mediaService *services.MediaService) *MediaHandler {
+	return &MediaHandler{
+		MediaService: mediaService,
+	}
+}
+
+// UploadMedia sube una imagen a la biblioteca y genera sus versiones (solo admins)
+func (h *MediaHandler) UploadMedia(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Archivo requerido"})
+		return
+	}
+
+	var altText *string
+	if alt := strings.TrimSpace(c.PostForm("alt_text")); alt != "" {
+		altText = &alt
+	}
+
+	media, err := h.MediaService.UploadMedia(file, altText, adminID.(uuid.UUID))
+	if err != nil {
+		for _, prefix := range []string{"archivo demasiado grande", "tipo de archivo no permitido", "imagen inválida", "imagen demasiado grande"} {
+			if strings.HasPrefix(err.Error(), prefix) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error subiendo imagen", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, media)
+}
+
+// GetMedia obtiene la biblioteca de imágenes con paginación (solo admins)
+func (h *MediaHandler) GetMedia(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	items, total, err := h.MediaService.GetMedia(page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo imágenes", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":        items,
+		"total":       total,
+		"page":        page,
+		"limit":       limit,
+		"total_pages": (total + limit - 1) / limit,
+	})
+}
+
+// GetMediaByID obtiene una imagen de la biblioteca (solo admins)
+func (h *MediaHandler) GetMediaByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de imagen inválido"})
+		return
+	}
+
+	media, err := h.MediaService.GetMediaByID(id)
+	if err != nil {
+		if err.Error() == "imagen no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Imagen no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo imagen", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// DeleteMedia elimina una imagen que no esté en uso por ninguna noticia (solo admins)
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de imagen inválido"})
+		return
+	}
+
+	if err := h.MediaService.DeleteMedia(id); err != nil {
+		if err.Error() == "imagen no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Imagen no encontrada"})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "imagen en uso") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error eliminando imagen", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Imagen eliminada exitosamente"})
+}
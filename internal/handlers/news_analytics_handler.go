@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// MarkNewsAsRead marca una noticia publicada como leída por el usuario
+func (h *NewsHandler) MarkNewsAsRead(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	news, err := h.NewsService.GetNewsByID(id)
+	if err != nil {
+		if err.Error() == "noticia no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Noticia no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo noticia", "details": err.Error()})
+		return
+	}
+	if !news.IsPublished(time.Now()) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Noticia no encontrada"})
+		return
+	}
+
+	if err := h.NewsService.MarkAsRead(id, userID.(uuid.UUID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error marcando noticia como leída", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Noticia marcada como leída"})
+}
+
+// GetNewsArticleStats obtiene visitas, lectores únicos y tasa de lectura de una noticia (solo admins)
+func (h *NewsHandler) GetNewsArticleStats(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+		return
+	}
+
+	stats, err := h.NewsService.GetArticleStats(id)
+	if err != nil {
+		if err.Error() == "noticia no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Noticia no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo estadísticas", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetNewsTimeSeries obtiene la serie diaria de visitas y lecturas; ?days= (30 por defecto) y
+// ?news_id= limitan el rango y la noticia (solo admins)
+func (h *NewsHandler) GetNewsTimeSeries(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parámetro days inválido"})
+		return
+	}
+
+	var newsID *uuid.UUID
+	if idStr := c.Query("news_id"); idStr != "" {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+			return
+		}
+		newsID = &id
+	}
+
+	points, err := h.NewsService.GetViewsTimeSeries(days, newsID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "el rango debe ser") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo serie temporal", "details": err.Error()})
+		return
+	}
+	if points == nil {
+		points = []models.NewsTimeSeriesPoint{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": points, "total": len(points)})
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BookmarkNews guarda una noticia publicada para leer más tarde
+func (h *NewsHandler) BookmarkNews(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	if err := h.NewsService.AddBookmark(id, userID.(uuid.UUID)); err != nil {
+		if err.Error() == "noticia no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Noticia no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error guardando noticia", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Noticia guardada"})
+}
+
+// RemoveNewsBookmark quita una noticia de las guardadas
+func (h *NewsHandler) RemoveNewsBookmark(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	if err := h.NewsService.RemoveBookmark(id, userID.(uuid.UUID)); err != nil {
+		if err.Error() == "noticia guardada no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Noticia guardada no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error quitando noticia guardada", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Noticia quitada de guardadas"})
+}
+
+// GetBookmarkedNews lista las noticias guardadas por el usuario con paginación
+func (h *NewsHandler) GetBookmarkedNews(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	news, total, err := h.NewsService.GetBookmarks(userID.(uuid.UUID), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo noticias guardadas", "details": err.Error()})
+		return
+	}
+
+	if err := h.NewsService.SetUserState(userID.(uuid.UUID), news); err != nil {
+		log.Printf("Error obteniendo estado de noticias del usuario: %v", err)
+	}
+	h.localizeNews(c, news)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":        news,
+		"total":       total,
+		"page":        page,
+		"limit":       limit,
+		"total_pages": (total + limit - 1) / limit,
+	})
+}
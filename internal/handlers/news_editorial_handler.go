@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ChangeNewsStatus cambia el estado editorial de una noticia (solo admins)
+func (h *NewsHandler) ChangeNewsStatus(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+		return
+	}
+
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	var req models.ChangeNewsStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	news, err := h.NewsService.ChangeStatus(id, req, adminID.(uuid.UUID))
+	if err != nil {
+		respondEditorialError(c, err, "Error cambiando estado de la noticia")
+		return
+	}
+
+	c.JSON(http.StatusOK, news)
+}
+
+// GetNewsRevisions obtiene las versiones guardadas de una noticia (solo admins)
+func (h *NewsHandler) GetNewsRevisions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+		return
+	}
+
+	revisions, err := h.NewsService.GetRevisions(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo versiones", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  revisions,
+		"total": len(revisions),
+	})
+}
+
+// DiffNewsRevisions compara una versión con otra (por defecto con la anterior) (solo admins)
+func (h *NewsHandler) DiffNewsRevisions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+		return
+	}
+
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Versión inválida"})
+		return
+	}
+
+	against := revision - 1
+	if value := c.Query("against"); value != "" {
+		if against, err = strconv.Atoi(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Versión a comparar inválida"})
+			return
+		}
+	}
+
+	diff, err := h.NewsService.DiffRevisions(id, against, revision)
+	if err != nil {
+		respondEditorialError(c, err, "Error comparando versiones")
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// RestoreNewsRevision restaura el contenido de una versión anterior (solo admins)
+func (h *NewsHandler) RestoreNewsRevision(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+		return
+	}
+
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Versión inválida"})
+		return
+	}
+
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	news, err := h.NewsService.RestoreRevision(id, revision, adminID.(uuid.UUID))
+	if err != nil {
+		respondEditorialError(c, err, "Error restaurando versión")
+		return
+	}
+
+	c.JSON(http.StatusOK, news)
+}
+
+// respondEditorialError traduce los errores del flujo editorial a códigos HTTP
+func respondEditorialError(c *gin.Context, err error, message string) {
+	switch {
+	case err.Error() == "noticia no encontrada", err.Error() == "versión no encontrada":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err.Error() == "la noticia debe ser aprobada por alguien distinto de su autor":
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "transición no permitida"), err.Error() == "no se puede editar una noticia archivada",
+		err.Error() == "la noticia fue modificada mientras se cambiaba su estado":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err.Error() == errInvalidPublicationWindow:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message, "details": err.Error()})
+	}
+}
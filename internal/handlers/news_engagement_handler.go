@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SetNewsReaction registra o cambia la reacción del usuario a una noticia (requiere KYC)
+func (h *NewsHandler) SetNewsReaction(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	var req models.SetNewsReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	if err := h.NewsService.SetReaction(id, userID.(uuid.UUID), req.Reaction); err != nil {
+		if err.Error() == "noticia no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Noticia no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error guardando reacción", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reacción guardada", "reaction": req.Reaction})
+}
+
+// RemoveNewsReaction quita la reacción del usuario a una noticia
+func (h *NewsHandler) RemoveNewsReaction(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	if err := h.NewsService.RemoveReaction(id, userID.(uuid.UUID)); err != nil {
+		if err.Error() == "reacción no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Reacción no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error quitando reacción", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reacción eliminada"})
+}
+
+// GetNewsComments lista los hilos de comentarios de una noticia publicada con paginación
+func (h *NewsHandler) GetNewsComments(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	comments, total, err := h.NewsService.GetComments(id, userID.(uuid.UUID), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo comentarios", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":        comments,
+		"total":       total,
+		"page":        page,
+		"limit":       limit,
+		"total_pages": (total + limit - 1) / limit,
+	})
+}
+
+// CreateNewsComment comenta una noticia o responde a un comentario (requiere KYC). Los comentarios
+// con groserías o enlaces quedan pendientes de moderación.
+func (h *NewsHandler) CreateNewsComment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	var req models.CreateNewsCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	comment, err := h.NewsService.CreateComment(id, userID.(uuid.UUID), req)
+	if err != nil {
+		switch {
+		case err.Error() == "noticia no encontrada":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Noticia no encontrada"})
+		case err.Error() == "comentario a responder no encontrado":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Comentario a responder no encontrado"})
+		case strings.HasPrefix(err.Error(), "usuario silenciado"):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case strings.HasPrefix(err.Error(), "el comentario"), strings.HasPrefix(err.Error(), "no se puede responder"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creando comentario", "details": err.Error()})
+		}
+		return
+	}
+
+	if comment.Status == models.NewsCommentStatusPending {
+		c.JSON(http.StatusAccepted, gin.H{"message": "Comentario enviado a moderación", "comment": comment})
+		return
+	}
+	c.JSON(http.StatusCreated, comment)
+}
+
+// DeleteNewsComment elimina un comentario propio
+func (h *NewsHandler) DeleteNewsComment(c *gin.Context) {
+	commentID, err := uuid.Parse(c.Param("comment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de comentario inválido"})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	if err := h.NewsService.DeleteComment(commentID, userID.(uuid.UUID)); err != nil {
+		if err.Error() == "comentario no encontrado" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Comentario no encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error eliminando comentario", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comentario eliminado exitosamente"})
+}
+
+// GetCommentModerationQueue lista los comentarios por estado de moderación; por defecto los
+// pendientes, los más antiguos primero (solo admins)
+func (h *NewsHandler) GetCommentModerationQueue(c *gin.Context) {
+	status := models.NewsCommentStatus(c.DefaultQuery("status", string(models.NewsCommentStatusPending)))
+	switch status {
+	case models.NewsCommentStatusPending, models.NewsCommentStatusApproved, models.NewsCommentStatusRejected, models.NewsCommentStatusDeleted:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Estado de comentario inválido"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	comments, total, err := h.NewsService.GetCommentsForModeration(status, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo comentarios", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":        comments,
+		"total":       total,
+		"page":        page,
+		"limit":       limit,
+		"total_pages": (total + limit - 1) / limit,
+	})
+}
+
+// ModerateNewsComment aprueba o rechaza un comentario (solo admins)
+func (h *NewsHandler) ModerateNewsComment(c *gin.Context) {
+	commentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de comentario inválido"})
+		return
+	}
+
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	var req models.ModerateNewsCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	comment, err := h.NewsService.ModerateComment(commentID, req, adminID.(uuid.UUID))
+	if err != nil {
+		if err.Error() == "comentario no encontrado" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Comentario no encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error moderando comentario", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// GetMutedUsers lista los usuarios silenciados con silencio vigente (solo admins)
+func (h *NewsHandler) GetMutedUsers(c *gin.Context) {
+	users, err := h.NewsService.GetMutedUsers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo usuarios silenciados", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": users, "total": len(users)})
+}
+
+// MuteUser impide que un usuario comente noticias (solo admins)
+func (h *NewsHandler) MuteUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	var req models.MuteUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	if err := h.NewsService.MuteUser(userID, req, adminID.(uuid.UUID)); err != nil {
+		if err.Error() == "usuario no encontrado" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Usuario no encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error silenciando usuario", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Usuario silenciado exitosamente"})
+}
+
+// UnmuteUser vuelve a permitir que un usuario comente noticias (solo admins)
+func (h *NewsHandler) UnmuteUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	if err := h.NewsService.UnmuteUser(userID); err != nil {
+		if err.Error() == "el usuario no está silenciado" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error quitando silencio", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Silencio eliminado exitosamente"})
+}
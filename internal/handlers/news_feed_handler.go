@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"tradeoptix-back/internal/models"
+	"tradeoptix-back/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewsFeedHandler feeds públicos RSS y Atom de las noticias publicadas
+type NewsFeedHandler struct {
+	NewsService *services.NewsService
+	SiteURL     string
+	// PublicURL URL pública de la API; el id y el enlace self del feed no dependen de las cabeceras
+	// de la petición (Host, X-Forwarded-Proto), que el cliente puede falsear
+	PublicURL string
+	ItemLimit int
+	MaxAge    time.Duration
+}
+
+func NewNewsFeedHandler(newsService *services.NewsService, siteURL, publicURL string, itemLimit int, maxAge time.Duration) *NewsFeedHandler {
+	if publicURL == "" {
+		publicURL = siteURL
+	}
+	return &NewsFeedHandler{
+		NewsService: newsService,
+		SiteURL:     siteURL,
+		PublicURL:   publicURL,
+		ItemLimit:   itemLimit,
+		MaxAge:      maxAge,
+	}
+}
+
+// GetRSS devuelve el feed RSS 2.0 de noticias, general o de la categoría indicada; ?tag= filtra por etiqueta (público)
+func (h *NewsFeedHandler) GetRSS(c *gin.Context) {
+	h.serveFeed(c, "application/rss+xml; charset=utf-8", (*services.NewsFeed).RSS)
+}
+
+// GetAtom devuelve el feed Atom 1.0 de noticias, general o de la categoría indicada (público)
+func (h *NewsFeedHandler) GetAtom(c *gin.Context) {
+	h.serveFeed(c, "application/atom+xml; charset=utf-8", (*services.NewsFeed).Atom)
+}
+
+func (h *NewsFeedHandler) serveFeed(c *gin.Context, contentType string, render func(*services.NewsFeed) ([]byte, error)) {
+	title := "TradeOptix - Noticias del mercado"
+	category := c.Param("category")
+	if category != "" {
+		found, err := h.NewsService.GetCategory(category)
+		if err != nil {
+			if err.Error() == "categoría no encontrada" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Categoría no encontrada"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo categoría", "details": err.Error()})
+			return
+		}
+		title += " (" + found.Name + ")"
+	}
+
+	tag := c.Query("tag")
+	news, err := h.NewsService.GetLatestNews(h.ItemLimit, models.NewsFilter{Category: category, Tag: tag})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo noticias", "details": err.Error()})
+		return
+	}
+
+	feed := &services.NewsFeed{
+		Title:       title,
+		Description: "Últimas noticias del mercado publicadas por TradeOptix",
+		SiteURL:     h.SiteURL,
+		SelfURL:     h.feedURL(c.Request.URL.Path, tag),
+		Items:       news,
+	}
+
+	body, err := render(feed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generando feed", "details": err.Error()})
+		return
+	}
+
+	// El ETag depende del contenido, así también cambia cuando una noticia expira o se despublica
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.MaxAge.Seconds())))
+	updated := feed.Updated()
+	if !updated.IsZero() {
+		c.Header("Last-Modified", updated.Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request, etag, updated) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// notModified evalúa las cabeceras de GET condicional; If-None-Match tiene prioridad
+// sobre If-Modified-Since (RFC 9110)
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := r.Header.Get("If-Modified-Since"); since != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(since)
+		if err == nil && !lastModified.Truncate(time.Second).After(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// feedURL URL pública del feed solicitado; incluye la etiqueta para que cada feed filtrado
+// tenga su propio id
+func (h *NewsFeedHandler) feedURL(path, tag string) string {
+	feedURL := strings.TrimRight(h.PublicURL, "/") + path
+	if tag != "" {
+		feedURL += "?" + url.Values{"tag": {tag}}.Encode()
+	}
+	return feedURL
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"tradeoptix-back/internal/models"
 	"tradeoptix-back/internal/services"
@@ -11,13 +14,26 @@ import (
 	"github.com/google/uuid"
 )
 
+// errInvalidPublicationWindow error del servicio cuando expire_at no es posterior a publish_at
+const errInvalidPublicationWindow = "la fecha de expiración debe ser posterior a la de publicación"
+
+// isNewsValidationError indica si el error del servicio se debe a datos inválidos de la noticia
+func isNewsValidationError(err error) bool {
+	message := err.Error()
+	return message == errInvalidPublicationWindow || message == "imagen no encontrada" ||
+		strings.HasPrefix(message, "categoría inválida") || strings.HasPrefix(message, "etiqueta inválida") ||
+		strings.HasPrefix(message, "máximo")
+}
+
 type NewsHandler struct {
 	NewsService *services.NewsService
+	UserService *services.UserService // Idioma preferido de los usuarios
 }
 
-func NewNewsHandler(newsService *services.NewsService) *NewsHandler {
+func NewNewsHandler(newsService *services.NewsService, userService *services.UserService) *NewsHandler {
 	return &NewsHandler{
 		NewsService: newsService,
+		UserService: userService,
 	}
 }
 
@@ -45,6 +61,10 @@ func (h *NewsHandler) CreateNews(c *gin.Context) {
 
 	news, err := h.NewsService.CreateNews(req, adminID.(uuid.UUID))
 	if err != nil {
+		if isNewsValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creando noticia", "details": err.Error()})
 		return
 	}
@@ -57,6 +77,7 @@ func (h *NewsHandler) GetNews(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	category := c.Query("category")
+	search := c.Query("q")
 	activeOnlyStr := c.DefaultQuery("active_only", "false")
 	activeOnly := activeOnlyStr == "true"
 
@@ -67,8 +88,20 @@ func (h *NewsHandler) GetNews(c *gin.Context) {
 		limit = 10
 	}
 
-	news, total, err := h.NewsService.GetNews(page, limit, category, activeOnly)
+	filter := models.NewsFilter{
+		Category:   category,
+		Tag:        c.Query("tag"),
+		Search:     search,
+		Status:     models.NewsStatus(c.Query("status")),
+		ActiveOnly: activeOnly,
+	}
+
+	news, total, err := h.NewsService.GetNews(page, limit, filter)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "la búsqueda") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo noticias", "details": err.Error()})
 		return
 	}
@@ -86,19 +119,96 @@ func (h *NewsHandler) GetNews(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-// GetLatestNews obtiene las últimas noticias activas (para app móvil)
+// SearchNews busca en las noticias publicadas por texto completo (q) con resultados resaltados.
+// La búsqueda se hace sobre el original en español; los resultados se devuelven traducidos.
+func (h *NewsHandler) SearchNews(c *gin.Context) {
+	search := c.Query("q")
+	category := c.Query("category")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	results, total, err := h.NewsService.SearchNews(search, category, page, limit)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "la búsqueda") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error buscando noticias", "details": err.Error()})
+		return
+	}
+
+	// Los resultados se muestran en el idioma del usuario, como en el listado de noticias
+	c.Header("Vary", "Accept-Language")
+	if err := h.NewsService.LocalizeSearchResults(results, requestLanguages(c, h.UserService)); err != nil {
+		log.Printf("Error aplicando traducciones a la búsqueda: %v", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":        results,
+		"total":       total,
+		"page":        page,
+		"limit":       limit,
+		"total_pages": (total + limit - 1) / limit,
+	})
+}
+
+// GetScheduledNews obtiene las noticias programadas para publicarse más adelante (solo admins)
+func (h *NewsHandler) GetScheduledNews(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	news, total, err := h.NewsService.GetScheduledNews(page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo noticias programadas", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":        news,
+		"total":       total,
+		"page":        page,
+		"limit":       limit,
+		"total_pages": (total + limit - 1) / limit,
+	})
+}
+
+// GetLatestNews obtiene las últimas noticias publicadas y vigentes (para app móvil)
 func (h *NewsHandler) GetLatestNews(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
 	if limit < 1 || limit > 50 {
 		limit = 5
 	}
 
-	news, err := h.NewsService.GetLatestNews(limit)
+	news, err := h.NewsService.GetLatestNews(limit, models.NewsFilter{
+		Category: c.Query("category"),
+		Tag:      c.Query("tag"),
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo noticias", "details": err.Error()})
 		return
 	}
 
+	if userID, exists := c.Get("user_id"); exists {
+		if err := h.NewsService.SetUserState(userID.(uuid.UUID), news); err != nil {
+			log.Printf("Error obteniendo estado de noticias del usuario: %v", err)
+		}
+	}
+	h.localizeNews(c, news)
+
 	c.JSON(http.StatusOK, gin.H{"data": news})
 }
 
@@ -121,6 +231,39 @@ func (h *NewsHandler) GetNewsByID(c *gin.Context) {
 		return
 	}
 
+	// Los usuarios no ven noticias programadas, expiradas ni desactivadas
+	role, _ := c.Get("user_role")
+	if role != string(models.UserRoleAdmin) && !news.IsPublished(time.Now()) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Noticia no encontrada"})
+		return
+	}
+
+	// Las visitas de los admins no cuentan en las estadísticas de audiencia
+	if userID, exists := c.Get("user_id"); exists && role != string(models.UserRoleAdmin) {
+		if err := h.NewsService.RecordView(id, userID.(uuid.UUID)); err != nil {
+			log.Printf("Error registrando visita de noticia %s: %v", id, err)
+		}
+		viewed := []models.MarketNews{*news}
+		if err := h.NewsService.SetUserState(userID.(uuid.UUID), viewed); err != nil {
+			log.Printf("Error obteniendo estado de noticia %s del usuario: %v", id, err)
+		}
+		news.IsRead, news.IsBookmarked = viewed[0].IsRead, viewed[0].IsBookmarked
+	}
+
+	if userID, exists := c.Get("user_id"); exists {
+		if err := h.NewsService.SetEngagement(news, userID.(uuid.UUID)); err != nil {
+			log.Printf("Error obteniendo reacciones de noticia %s: %v", id, err)
+		}
+	}
+
+	// Los admins editan el original; los usuarios reciben la mejor traducción disponible
+	if role != string(models.UserRoleAdmin) {
+		localized := []models.MarketNews{*news}
+		h.localizeNews(c, localized)
+		news = &localized[0]
+		c.Header("Content-Language", news.Language)
+	}
+
 	c.JSON(http.StatusOK, news)
 }
 
@@ -133,18 +276,32 @@ func (h *NewsHandler) UpdateNews(c *gin.Context) {
 		return
 	}
 
+	editorID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
 	var req models.UpdateNewsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
 		return
 	}
 
-	err = h.NewsService.UpdateNews(id, req)
+	err = h.NewsService.UpdateNews(id, req, editorID.(uuid.UUID))
 	if err != nil {
 		if err.Error() == "noticia no encontrada" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Noticia no encontrada"})
 			return
 		}
+		if isNewsValidationError(err) || err.Error() == "no hay campos para actualizar" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "no se puede editar una noticia archivada" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error actualizando noticia", "details": err.Error()})
 		return
 	}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"tradeoptix-back/internal/models"
+	"tradeoptix-back/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// NewsSourceHandler administración de las fuentes externas de noticias
+type NewsSourceHandler struct {
+	IngestionService *services.NewsIngestionService
+}
+
+func NewNewsSourceHandler(ingestionService *services.NewsIngestionService) *NewsSourceHandler {
+	return &NewsSourceHandler{
+		IngestionService: ingestionService,
+	}
+}
+
+// GetSources lista las fuentes registradas (solo admins)
+func (h *NewsSourceHandler) GetSources(c *gin.Context) {
+	sources, err := h.IngestionService.GetSources()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo fuentes", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": sources, "total": len(sources)})
+}
+
+// CreateSource registra una fuente RSS/Atom (solo admins)
+func (h *NewsSourceHandler) CreateSource(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	var req models.CreateNewsSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	source, err := h.IngestionService.CreateSource(req, adminID.(uuid.UUID))
+	if err != nil {
+		respondNewsSourceError(c, err, "Error creando fuente")
+		return
+	}
+
+	c.JSON(http.StatusCreated, source)
+}
+
+// UpdateSource edita una fuente (solo admins)
+func (h *NewsSourceHandler) UpdateSource(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de fuente inválido"})
+		return
+	}
+
+	var req models.UpdateNewsSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	source, err := h.IngestionService.UpdateSource(id, req)
+	if err != nil {
+		respondNewsSourceError(c, err, "Error actualizando fuente")
+		return
+	}
+
+	c.JSON(http.StatusOK, source)
+}
+
+// DeleteSource elimina una fuente; las noticias importadas se conservan (solo admins)
+func (h *NewsSourceHandler) DeleteSource(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de fuente inválido"})
+		return
+	}
+
+	if err := h.IngestionService.DeleteSource(id); err != nil {
+		respondNewsSourceError(c, err, "Error eliminando fuente")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Fuente eliminada exitosamente"})
+}
+
+// PollSource consulta una fuente de inmediato e importa sus artículos nuevos como borradores (solo admins)
+func (h *NewsSourceHandler) PollSource(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de fuente inválido"})
+		return
+	}
+
+	result, err := h.IngestionService.PollSource(id)
+	if err != nil {
+		if err.Error() == "fuente no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Fuente no encontrada"})
+			return
+		}
+		// La fuente respondió con error o un feed inválido
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Error consultando fuente", "details": err.Error(), "result": result})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func respondNewsSourceError(c *gin.Context, err error, message string) {
+	switch {
+	case err.Error() == "fuente no encontrada":
+		c.JSON(http.StatusNotFound, gin.H{"error": "Fuente no encontrada"})
+	case err.Error() == "ya existe una fuente con esa URL":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "categoría inválida"), err.Error() == "URL de feed inválida":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message, "details": err.Error()})
+	}
+}
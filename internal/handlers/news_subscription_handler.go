@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetNewsSubscriptions lista las categorías de noticias de las que el usuario recibe alertas
+func (h *NotificationHandler) GetNewsSubscriptions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	subscriptions, err := h.NotificationService.GetNewsSubscriptions(userID.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo suscripciones", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": subscriptions, "total": len(subscriptions)})
+}
+
+// SubscribeNewsCategory suscribe al usuario a las alertas de noticias destacadas de una categoría
+func (h *NotificationHandler) SubscribeNewsCategory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	if err := h.NotificationService.SubscribeNewsCategory(userID.(uuid.UUID), c.Param("category")); err != nil {
+		if strings.HasPrefix(err.Error(), "categoría inválida") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Categoría no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error guardando suscripción", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Suscripción guardada"})
+}
+
+// UnsubscribeNewsCategory deja de enviar al usuario alertas de una categoría
+func (h *NotificationHandler) UnsubscribeNewsCategory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	if err := h.NotificationService.UnsubscribeNewsCategory(userID.(uuid.UUID), c.Param("category")); err != nil {
+		if err.Error() == "suscripción no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Suscripción no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error cancelando suscripción", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Suscripción cancelada"})
+}
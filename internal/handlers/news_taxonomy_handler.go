@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetNewsCategories lista las categorías activas
+func (h *NewsHandler) GetNewsCategories(c *gin.Context) {
+	h.respondCategories(c, true)
+}
+
+// GetAllNewsCategories lista todas las categorías, incluidas las inactivas (solo admins)
+func (h *NewsHandler) GetAllNewsCategories(c *gin.Context) {
+	h.respondCategories(c, false)
+}
+
+func (h *NewsHandler) respondCategories(c *gin.Context, activeOnly bool) {
+	categories, err := h.NewsService.GetCategories(activeOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo categorías", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": categories, "total": len(categories)})
+}
+
+// CreateNewsCategory crea una categoría de noticias (solo admins)
+func (h *NewsHandler) CreateNewsCategory(c *gin.Context) {
+	var req models.CreateNewsCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	category, err := h.NewsService.CreateCategory(req)
+	if err != nil {
+		respondCategoryError(c, err, "Error creando categoría")
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+// UpdateNewsCategory edita una categoría; desactivarla impide asignarla a noticias nuevas (solo admins)
+func (h *NewsHandler) UpdateNewsCategory(c *gin.Context) {
+	var req models.UpdateNewsCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	category, err := h.NewsService.UpdateCategory(c.Param("slug"), req)
+	if err != nil {
+		respondCategoryError(c, err, "Error actualizando categoría")
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// DeleteNewsCategory elimina una categoría sin noticias ni fuentes (solo admins)
+func (h *NewsHandler) DeleteNewsCategory(c *gin.Context) {
+	if err := h.NewsService.DeleteCategory(c.Param("slug")); err != nil {
+		respondCategoryError(c, err, "Error eliminando categoría")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Categoría eliminada exitosamente"})
+}
+
+// GetNewsTags lista las etiquetas con la cantidad de noticias publicadas de cada una
+func (h *NewsHandler) GetNewsTags(c *gin.Context) {
+	tags, err := h.NewsService.GetTags()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo etiquetas", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tags, "total": len(tags)})
+}
+
+// DeleteNewsTag elimina una etiqueta de todas las noticias (solo admins)
+func (h *NewsHandler) DeleteNewsTag(c *gin.Context) {
+	if err := h.NewsService.DeleteTag(c.Param("slug")); err != nil {
+		if err.Error() == "etiqueta no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Etiqueta no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error eliminando etiqueta", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Etiqueta eliminada exitosamente"})
+}
+
+func respondCategoryError(c *gin.Context, err error, message string) {
+	switch {
+	case err.Error() == "categoría no encontrada":
+		c.JSON(http.StatusNotFound, gin.H{"error": "Categoría no encontrada"})
+	case err.Error() == "la categoría ya existe", strings.HasPrefix(err.Error(), "categoría en uso"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "slug de categoría inválido"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message, "details": err.Error()})
+	}
+}
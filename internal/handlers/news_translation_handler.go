@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// localizeNews aplica a las noticias la traducción que mejor se ajusta al usuario; ante un
+// error se devuelve el original
+func (h *NewsHandler) localizeNews(c *gin.Context, news []models.MarketNews) {
+	c.Header("Vary", "Accept-Language")
+	if err := h.NewsService.ApplyTranslations(news, requestLanguages(c, h.UserService)); err != nil {
+		log.Printf("Error aplicando traducciones de noticias: %v", err)
+	}
+}
+
+// GetNewsTranslations lista las traducciones de una noticia (solo admins)
+func (h *NewsHandler) GetNewsTranslations(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+		return
+	}
+
+	translations, err := h.NewsService.GetTranslations(id)
+	if err != nil {
+		if err.Error() == "noticia no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Noticia no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo traducciones", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": translations, "total": len(translations)})
+}
+
+// UpsertNewsTranslation crea o reemplaza la traducción de una noticia al idioma indicado (solo admins)
+func (h *NewsHandler) UpsertNewsTranslation(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+		return
+	}
+
+	editorID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	var req models.UpsertNewsTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	translation, err := h.NewsService.UpsertTranslation(id, normalizeLanguage(c.Param("lang")), req, editorID.(uuid.UUID))
+	if err != nil {
+		if err.Error() == "noticia no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Noticia no encontrada"})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "idioma inválido") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "no se puede editar una noticia archivada" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error guardando traducción", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
+// GetNewsTranslationRevisions lista las versiones de la traducción de una noticia a un idioma (solo admins)
+func (h *NewsHandler) GetNewsTranslationRevisions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+		return
+	}
+
+	revisions, err := h.NewsService.GetTranslationRevisions(id, normalizeLanguage(c.Param("lang")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo versiones", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": revisions, "total": len(revisions)})
+}
+
+// DeleteNewsTranslation elimina la traducción de una noticia a un idioma (solo admins)
+func (h *NewsHandler) DeleteNewsTranslation(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de noticia inválido"})
+		return
+	}
+
+	editorID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	if err := h.NewsService.DeleteTranslation(id, normalizeLanguage(c.Param("lang")), editorID.(uuid.UUID)); err != nil {
+		if err.Error() == "traducción no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Traducción no encontrada"})
+			return
+		}
+		if err.Error() == "noticia no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Noticia no encontrada"})
+			return
+		}
+		if err.Error() == "no se puede editar una noticia archivada" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error eliminando traducción", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Traducción eliminada exitosamente"})
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tradeoptix-back/internal/models"
+	"tradeoptix-back/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RetentionHandler struct {
+	RetentionService *services.RetentionService
+}
+
+func NewRetentionHandler(retentionService *services.RetentionService) *RetentionHandler {
+	return &RetentionHandler{
+		RetentionService: retentionService,
+	}
+}
+
+// GetReport lista los archivos KYC que se purgarían, sin eliminarlos (solo admins)
+func (h *RetentionHandler) GetReport(c *gin.Context) {
+	report, err := h.RetentionService.Report()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generando reporte de retención", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Purge ejecuta la purga de archivos KYC vencidos inmediatamente (solo admins)
+func (h *RetentionHandler) Purge(c *gin.Context) {
+	report, err := h.RetentionService.Purge()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error purgando archivos", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetTombstones obtiene las constancias de archivos purgados (solo admins)
+func (h *RetentionHandler) GetTombstones(c *gin.Context) {
+	var userID *uuid.UUID
+	if raw := c.Query("user_id"); raw != "" {
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+			return
+		}
+		userID = &parsed
+	}
+
+	tombstones, err := h.RetentionService.GetTombstones(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo constancias de purga", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  tombstones,
+		"total": len(tombstones),
+	})
+}
+
+// SetLegalHold activa o levanta la retención legal de un usuario (solo admins)
+func (h *RetentionHandler) SetLegalHold(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	var req models.SetLegalHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	if err := h.RetentionService.SetLegalHold(userID, req, adminID.(uuid.UUID)); err != nil {
+		if err.Error() == "usuario no encontrado" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error actualizando retención legal", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Retención legal actualizada exitosamente"})
+}
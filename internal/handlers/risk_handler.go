@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tradeoptix-back/internal/models"
+	"tradeoptix-back/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RiskHandler struct {
+	RiskService *services.RiskService
+}
+
+func NewRiskHandler(riskService *services.RiskService) *RiskHandler {
+	return &RiskHandler{
+		RiskService: riskService,
+	}
+}
+
+// GetRiskHistory obtiene el historial de puntajes de riesgo de un usuario (solo admins)
+func (h *RiskHandler) GetRiskHistory(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	history, err := h.RiskService.GetRiskHistory(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo historial de riesgo", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  history,
+		"total": len(history),
+	})
+}
+
+// RecalculateRisk recalcula el puntaje de riesgo de un usuario (solo admins)
+func (h *RiskHandler) RecalculateRisk(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	assessment, err := h.RiskService.Recalculate(userID, "manual")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error calculando riesgo", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, assessment)
+}
+
+// GetWeights obtiene los pesos de los factores de riesgo (solo admins)
+func (h *RiskHandler) GetWeights(c *gin.Context) {
+	weights, err := h.RiskService.GetWeights()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo pesos de riesgo", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": weights})
+}
+
+// UpdateWeights cambia los pesos y recalcula el riesgo de todos los usuarios (solo admins)
+func (h *RiskHandler) UpdateWeights(c *gin.Context) {
+	var req models.UpdateRiskWeightsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	if err := h.RiskService.UpdateWeights(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error actualizando pesos de riesgo", "details": err.Error()})
+		return
+	}
+
+	recalculated, err := h.RiskService.RecalculateAll("weights_updated")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Pesos actualizados, pero falló el recálculo", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Pesos de riesgo actualizados exitosamente",
+		"recalculated": recalculated,
+	})
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tradeoptix-back/internal/models"
+	"tradeoptix-back/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ScreeningHandler struct {
+	ScreeningService *services.ScreeningService
+}
+
+func NewScreeningHandler(screeningService *services.ScreeningService) *ScreeningHandler {
+	return &ScreeningHandler{
+		ScreeningService: screeningService,
+	}
+}
+
+// GetWatchlists obtiene las listas de sanciones/PEP cargadas (solo admins)
+func (h *ScreeningHandler) GetWatchlists(c *gin.Context) {
+	lists, err := h.ScreeningService.GetWatchlists()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo listas", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  lists,
+		"total": len(lists),
+	})
+}
+
+// ImportWatchlist importa una lista desde un archivo CSV/XML y reevalúa a los usuarios (solo admins)
+func (h *ScreeningHandler) ImportWatchlist(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	name := c.PostForm("name")
+	format := c.PostForm("format")
+	listType := c.DefaultPostForm("list_type", "sanctions")
+	if name == "" || format == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nombre y formato de la lista requeridos"})
+		return
+	}
+	if listType != "sanctions" && listType != "pep" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tipo de lista inválido (sanctions, pep)"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Archivo requerido"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error abriendo archivo"})
+		return
+	}
+	defer file.Close()
+
+	list, hits, err := h.ScreeningService.ImportWatchlist(name, format, listType, file, adminID.(uuid.UUID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error importando lista", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":   "Lista importada exitosamente",
+		"watchlist": list,
+		"hits":      hits,
+	})
+}
+
+// GetHits obtiene las coincidencias de screening (solo admins)
+func (h *ScreeningHandler) GetHits(c *gin.Context) {
+	status := c.DefaultQuery("status", string(models.ScreeningHitPending))
+	if status == "all" {
+		status = ""
+	}
+
+	hits, err := h.ScreeningService.GetHits(status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error obteniendo coincidencias", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  hits,
+		"total": len(hits),
+	})
+}
+
+// ResolveHit descarta o confirma una coincidencia de screening (solo admins)
+func (h *ScreeningHandler) ResolveHit(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de coincidencia inválido"})
+		return
+	}
+
+	var req models.ResolveScreeningHitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	err = h.ScreeningService.ResolveHit(id, req, adminID.(uuid.UUID))
+	if err != nil {
+		if err.Error() == "coincidencia no encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Coincidencia no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error actualizando coincidencia", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Coincidencia actualizada exitosamente"})
+}
+
+// ScreenUser vuelve a evaluar a un usuario contra todas las listas (solo admins)
+func (h *ScreeningHandler) ScreenUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de usuario inválido"})
+		return
+	}
+
+	hits, err := h.ScreeningService.ScreenUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error evaluando usuario", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Screening completado",
+		"hits":    hits,
+	})
+}
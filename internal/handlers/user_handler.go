@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 	"tradeoptix-back/internal/models"
 	"tradeoptix-back/internal/services"
 
@@ -122,4 +123,85 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 	user.PasswordHash = ""
 	
 	c.JSON(http.StatusOK, user)
-}
\ No newline at end of file
+}
+// RefreshToken godoc
+// @Summary Renovar token
+// @Description Emite un nuevo token JWT con el rol y el nivel KYC actuales del usuario
+// @Tags usuarios
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.LoginResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/token/refresh [post]
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	loginResponse, err := h.UserService.RefreshToken(userID.(uuid.UUID))
+	if err != nil {
+		switch err.Error() {
+		case "usuario no encontrado":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Usuario no encontrado"})
+		case "cuenta cerrada":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error renovando token", "details": err.Error()})
+		}
+		return
+	}
+
+	// No devolver el hash de la contraseña
+	loginResponse.User.PasswordHash = ""
+
+	c.JSON(http.StatusOK, loginResponse)
+}
+
+// UpdateLocale godoc
+// @Summary Idioma preferido
+// @Description Guarda el idioma en que el usuario prefiere leer las noticias; null o vacío vuelve a usar Accept-Language
+// @Tags usuarios
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdateLocaleRequest true "Idioma (ej: en)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /users/locale [put]
+func (h *UserHandler) UpdateLocale(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	var req models.UpdateLocaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	var locale *string
+	if req.Locale != nil && strings.TrimSpace(*req.Locale) != "" {
+		normalized := normalizeLanguage(*req.Locale)
+		locale = &normalized
+	}
+
+	if err := h.UserService.SetLocale(userID.(uuid.UUID), locale); err != nil {
+		switch err.Error() {
+		case "idioma inválido":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "usuario no encontrado":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Usuario no encontrado"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error guardando idioma", "details": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Idioma actualizado", "locale": locale})
+}
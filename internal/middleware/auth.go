@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 	"tradeoptix-back/internal/models"
@@ -51,6 +52,13 @@ func JWTAuth(jwtSecret string) gin.HandlerFunc {
 		c.Set("user_id", userID)
 		c.Set("user_email", claims["email"].(string))
 		c.Set("user_role", claims["role"].(string))
+
+		// Los tokens emitidos antes de los niveles KYC no traen el claim: nivel 0
+		kycLevel := 0
+		if level, ok := claims["kyc_level"].(float64); ok {
+			kycLevel = int(level)
+		}
+		c.Set("kyc_level", kycLevel)
 		c.Next()
 	}
 }
@@ -74,6 +82,50 @@ func AdminAuth() gin.HandlerFunc {
 	}
 }
 
+// KYCLevelSource obtiene el nivel KYC vigente de un usuario
+type KYCLevelSource interface {
+	CurrentKYCLevel(userID uuid.UUID) (int, error)
+}
+
+// RequireKYCLevel restringe la ruta a usuarios con al menos el nivel KYC indicado. El nivel se
+// consulta en cada petición: el claim del token puede estar desactualizado tras un rechazo,
+// un vencimiento o una re-verificación.
+func RequireKYCLevel(levels KYCLevelSource, minLevel int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if role, _ := c.Get("user_role"); role == string(models.UserRoleAdmin) {
+			c.Next()
+			return
+		}
+
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+			c.Abort()
+			return
+		}
+
+		level, err := levels.CurrentKYCLevel(userID.(uuid.UUID))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error verificando nivel KYC"})
+			c.Abort()
+			return
+		}
+		c.Set("kyc_level", level)
+
+		if level < minLevel {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":              fmt.Sprintf("Acceso denegado. Requiere nivel de verificación %d", minLevel),
+				"required_kyc_level": minLevel,
+				"kyc_level":          level,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
@@ -13,6 +13,7 @@ type CORSConfig struct {
 	AllowOrigins     []string
 	AllowMethods     []string
 	AllowHeaders     []string
+	ExposeHeaders    []string
 	AllowCredentials bool
 }
 
@@ -37,12 +38,18 @@ func DefaultCORSConfig() *CORSConfig {
 	return &CORSConfig{
 		AllowOrigins: origins,
 		AllowMethods: []string{
-			"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS",
+			"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS",
 		},
 		AllowHeaders: []string{
 			"Content-Type", "Content-Length", "Accept-Encoding",
 			"X-CSRF-Token", "Authorization", "accept", "origin",
 			"Cache-Control", "X-Requested-With",
+			// Cargas reanudables (tus)
+			"Tus-Resumable", "Upload-Length", "Upload-Metadata", "Upload-Offset",
+		},
+		ExposeHeaders: []string{
+			"Location", "Tus-Resumable", "Tus-Version",
+			"Upload-Offset", "Upload-Length", "Upload-Expires",
 		},
 		AllowCredentials: true,
 	}
@@ -104,6 +111,9 @@ func NewCORS(config *CORSConfig) gin.HandlerFunc {
 
 			c.Header("Access-Control-Allow-Methods", strings.Join(config.AllowMethods, ", "))
 			c.Header("Access-Control-Allow-Headers", strings.Join(config.AllowHeaders, ", "))
+			if len(config.ExposeHeaders) > 0 {
+				c.Header("Access-Control-Expose-Headers", strings.Join(config.ExposeHeaders, ", "))
+			}
 			c.Header("Vary", "Origin")
 		}
 
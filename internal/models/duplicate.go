@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DuplicateMatchStatus string
+
+const (
+	DuplicateMatchOpen      DuplicateMatchStatus = "open"
+	DuplicateMatchDismissed DuplicateMatchStatus = "dismissed"
+	DuplicateMatchConfirmed DuplicateMatchStatus = "confirmed"
+)
+
+// DuplicateUserSummary datos básicos de una cuenta involucrada en una coincidencia
+type DuplicateUserSummary struct {
+	ID             uuid.UUID    `json:"id"`
+	FirstName      string       `json:"first_name"`
+	LastName       string       `json:"last_name"`
+	Email          string       `json:"email"`
+	DocumentType   DocumentType `json:"document_type"`
+	DocumentNumber string       `json:"document_number"`
+	KYCStatus      KYCStatus    `json:"kyc_status"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// DuplicateMatch representa una posible identidad duplicada entre dos cuentas
+type DuplicateMatch struct {
+	ID                uuid.UUID            `json:"id" db:"id"`
+	UserID            uuid.UUID            `json:"user_id" db:"user_id"`
+	MatchedUserID     uuid.UUID            `json:"matched_user_id" db:"matched_user_id"`
+	MatchType         string               `json:"match_type" db:"match_type"`
+	DocumentID        *uuid.UUID           `json:"document_id,omitempty" db:"document_id"`
+	MatchedDocumentID *uuid.UUID           `json:"matched_document_id,omitempty" db:"matched_document_id"`
+	Distance          *int                 `json:"distance,omitempty" db:"distance"`
+	Status            DuplicateMatchStatus `json:"status" db:"status"`
+	Note              *string              `json:"note,omitempty" db:"note"`
+	ReviewedBy        *uuid.UUID           `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt        *time.Time           `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt         time.Time            `json:"created_at" db:"created_at"`
+	User              DuplicateUserSummary `json:"user"`
+	MatchedUser       DuplicateUserSummary `json:"matched_user"`
+}
+
+// ResolveDuplicateMatchRequest representa la decisión de un administrador sobre una coincidencia
+type ResolveDuplicateMatchRequest struct {
+	Status DuplicateMatchStatus `json:"status" binding:"required,oneof=dismissed confirmed"`
+	Note   *string              `json:"note"`
+}
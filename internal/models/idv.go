@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IDVDecision veredicto del proveedor externo de verificación de identidad
+type IDVDecision string
+
+const (
+	IDVDecisionApproved IDVDecision = "approved"
+	IDVDecisionRejected IDVDecision = "rejected"
+	IDVDecisionReview   IDVDecision = "review" // el proveedor no pudo decidir
+)
+
+// Resultado aplicado a los documentos tras recibir el veredicto
+const (
+	IDVOutcomeAutoApproved = "auto_approved"
+	IDVOutcomeAutoRejected = "auto_rejected"
+	IDVOutcomeManualReview = "manual_review"
+)
+
+// IDVVerification representa un envío de documentos a un proveedor externo
+type IDVVerification struct {
+	ID          uuid.UUID    `json:"id" db:"id"`
+	UserID      uuid.UUID    `json:"user_id" db:"user_id"`
+	Provider    string       `json:"provider" db:"provider"`
+	ExternalID  *string      `json:"external_id,omitempty" db:"external_id"`
+	DocumentIDs []uuid.UUID  `json:"document_ids" db:"document_ids"` // Documentos enviados al proveedor
+	Status      string       `json:"status" db:"status"`             // submitted, completed, failed
+	Decision    *IDVDecision `json:"decision,omitempty" db:"decision"`
+	Confidence  *float64     `json:"confidence,omitempty" db:"confidence"`
+	ReasonCodes []string     `json:"reason_codes,omitempty" db:"reason_codes"`
+	Outcome     *string      `json:"outcome,omitempty" db:"outcome"`
+	Error       *string      `json:"error,omitempty" db:"error"`
+	SubmittedAt time.Time    `json:"submitted_at" db:"submitted_at"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty" db:"completed_at"`
+}
+
+// IDVSubmission datos enviados al proveedor para verificar la identidad de un usuario
+type IDVSubmission struct {
+	VerificationID uuid.UUID
+	UserID         uuid.UUID
+	FirstName      string
+	LastName       string
+	DocumentType   DocumentType
+	DocumentNumber string
+	Documents      []KYCDocument
+}
+
+// IDVVerdict veredicto recibido por webhook
+type IDVVerdict struct {
+	ExternalID  string
+	Decision    IDVDecision
+	Confidence  float64  // 0 a 1
+	ReasonCodes []string // Códigos del catálogo de motivos de rechazo
+	Payload     []byte   // Cuerpo original, se guarda para auditoría
+}
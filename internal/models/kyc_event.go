@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type KYCEventType string
+
+const (
+	KYCEventApproved            KYCEventType = "kyc_approved"
+	KYCEventRejected            KYCEventType = "kyc_rejected"
+	KYCEventDocumentsReceived   KYCEventType = "kyc_documents_received"
+	KYCEventDocumentUploaded    KYCEventType = "kyc_document_uploaded"
+	KYCEventDocumentRejected    KYCEventType = "kyc_document_rejected"
+	KYCEventExpiryReminder      KYCEventType = "kyc_expiry_reminder"
+	KYCEventReverification      KYCEventType = "kyc_reverification_required"
+	KYCEventDocumentQuarantined KYCEventType = "kyc_document_quarantined"
+	KYCEventLevelChanged        KYCEventType = "kyc_level_changed"
+)
+
+// KYCEvent representa un cambio relevante en el proceso KYC de un usuario
+type KYCEvent struct {
+	Type       KYCEventType `json:"type"`
+	UserID     uuid.UUID    `json:"user_id"`
+	Status     KYCStatus    `json:"kyc_status"` // En kyc_document_rejected, el estado del usuario antes del rechazo
+	DocumentID *uuid.UUID   `json:"document_id,omitempty"`
+	Reasons    []string     `json:"reasons,omitempty"` // Motivos de rechazo mostrados al usuario
+	ExpiresAt  *time.Time   `json:"expires_at,omitempty"`
+	Level      *int         `json:"kyc_level,omitempty"` // Nuevo nivel KYC (kyc_level_changed)
+	// Silent indica que el usuario no debe recibir un aviso por este evento: el cambio de nivel
+	// ya lo cubre el aviso de cambio de estado, o viene de un recálculo masivo
+	Silent bool `json:"silent,omitempty"`
+}
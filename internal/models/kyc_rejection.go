@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// DefaultLanguage es el idioma usado cuando no se indica otro
+const DefaultLanguage = "es"
+
+// RejectionReason representa un motivo del catálogo de rechazos KYC
+type RejectionReason struct {
+	Code        string            `json:"code" db:"code"`
+	Description string            `json:"description" db:"description"`
+	IsActive    bool              `json:"is_active" db:"is_active"`
+	Templates   map[string]string `json:"templates"` // idioma -> mensaje para el usuario
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// CreateRejectionReasonRequest representa la petición para crear un motivo de rechazo
+type CreateRejectionReasonRequest struct {
+	Code        string            `json:"code" binding:"required,min=2,max=50"`
+	Description string            `json:"description" binding:"required"`
+	Templates   map[string]string `json:"templates" binding:"required"`
+}
+
+// UpdateRejectionReasonRequest representa la petición para actualizar un motivo de rechazo
+type UpdateRejectionReasonRequest struct {
+	Description *string           `json:"description"`
+	IsActive    *bool             `json:"is_active"`
+	Templates   map[string]string `json:"templates"`
+}
+
+// RejectDocumentRequest representa la petición para rechazar un documento KYC
+type RejectDocumentRequest struct {
+	ReasonCodes  []string `json:"reason_codes" binding:"required,min=1"`
+	InternalNote *string  `json:"internal_note"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "github.com/google/uuid"
+
+// ApproveDocumentRequest datos opcionales capturados por el revisor al aprobar
+type ApproveDocumentRequest struct {
+	ExpiresAt *string `json:"expires_at"` // Formato YYYY-MM-DD
+}
+
+// SetDocumentExpiryRequest representa la captura del vencimiento de un documento
+type SetDocumentExpiryRequest struct {
+	ExpiresAt string `json:"expires_at" binding:"required"` // Formato YYYY-MM-DD
+}
+
+// BulkKYCRequest documentos a decidir en lote: los IDs indicados más los pendientes de los usuarios indicados
+type BulkKYCRequest struct {
+	DocumentIDs []uuid.UUID `json:"document_ids"`
+	UserIDs     []uuid.UUID `json:"user_ids"`
+}
+
+// BulkRejectRequest rechazo en lote con los mismos motivos para todos los documentos
+type BulkRejectRequest struct {
+	BulkKYCRequest
+	ReasonCodes  []string `json:"reason_codes" binding:"required,min=1"`
+	InternalNote *string  `json:"internal_note"`
+}
+
+// BulkKYCItemResult resultado de la decisión sobre un documento
+type BulkKYCItemResult struct {
+	DocumentID uuid.UUID  `json:"document_id"`
+	UserID     *uuid.UUID `json:"user_id,omitempty"`
+	Success    bool       `json:"success"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// BulkKYCResult resumen de una decisión en lote
+type BulkKYCResult struct {
+	Processed int                 `json:"processed"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Results   []BulkKYCItemResult `json:"results"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// KYCTier nivel de verificación y los documentos aprobados que exige
+type KYCTier struct {
+	Level                 int       `json:"level" db:"level"`
+	Name                  string    `json:"name" db:"name"`
+	Description           string    `json:"description" db:"description"`
+	RequiredDocumentTypes []string  `json:"required_document_types" db:"required_document_types"`
+	UpdatedAt             time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UpdateKYCTierRequest representa la edición de un nivel KYC
+type UpdateKYCTierRequest struct {
+	Name                  *string  `json:"name"`
+	Description           *string  `json:"description"`
+	RequiredDocumentTypes []string `json:"required_document_types"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KYCUploadSession carga reanudable de un documento KYC: el archivo se recibe en partes
+// y se registra como documento al completarse
+type KYCUploadSession struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	UserID       uuid.UUID  `json:"user_id" db:"user_id"`
+	DocumentType string     `json:"document_type" db:"document_type"`
+	OriginalName string     `json:"original_name" db:"original_name"`
+	MimeType     string     `json:"mime_type" db:"mime_type"`
+	Length       int64      `json:"upload_length" db:"upload_length"`
+	Offset       int64      `json:"upload_offset" db:"upload_offset"`
+	FilePath     string     `json:"-" db:"file_path"`
+	DocumentID   *uuid.UUID `json:"document_id,omitempty" db:"document_id"`
+	ExpiresAt    time.Time  `json:"expires_at" db:"expires_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
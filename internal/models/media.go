@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MediaRendition versión de una imagen en un tamaño determinado
+type MediaRendition struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// Media imagen de la biblioteca. Renditions incluye siempre "original" y los tamaños
+// menores que la imagen original ("large", "medium", "thumbnail").
+type Media struct {
+	ID           uuid.UUID                 `json:"id" db:"id"`
+	OriginalName string                    `json:"original_name" db:"original_name"`
+	MimeType     string                    `json:"mime_type" db:"mime_type"`
+	FileSize     int64                     `json:"file_size" db:"file_size"`
+	Width        int                       `json:"width" db:"width"`
+	Height       int                       `json:"height" db:"height"`
+	AltText      *string                   `json:"alt_text" db:"alt_text"`
+	Renditions   map[string]MediaRendition `json:"renditions" db:"renditions"`
+	UploadedBy   *uuid.UUID                `json:"uploaded_by" db:"uploaded_by"`
+	CreatedAt    time.Time                 `json:"created_at" db:"created_at"`
+}
@@ -8,39 +8,99 @@ import (
 
 // MarketNews representa una noticia del mercado
 type MarketNews struct {
-	ID          uuid.UUID  `json:"id" db:"id"`
-	Title       string     `json:"title" db:"title"`
-	Content     string     `json:"content" db:"content"`
-	Summary     *string    `json:"summary" db:"summary"`
-	ImageURL    *string    `json:"image_url" db:"image_url"`
-	Category    string     `json:"category" db:"category"`
-	Priority    int        `json:"priority" db:"priority"`
-	IsActive    bool       `json:"is_active" db:"is_active"`
-	PublishedAt time.Time  `json:"published_at" db:"published_at"`
-	CreatedBy   *uuid.UUID `json:"created_by" db:"created_by"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	ID               uuid.UUID                 `json:"id" db:"id"`
+	Title            string                    `json:"title" db:"title"`
+	Content          string                    `json:"content" db:"content"`           // Fuente en Markdown
+	ContentHTML      string                    `json:"content_html" db:"content_html"` // HTML renderizado y sanitizado
+	Summary          *string                   `json:"summary" db:"summary"`
+	SummaryGenerated bool                      `json:"summary_generated" db:"summary_generated"`
+	ImageURL         *string                   `json:"image_url" db:"image_url"`
+	ImageMediaID     *uuid.UUID                `json:"image_media_id" db:"image_media_id"`
+	ImageRenditions  map[string]MediaRendition `json:"image_renditions,omitempty"` // Versiones de la imagen de la biblioteca
+	Category         string                    `json:"category" db:"category"`
+	Tags             []string                  `json:"tags" db:"tags"`
+	Priority         int                       `json:"priority" db:"priority"`
+	IsActive         bool                      `json:"is_active" db:"is_active"`
+	Status           NewsStatus                `json:"status" db:"status"`
+	ReviewedBy       *uuid.UUID                `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedAt       *time.Time                `json:"reviewed_at" db:"reviewed_at"`
+	ReviewNote       *string                   `json:"review_note" db:"review_note"`
+	PublishedAt      time.Time                 `json:"published_at" db:"published_at"`
+	ExpireAt         *time.Time                `json:"expire_at" db:"expire_at"`
+	SourceID         *uuid.UUID                `json:"source_id" db:"source_id"`   // Fuente externa si fue importada
+	SourceURL        *string                   `json:"source_url" db:"source_url"` // Artículo original
+	CreatedBy        *uuid.UUID                `json:"created_by" db:"created_by"`
+	CreatedAt        time.Time                 `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time                 `json:"updated_at" db:"updated_at"`
+	IsRead           *bool                     `json:"is_read,omitempty"`       // Solo en las respuestas a usuarios
+	IsBookmarked     *bool                     `json:"is_bookmarked,omitempty"` // Solo en las respuestas a usuarios
+	Reactions        map[NewsReaction]int      `json:"reactions,omitempty"`     // Cantidad por tipo de reacción
+	UserReaction     *NewsReaction             `json:"user_reaction,omitempty"` // Reacción del usuario actual
+	CommentCount     *int                      `json:"comment_count,omitempty"`
+	Language         string                    `json:"language,omitempty"`            // Idioma del contenido devuelto a usuarios
+	Languages        []string                  `json:"available_languages,omitempty"` // Idioma original y traducciones
+}
+
+// IsPublished indica si la noticia está publicada, activa y dentro de su ventana de publicación
+func (n *MarketNews) IsPublished(now time.Time) bool {
+	return n.Status == NewsStatusPublished && n.IsActive && !n.PublishedAt.After(now) && (n.ExpireAt == nil || n.ExpireAt.After(now))
+}
+
+// NewsFilter filtros del listado de noticias
+type NewsFilter struct {
+	Category   string
+	Tag        string // Slug de etiqueta
+	Search     string
+	Status     NewsStatus
+	ActiveOnly bool
+}
+
+// NewsSearchResult noticia encontrada por búsqueda de texto completo con su relevancia
+// y fragmentos resaltados (términos entre <mark></mark>)
+type NewsSearchResult struct {
+	MarketNews
+	Rank           float64 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
 }
 
 // CreateNewsRequest representa la petición para crear una noticia
 type CreateNewsRequest struct {
 	Title    string  `json:"title" binding:"required" validate:"min=3,max=255"`
-	Content  string  `json:"content" binding:"required" validate:"min=10"`
-	Summary  *string `json:"summary" validate:"max=500"`
-	ImageURL *string `json:"image_url" validate:"url"`
-	Category string  `json:"category" validate:"oneof=general markets crypto analysis regulation"`
+	Content  string  `json:"content" binding:"required" validate:"min=10"` // Markdown
+	Summary  *string `json:"summary" validate:"max=500"`                   // Si se omite se genera a partir del contenido
+	ImageURL *string `json:"image_url" validate:"url"`                     // Obsoleto: usar image_media_id
+	Category string  `json:"category"`                                     // Slug de una categoría activa
 	Priority int     `json:"priority" validate:"min=1,max=3"`
+
+	// Etiquetas libres; las que no existen se crean
+	Tags []string `json:"tags"`
+
+	// Imagen de la biblioteca de medios; tiene prioridad sobre image_url
+	ImageMediaID *uuid.UUID `json:"image_media_id"`
+
+	// Ventana de publicación: sin publish_at se publica de inmediato, sin expire_at no expira
+	PublishAt *time.Time `json:"publish_at"`
+	ExpireAt  *time.Time `json:"expire_at"`
 }
 
 // UpdateNewsRequest representa la petición para actualizar una noticia
 type UpdateNewsRequest struct {
-	Title    *string `json:"title" validate:"min=3,max=255"`
-	Content  *string `json:"content" validate:"min=10"`
-	Summary  *string `json:"summary" validate:"max=500"`
-	ImageURL *string `json:"image_url" validate:"url"`
-	Category *string `json:"category" validate:"oneof=general markets crypto analysis regulation"`
-	Priority *int    `json:"priority" validate:"min=1,max=3"`
-	IsActive *bool   `json:"is_active"`
+	Title    *string  `json:"title" validate:"min=3,max=255"`
+	Content  *string  `json:"content" validate:"min=10"`
+	Summary  *string  `json:"summary" validate:"max=500"`
+	ImageURL *string  `json:"image_url" validate:"url"` // Obsoleto: usar image_media_id
+	Category *string  `json:"category"`
+	Priority *int     `json:"priority" validate:"min=1,max=3"`
+	IsActive *bool    `json:"is_active"`
+	Tags     []string `json:"tags"` // Reemplaza las etiquetas; omitido no las cambia y [] las quita
+
+	ImageMediaID *uuid.UUID `json:"image_media_id"`
+	ClearImage   bool       `json:"clear_image"` // Quita la imagen
+
+	PublishAt     *time.Time `json:"publish_at"`
+	ExpireAt      *time.Time `json:"expire_at"`
+	ClearExpireAt bool       `json:"clear_expire_at"` // Quita la expiración
 }
 
 // Notification representa una notificación del sistema
@@ -82,6 +142,14 @@ type NotificationStats struct {
 type NewsStats struct {
 	TotalNews      int            `json:"total_news"`
 	ActiveNews     int            `json:"active_news"`
+	ScheduledNews  int            `json:"scheduled_news"`
 	TodayNews      int            `json:"today_news"`
 	NewsByCategory map[string]int `json:"news_by_category"`
+
+	// Audiencia: totales y noticias más vistas
+	TotalViews     int                `json:"total_views"`
+	UniqueReaders  int                `json:"unique_readers"`
+	TotalReads     int                `json:"total_reads"`
+	TotalBookmarks int                `json:"total_bookmarks"`
+	MostViewedNews []NewsArticleStats `json:"most_viewed_news"`
 }
@@ -0,0 +1,23 @@
+package models
+
+import "github.com/google/uuid"
+
+// NewsArticleStats visitas y lecturas de una noticia. ReadThroughRate es la proporción (0-1)
+// de lectores únicos que la leyeron completa.
+type NewsArticleStats struct {
+	NewsID          uuid.UUID `json:"news_id"`
+	Title           string    `json:"title"`
+	TotalViews      int       `json:"total_views"`
+	UniqueViews     int       `json:"unique_views"`
+	Reads           int       `json:"reads"`
+	Bookmarks       int       `json:"bookmarks"`
+	ReadThroughRate float64   `json:"read_through_rate"`
+}
+
+// NewsTimeSeriesPoint visitas y lecturas de un día (fecha en formato YYYY-MM-DD)
+type NewsTimeSeriesPoint struct {
+	Date        string `json:"date"`
+	Views       int    `json:"views"`
+	UniqueViews int    `json:"unique_views"`
+	Reads       int    `json:"reads"`
+}
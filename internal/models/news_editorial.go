@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NewsStatus estado editorial de una noticia
+type NewsStatus string
+
+const (
+	NewsStatusDraft     NewsStatus = "draft"
+	NewsStatusInReview  NewsStatus = "in_review"
+	NewsStatusPublished NewsStatus = "published"
+	NewsStatusArchived  NewsStatus = "archived"
+)
+
+// ChangeNewsStatusRequest representa un cambio de estado editorial (enviar a revisión, aprobar, devolver, archivar)
+type ChangeNewsStatusRequest struct {
+	Status NewsStatus `json:"status" binding:"required,oneof=draft in_review published archived"`
+	Note   *string    `json:"note"` // Comentario del revisor, ej: al devolver a borrador
+}
+
+// NewsRevision versión guardada del contenido de una noticia
+type NewsRevision struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	NewsID       uuid.UUID  `json:"news_id" db:"news_id"`
+	Revision     int        `json:"revision" db:"revision"`
+	Title        string     `json:"title" db:"title"`
+	Content      string     `json:"content" db:"content"`
+	Summary      *string    `json:"summary" db:"summary"`
+	ImageURL     *string    `json:"image_url" db:"image_url"`
+	ImageMediaID *uuid.UUID `json:"image_media_id" db:"image_media_id"`
+	Category     string     `json:"category" db:"category"`
+	Priority     int        `json:"priority" db:"priority"`
+	EditedBy     *uuid.UUID `json:"edited_by" db:"edited_by"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// NewsFieldChange cambio de un campo entre dos versiones
+type NewsFieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// DiffLine línea de un diff de texto: equal, insert o delete
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// NewsRevisionDiff diferencias entre dos versiones de una noticia
+type NewsRevisionDiff struct {
+	NewsID      uuid.UUID         `json:"news_id"`
+	From        int               `json:"from"`
+	To          int               `json:"to"`
+	Changes     []NewsFieldChange `json:"changes"`
+	ContentDiff []DiffLine        `json:"content_diff"`
+}
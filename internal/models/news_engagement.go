@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NewsReaction reacción de un usuario a una noticia
+type NewsReaction string
+
+const (
+	NewsReactionLike    NewsReaction = "like"
+	NewsReactionBullish NewsReaction = "bullish"
+	NewsReactionBearish NewsReaction = "bearish"
+)
+
+// NewsReactions tipos de reacción disponibles
+var NewsReactions = []NewsReaction{NewsReactionLike, NewsReactionBullish, NewsReactionBearish}
+
+// SetNewsReactionRequest representa la reacción de un usuario; reemplaza la anterior
+type SetNewsReactionRequest struct {
+	Reaction NewsReaction `json:"reaction" binding:"required,oneof=like bullish bearish"`
+}
+
+// NewsCommentStatus estado de moderación de un comentario
+type NewsCommentStatus string
+
+const (
+	NewsCommentStatusPending  NewsCommentStatus = "pending"
+	NewsCommentStatusApproved NewsCommentStatus = "approved"
+	NewsCommentStatusRejected NewsCommentStatus = "rejected"
+	NewsCommentStatusDeleted  NewsCommentStatus = "deleted" // Eliminado por su autor; se conserva para no romper el hilo
+)
+
+// NewsComment comentario de una noticia. Replies solo se completa en el listado en hilos.
+type NewsComment struct {
+	ID             uuid.UUID         `json:"id" db:"id"`
+	NewsID         uuid.UUID         `json:"news_id" db:"news_id"`
+	UserID         uuid.UUID         `json:"user_id" db:"user_id"`
+	AuthorName     string            `json:"author_name"`
+	ParentID       *uuid.UUID        `json:"parent_id" db:"parent_id"`
+	ThreadID       uuid.UUID         `json:"thread_id" db:"thread_id"`
+	Depth          int               `json:"depth" db:"depth"`
+	Content        string            `json:"content" db:"content"`
+	Status         NewsCommentStatus `json:"status" db:"status"`
+	FlagReasons    []string          `json:"flag_reasons,omitempty" db:"flag_reasons"` // Motivos por los que el filtro lo envió a moderación
+	ModeratedBy    *uuid.UUID        `json:"moderated_by,omitempty" db:"moderated_by"`
+	ModeratedAt    *time.Time        `json:"moderated_at,omitempty" db:"moderated_at"`
+	ModerationNote *string           `json:"moderation_note,omitempty" db:"moderation_note"`
+	CreatedAt      time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at" db:"updated_at"`
+	Replies        []NewsComment     `json:"replies,omitempty"`
+}
+
+// CreateNewsCommentRequest representa un comentario nuevo o una respuesta (parent_id)
+type CreateNewsCommentRequest struct {
+	Content  string     `json:"content" binding:"required"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+// ModerateNewsCommentRequest representa la decisión de un admin sobre un comentario
+type ModerateNewsCommentRequest struct {
+	Status NewsCommentStatus `json:"status" binding:"required,oneof=approved rejected"`
+	Note   *string           `json:"note"`
+}
+
+// NewsMutedUser usuario que no puede comentar mientras el silencio esté vigente
+type NewsMutedUser struct {
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	UserName   string     `json:"user_name"`
+	Email      string     `json:"email"`
+	Reason     *string    `json:"reason" db:"reason"`
+	MutedUntil *time.Time `json:"muted_until" db:"muted_until"` // nil = indefinido
+	MutedBy    *uuid.UUID `json:"muted_by" db:"muted_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// MuteUserRequest representa el silencio de un usuario; sin duración es indefinido
+type MuteUserRequest struct {
+	Reason        *string `json:"reason"`
+	DurationHours *int    `json:"duration_hours" binding:"omitempty,min=1"`
+}
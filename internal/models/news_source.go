@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NewsSource fuente externa RSS/Atom desde la que se importan noticias como borradores.
+// CategoryMap traduce las categorías de la fuente (en minúsculas) a las nuestras; si ninguna
+// coincide se usa Category.
+type NewsSource struct {
+	ID           uuid.UUID         `json:"id" db:"id"`
+	Name         string            `json:"name" db:"name"`
+	FeedURL      string            `json:"feed_url" db:"feed_url"`
+	Category     string            `json:"category" db:"category"`
+	CategoryMap  map[string]string `json:"category_map" db:"category_map"`
+	IsActive     bool              `json:"is_active" db:"is_active"`
+	ETag         *string           `json:"-" db:"etag"`
+	LastModified *string           `json:"-" db:"last_modified"`
+	LastPolledAt *time.Time        `json:"last_polled_at" db:"last_polled_at"`
+	LastError    *string           `json:"last_error" db:"last_error"`
+	CreatedBy    *uuid.UUID        `json:"created_by" db:"created_by"`
+	CreatedAt    time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// CreateNewsSourceRequest representa la petición para registrar una fuente
+type CreateNewsSourceRequest struct {
+	Name        string            `json:"name" binding:"required"`
+	FeedURL     string            `json:"feed_url" binding:"required,url"`
+	Category    string            `json:"category"`
+	CategoryMap map[string]string `json:"category_map"`
+}
+
+// UpdateNewsSourceRequest representa la edición de una fuente
+type UpdateNewsSourceRequest struct {
+	Name        *string           `json:"name"`
+	FeedURL     *string           `json:"feed_url" binding:"omitempty,url"`
+	Category    *string           `json:"category"`
+	CategoryMap map[string]string `json:"category_map"`
+	IsActive    *bool             `json:"is_active"`
+}
+
+// NewsIngestionResult resultado de consultar una fuente
+type NewsIngestionResult struct {
+	SourceID    uuid.UUID `json:"source_id"`
+	NotModified bool      `json:"not_modified"` // La fuente respondió 304
+	Fetched     int       `json:"fetched"`
+	Imported    int       `json:"imported"`
+	Duplicates  int       `json:"duplicates"`
+	Failed      int       `json:"failed"` // Artículos que no se pudieron importar; se reintentan cuando el feed cambie
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NewsCategory categoría de noticias administrable. Las inactivas se conservan en las noticias
+// existentes pero no se pueden asignar a noticias nuevas.
+type NewsCategory struct {
+	Slug        string    `json:"slug" db:"slug"`
+	Name        string    `json:"name" db:"name"`
+	Description *string   `json:"description" db:"description"`
+	SortOrder   int       `json:"sort_order" db:"sort_order"`
+	IsActive    bool      `json:"is_active" db:"is_active"`
+	NewsCount   int       `json:"news_count" db:"news_count"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateNewsCategoryRequest representa la petición para crear una categoría
+type CreateNewsCategoryRequest struct {
+	Slug        string  `json:"slug" binding:"required"`
+	Name        string  `json:"name" binding:"required"`
+	Description *string `json:"description"`
+	SortOrder   int     `json:"sort_order"`
+}
+
+// UpdateNewsCategoryRequest representa la edición de una categoría; el slug no cambia
+type UpdateNewsCategoryRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	SortOrder   *int    `json:"sort_order"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// NewsTag etiqueta libre de noticias; se crea al usarse por primera vez
+type NewsTag struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Slug      string    `json:"slug" db:"slug"`
+	NewsCount int       `json:"news_count" db:"news_count"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// NewsCategorySubscription suscripción de un usuario a las alertas de una categoría
+type NewsCategorySubscription struct {
+	Category     string    `json:"category" db:"category"`
+	CategoryName string    `json:"category_name"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NewsTranslation versión de una noticia en otro idioma
+type NewsTranslation struct {
+	NewsID           uuid.UUID  `json:"news_id" db:"news_id"`
+	Language         string     `json:"language" db:"language"`
+	Title            string     `json:"title" db:"title"`
+	Summary          *string    `json:"summary" db:"summary"`
+	SummaryGenerated bool       `json:"summary_generated" db:"summary_generated"`
+	Content          string     `json:"content" db:"content"`           // Fuente en Markdown
+	ContentHTML      string     `json:"content_html" db:"content_html"` // HTML renderizado y sanitizado
+	UpdatedBy        *uuid.UUID `json:"updated_by" db:"updated_by"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// NewsTranslationRevision versión guardada de una traducción
+type NewsTranslationRevision struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	NewsID    uuid.UUID  `json:"news_id" db:"news_id"`
+	Language  string     `json:"language" db:"language"`
+	Revision  int        `json:"revision" db:"revision"`
+	Title     string     `json:"title" db:"title"`
+	Summary   *string    `json:"summary" db:"summary"`
+	Content   string     `json:"content" db:"content"`
+	Deleted   bool       `json:"deleted" db:"deleted"` // La traducción se eliminó en esta versión
+	EditedBy  *uuid.UUID `json:"edited_by" db:"edited_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// UpsertNewsTranslationRequest representa la traducción de una noticia a un idioma; sin resumen
+// se genera a partir del contenido
+type UpsertNewsTranslationRequest struct {
+	Title   string  `json:"title" binding:"required,max=255"`
+	Summary *string `json:"summary"`
+	Content string  `json:"content" binding:"required"`
+}
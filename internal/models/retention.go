@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RetentionPolicy categoría de retención que aplica a los archivos KYC de un usuario
+type RetentionPolicy string
+
+const (
+	RetentionPolicyRejected  RetentionPolicy = "rejected"  // verificación rechazada
+	RetentionPolicyAbandoned RetentionPolicy = "abandoned" // verificación iniciada y nunca completada
+	RetentionPolicyClosed    RetentionPolicy = "closed"    // cuenta cerrada
+)
+
+// RetentionCandidate representa un documento cuyo archivo debe purgarse
+type RetentionCandidate struct {
+	DocumentID     uuid.UUID       `json:"document_id"`
+	UserID         uuid.UUID       `json:"user_id"`
+	DocumentType   string          `json:"document_type"`
+	DocumentStatus KYCStatus       `json:"document_status"`
+	Policy         RetentionPolicy `json:"policy"`
+	FileSize       int64           `json:"file_size"`
+	UploadedAt     time.Time       `json:"uploaded_at"`
+	LastActivityAt time.Time       `json:"last_activity_at"`
+	FilePath       string          `json:"-"`
+}
+
+// RetentionReport resume los archivos purgados (o que se purgarían en modo simulación)
+type RetentionReport struct {
+	DryRun      bool                    `json:"dry_run"`
+	GeneratedAt time.Time               `json:"generated_at"`
+	RetainDays  map[RetentionPolicy]int `json:"retain_days"`
+	ByPolicy    map[RetentionPolicy]int `json:"by_policy"`
+	TotalFiles  int                     `json:"total_files"`
+	TotalBytes  int64                   `json:"total_bytes"`
+	Documents   []RetentionCandidate    `json:"documents"`
+	Failures    []RetentionFailure      `json:"failures,omitempty"` // Documentos que no se pudieron purgar
+}
+
+// RetentionFailure documento que no se pudo purgar; se reintenta en la siguiente ejecución
+type RetentionFailure struct {
+	DocumentID uuid.UUID `json:"document_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	Error      string    `json:"error"`
+}
+
+// PurgeTombstone constancia de un archivo KYC eliminado por retención
+type PurgeTombstone struct {
+	ID             uuid.UUID       `json:"id" db:"id"`
+	DocumentID     uuid.UUID       `json:"document_id" db:"document_id"`
+	UserID         uuid.UUID       `json:"user_id" db:"user_id"`
+	DocumentType   string          `json:"document_type" db:"document_type"`
+	DocumentStatus KYCStatus       `json:"document_status" db:"document_status"`
+	Policy         RetentionPolicy `json:"policy" db:"policy"`
+	FileSize       int64           `json:"file_size" db:"file_size"`
+	FileSHA256     *string         `json:"file_sha256,omitempty" db:"file_sha256"`
+	UploadedAt     time.Time       `json:"uploaded_at" db:"uploaded_at"`
+	LastActivityAt time.Time       `json:"last_activity_at" db:"last_activity_at"`
+	PurgedAt       time.Time       `json:"purged_at" db:"purged_at"`
+}
+
+// SetLegalHoldRequest activa o levanta la retención legal de un usuario
+type SetLegalHoldRequest struct {
+	LegalHold *bool  `json:"legal_hold" binding:"required"`
+	Reason    string `json:"reason"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RiskLevel string
+
+const (
+	RiskLevelLow    RiskLevel = "low"
+	RiskLevelMedium RiskLevel = "medium"
+	RiskLevelHigh   RiskLevel = "high"
+)
+
+// RiskFactorWeight representa el peso configurable de un factor de riesgo
+type RiskFactorWeight struct {
+	Factor      string    `json:"factor" db:"factor"`
+	Weight      int       `json:"weight" db:"weight"`
+	Description string    `json:"description" db:"description"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// RiskFactor representa la contribución de un factor a un puntaje
+type RiskFactor struct {
+	Factor      string `json:"factor"`
+	Occurrences int    `json:"occurrences"`
+	Points      int    `json:"points"`
+}
+
+// RiskAssessment representa un cálculo de riesgo de un usuario
+type RiskAssessment struct {
+	ID        uuid.UUID    `json:"id" db:"id"`
+	UserID    uuid.UUID    `json:"user_id" db:"user_id"`
+	Score     int          `json:"score" db:"score"`
+	Level     RiskLevel    `json:"level" db:"level"`
+	Factors   []RiskFactor `json:"factors" db:"factors"`
+	Trigger   string       `json:"trigger" db:"trigger"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+}
+
+// UpdateRiskWeightsRequest representa la petición para cambiar pesos de factores
+type UpdateRiskWeightsRequest struct {
+	Weights map[string]int `json:"weights" binding:"required"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ScreeningHitStatus string
+
+const (
+	ScreeningHitPending   ScreeningHitStatus = "pending"
+	ScreeningHitCleared   ScreeningHitStatus = "cleared"
+	ScreeningHitConfirmed ScreeningHitStatus = "confirmed"
+	// ScreeningHitSuperseded coincidencia pendiente cuya entrada fue retirada de la lista
+	ScreeningHitSuperseded ScreeningHitStatus = "superseded"
+)
+
+// Watchlist representa una lista de sanciones o PEP importada
+type Watchlist struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	Name         string     `json:"name" db:"name"`
+	SourceFormat string     `json:"source_format" db:"source_format"`
+	ListType     string     `json:"list_type" db:"list_type"`
+	EntryCount   int        `json:"entry_count" db:"entry_count"`
+	ImportedBy   *uuid.UUID `json:"imported_by" db:"imported_by"`
+	ImportedAt   time.Time  `json:"imported_at" db:"imported_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// WatchlistEntry representa una persona o entidad de una lista
+type WatchlistEntry struct {
+	ExternalID      string   `json:"external_id"`
+	EntryType       string   `json:"entry_type"`
+	Name            string   `json:"name"`
+	Aliases         []string `json:"aliases"`
+	DocumentNumbers []string `json:"document_numbers"`
+	Country         string   `json:"country"`
+	Program         string   `json:"program"`
+}
+
+// ScreeningHit representa una coincidencia de un usuario contra una lista
+type ScreeningHit struct {
+	ID              uuid.UUID          `json:"id" db:"id"`
+	UserID          uuid.UUID          `json:"user_id" db:"user_id"`
+	UserName        string             `json:"user_name"`
+	UserEmail       string             `json:"user_email"`
+	WatchlistID     uuid.UUID          `json:"watchlist_id" db:"watchlist_id"`
+	WatchlistName   string             `json:"watchlist_name"`
+	ListType        string             `json:"list_type"`
+	EntryExternalID *string            `json:"entry_external_id" db:"entry_external_id"`
+	MatchedName     string             `json:"matched_name" db:"matched_name"`
+	MatchField      string             `json:"match_field" db:"match_field"`
+	Score           float64            `json:"score" db:"score"`
+	Status          ScreeningHitStatus `json:"status" db:"status"`
+	Note            *string            `json:"note,omitempty" db:"note"`
+	ReviewedBy      *uuid.UUID         `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt      *time.Time         `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt       time.Time          `json:"created_at" db:"created_at"`
+}
+
+// ResolveScreeningHitRequest representa la adjudicación de una coincidencia
+type ResolveScreeningHitRequest struct {
+	Status ScreeningHitStatus `json:"status" binding:"required,oneof=cleared confirmed"`
+	Note   *string            `json:"note"`
+}
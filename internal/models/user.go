@@ -19,6 +19,12 @@ const (
 	KYCStatusPending  KYCStatus = "pending"
 	KYCStatusApproved KYCStatus = "approved"
 	KYCStatusRejected KYCStatus = "rejected"
+	// Estado de usuario: debe volver a verificar su identidad
+	KYCStatusReverificationRequired KYCStatus = "reverification_required"
+	// Estado de documento: vencido o pendiente de renovación
+	KYCStatusExpired KYCStatus = "expired"
+	// Estado de documento: el antivirus detectó malware, el archivo está aislado
+	KYCStatusQuarantined KYCStatus = "quarantined"
 )
 
 type UserRole string
@@ -44,23 +50,35 @@ type User struct {
 	PasswordHash     string       `json:"-" db:"password_hash"`
 	Role             UserRole     `json:"role" db:"role"`
 	KYCStatus        KYCStatus    `json:"kyc_status" db:"kyc_status"`
+	KYCLevel         int          `json:"kyc_level" db:"kyc_level"`
 	EmailVerified    bool         `json:"email_verified" db:"email_verified"`
+	RiskScore        *int         `json:"risk_score,omitempty" db:"risk_score"`
+	RiskLevel        *RiskLevel   `json:"risk_level,omitempty" db:"risk_level"`
+	KYCVerifiedAt    *time.Time   `json:"kyc_verified_at,omitempty" db:"kyc_verified_at"`
+	LegalHold        bool         `json:"legal_hold" db:"legal_hold"`
+	ClosedAt         *time.Time   `json:"closed_at,omitempty" db:"closed_at"`
+	Locale           *string      `json:"locale" db:"locale"` // Idioma preferido para el contenido; nil = según Accept-Language
 	CreatedAt        time.Time    `json:"created_at" db:"created_at"`
 	UpdatedAt        time.Time    `json:"updated_at" db:"updated_at"`
 }
 
 type KYCDocument struct {
-	ID              uuid.UUID `json:"id" db:"id"`
-	UserID          uuid.UUID `json:"user_id" db:"user_id"`
-	DocumentType    string    `json:"document_type" db:"document_type"` // "cedula_front", "cedula_back", "face_photo"
-	FilePath        string    `json:"file_path" db:"file_path"`
-	OriginalName    string    `json:"original_name" db:"original_name"`
-	FileSize        int64     `json:"file_size" db:"file_size"`
-	MimeType        string    `json:"mime_type" db:"mime_type"`
-	Status          KYCStatus `json:"status" db:"status"`
-	RejectionReason *string   `json:"rejection_reason,omitempty" db:"rejection_reason"`
-	CreatedAt       time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	ID              uuid.UUID  `json:"id" db:"id"`
+	UserID          uuid.UUID  `json:"user_id" db:"user_id"`
+	DocumentType    string     `json:"document_type" db:"document_type"` // "cedula_front", "cedula_back", "face_photo"
+	FilePath        string     `json:"file_path" db:"file_path"`
+	OriginalName    string     `json:"original_name" db:"original_name"`
+	FileSize        int64      `json:"file_size" db:"file_size"`
+	MimeType        string     `json:"mime_type" db:"mime_type"`
+	Status          KYCStatus  `json:"status" db:"status"`
+	RejectionReason *string    `json:"rejection_reason,omitempty" db:"rejection_reason"`
+	RejectionCodes  []string   `json:"rejection_codes,omitempty" db:"rejection_codes"`
+	RejectionNote   *string    `json:"rejection_note,omitempty" db:"rejection_note"` // Solo visible para administradores
+	ExpiresAt       *time.Time `json:"expires_at,omitempty" db:"expires_at"`         // Vencimiento capturado por el revisor
+	PurgedAt        *time.Time `json:"purged_at,omitempty" db:"purged_at"`           // Archivo eliminado por retención
+	ScanSignature   *string    `json:"scan_signature,omitempty" db:"scan_signature"` // Firma de malware detectada
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 type UserRegistrationRequest struct {
@@ -83,6 +101,11 @@ type UserLoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// UpdateLocaleRequest representa el idioma preferido del usuario; null o vacío vuelve a usar Accept-Language
+type UpdateLocaleRequest struct {
+	Locale *string `json:"locale"`
+}
+
 type LoginResponse struct {
 	Token     string    `json:"token"`
 	User      User      `json:"user"`
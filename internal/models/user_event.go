@@ -0,0 +1,16 @@
+package models
+
+import "github.com/google/uuid"
+
+type UserEventType string
+
+const (
+	UserEventRegistered  UserEventType = "user_registered"
+	UserEventLoginFailed UserEventType = "user_login_failed"
+)
+
+// UserEvent representa un evento relevante en la cuenta de un usuario
+type UserEvent struct {
+	Type   UserEventType `json:"type"`
+	UserID uuid.UUID     `json:"user_id"`
+}
@@ -2,8 +2,12 @@ package routes
 
 import (
 	"database/sql"
+	"log"
+	"tradeoptix-back/internal/config"
 	"tradeoptix-back/internal/handlers"
 	"tradeoptix-back/internal/middleware"
+	"tradeoptix-back/internal/models"
+	"tradeoptix-back/internal/scheduler"
 	"tradeoptix-back/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -11,7 +15,7 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRoutes(router *gin.Engine, db *sql.DB) {
+func SetupRoutes(router *gin.Engine, db *sql.DB, cfg *config.Config) {
 	// Deshabilitar redirects automáticos de Gin
 	router.RedirectTrailingSlash = false
 	router.RedirectFixedPath = false
@@ -24,6 +28,9 @@ func SetupRoutes(router *gin.Engine, db *sql.DB) {
 	// Servir archivos estáticos de uploads para administradores
 	router.Static("/uploads", "./uploads")
 
+	// Imágenes de la biblioteca de medios (públicas, usadas por las noticias)
+	router.Static("/media", cfg.MediaDir)
+
 	// Configurar JWT secret (debe venir de config)
 	jwtSecret := "your-super-secret-key-change-in-production"
 
@@ -31,14 +38,119 @@ func SetupRoutes(router *gin.Engine, db *sql.DB) {
 	userService := services.NewUserService(db, jwtSecret)
 	kycService := services.NewKYCService(db, "uploads")
 	newsService := services.NewNewsService(db)
+	newsService.SetBlockedWords(cfg.NewsCommentBlockedWords)
+	mediaService := services.NewMediaService(db, cfg.MediaDir, cfg.MediaBaseURL)
+	newsIngestionService := services.NewNewsIngestionService(db, cfg.NewsIngestionTimeout)
 	notificationService := services.NewNotificationService(db)
+	newsNotifier := services.NewNewsNotifier(db, notificationService, services.NewsAlertPolicy{
+		MinPriority:    cfg.NewsAlertMinPriority,
+		Categories:     cfg.NewsAlertCategories,
+		MaxPerUser:     cfg.NewsAlertMaxPerUser,
+		ThrottleWindow: cfg.NewsAlertThrottleWindow,
+		DeepLinkBase:   cfg.NewsAlertDeepLinkBase,
+	})
+	duplicateService := services.NewDuplicateService(db)
+	screeningService := services.NewScreeningService(db, cfg.ScreeningMatchThreshold)
+	riskService := services.NewRiskService(db, cfg.RiskHighRiskCountries, cfg.RiskMediumThreshold, cfg.RiskHighThreshold)
+	retentionService := services.NewRetentionService(db, map[models.RetentionPolicy]int{
+		models.RetentionPolicyRejected:  cfg.RetentionRejectedDays,
+		models.RetentionPolicyAbandoned: cfg.RetentionAbandonedDays,
+		models.RetentionPolicyClosed:    cfg.RetentionClosedDays,
+	})
+
+	// Analizar los archivos subidos con clamd si está configurado
+	if cfg.ClamdAddress != "" {
+		kycService.SetScanner(services.NewClamdScanner(cfg.ClamdAddress, cfg.ClamdTimeout), cfg.QuarantineDir)
+	}
+
+	// Cargas reanudables: los archivos parciales quedan fuera del directorio público de uploads
+	kycService.SetUploadSessions(cfg.KYCUploadPartialDir, cfg.KYCUploadSessionTTL)
+
+	// Notificar al usuario los cambios de estado KYC
+	kycService.AddEventListener(services.NewKYCNotifier(notificationService))
+
+	// Detectar identidades duplicadas al registrarse y al subir documentos
+	userService.AddEventListener(duplicateService)
+	kycService.AddEventListener(duplicateService)
+
+	// Screening de sanciones/PEP al registrarse; bloquea la aprobación KYC con coincidencias abiertas
+	userService.AddEventListener(screeningService)
+	kycService.AddApprovalGuard(screeningService)
+
+	// Recalcular el riesgo (después de la detección de duplicados, que alimenta el puntaje)
+	userService.AddEventListener(riskService)
+	kycService.AddEventListener(riskService)
+	duplicateService.AddResolutionListener(riskService)
+	screeningService.AddResolutionListener(riskService)
+
+	// Verificación de identidad externa al completar la carga de documentos
+	var idvProvider services.VerificationProvider
+	switch cfg.IDVProvider {
+	case "fake":
+		idvProvider = services.NewFakeVerificationProvider(cfg.IDVWebhookSecret, cfg.IDVFakeCallbackURL,
+			models.IDVDecision(cfg.IDVFakeDecision), cfg.IDVFakeConfidence)
+	case "":
+	default:
+		log.Printf("Advertencia: proveedor IDV desconocido %q, verificación externa deshabilitada", cfg.IDVProvider)
+	}
+	idvService := services.NewIdentityVerificationService(db, kycService, idvProvider,
+		cfg.IDVAutoApproveConfidence, cfg.IDVAutoRejectConfidence)
+	if idvProvider != nil {
+		kycService.AddEventListener(idvService)
+	}
+
+	// Vencimiento de documentos y re-verificación periódica según riesgo
+	kycService.SetReverificationPolicy(services.ReverificationPolicy{
+		LeadDays:        cfg.KYCExpiryLeadDays,
+		ReminderOffsets: cfg.KYCExpiryReminderDays,
+		CadenceMonths: map[models.RiskLevel]int{
+			models.RiskLevelLow:    cfg.ReKYCMonthsLow,
+			models.RiskLevelMedium: cfg.ReKYCMonthsMedium,
+			models.RiskLevelHigh:   cfg.ReKYCMonthsHigh,
+		},
+	})
+	scheduler.Start(
+		scheduler.Job{
+			Name:     "kyc_reverification",
+			Interval: cfg.KYCReverificationInterval,
+			Run:      kycService.RunReverificationCheck,
+		},
+		scheduler.Job{
+			Name:     "kyc_upload_cleanup",
+			Interval: cfg.KYCUploadCleanupInterval,
+			Run:      kycService.RunUploadSessionCleanup,
+		},
+		scheduler.Job{
+			Name:     "kyc_retention_purge",
+			Interval: cfg.RetentionPurgeInterval,
+			Run:      retentionService.RunPurge,
+		},
+		scheduler.Job{
+			Name:     "news_ingestion",
+			Interval: cfg.NewsIngestionInterval,
+			Run:      newsIngestionService.RunIngestion,
+		},
+		scheduler.Job{
+			Name:     "news_alerts",
+			Interval: cfg.NewsAlertInterval,
+			Run:      newsNotifier.RunNewsAlerts,
+		},
+	)
 
 	// Inicializar handlers
 	userHandler := handlers.NewUserHandler(userService)
-	kycHandler := handlers.NewKYCHandler(kycService)
+	kycHandler := handlers.NewKYCHandler(kycService, userService)
 	adminHandler := handlers.NewAdminHandler(userService, kycService)
-	newsHandler := handlers.NewNewsHandler(newsService)
+	newsHandler := handlers.NewNewsHandler(newsService, userService)
+	mediaHandler := handlers.NewMediaHandler(mediaService)
+	newsSourceHandler := handlers.NewNewsSourceHandler(newsIngestionService)
+	newsFeedHandler := handlers.NewNewsFeedHandler(newsService, cfg.NewsFeedSiteURL, cfg.NewsFeedPublicURL, cfg.NewsFeedItemLimit, cfg.NewsFeedMaxAge)
 	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	duplicateHandler := handlers.NewDuplicateHandler(duplicateService)
+	screeningHandler := handlers.NewScreeningHandler(screeningService)
+	riskHandler := handlers.NewRiskHandler(riskService)
+	retentionHandler := handlers.NewRetentionHandler(retentionService)
+	idvHandler := handlers.NewIDVHandler(idvService)
 
 	// Documentación Swagger
 	router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -53,12 +165,26 @@ func SetupRoutes(router *gin.Engine, db *sql.DB) {
 			users.POST("/login", userHandler.LoginUser)
 		}
 
+		// Feeds públicos de noticias (general y por categoría)
+		feeds := v1.Group("/feeds/news")
+		{
+			feeds.GET("/rss", newsFeedHandler.GetRSS)
+			feeds.GET("/atom", newsFeedHandler.GetAtom)
+			feeds.GET("/:category/rss", newsFeedHandler.GetRSS)
+			feeds.GET("/:category/atom", newsFeedHandler.GetAtom)
+		}
+
+		// Webhooks de proveedores externos (autenticados por firma)
+		v1.POST("/webhooks/idv/:provider", idvHandler.HandleWebhook)
+
 		// Rutas protegidas (requieren autenticación)
 		protected := v1.Group("/")
 		protected.Use(middleware.JWTAuth(jwtSecret))
 		{
 			// Perfil de usuario
 			protected.GET("/users/profile", userHandler.GetProfile)
+			protected.POST("/users/token/refresh", userHandler.RefreshToken)
+			protected.PUT("/users/locale", userHandler.UpdateLocale)
 
 			// KYC
 			kyc := protected.Group("/kyc")
@@ -66,13 +192,35 @@ func SetupRoutes(router *gin.Engine, db *sql.DB) {
 				kyc.POST("/upload", kycHandler.UploadDocument)
 				kyc.GET("/documents", kycHandler.GetUserDocuments)
 				kyc.GET("/documents/:id/download", kycHandler.ServeDocument)
+
+				// Cargas reanudables (tus)
+				kyc.POST("/uploads", kycHandler.CreateUpload)
+				kyc.HEAD("/uploads/:id", kycHandler.GetUploadOffset)
+				kyc.GET("/uploads/:id", kycHandler.GetUpload)
+				kyc.PATCH("/uploads/:id", kycHandler.PatchUpload)
+				kyc.DELETE("/uploads/:id", kycHandler.CancelUpload)
 			}
 
 			// Noticias para usuarios
 			news := protected.Group("/news")
 			{
 				news.GET("/latest", newsHandler.GetLatestNews)
+				news.GET("/search", newsHandler.SearchNews)
+				news.GET("/categories", newsHandler.GetNewsCategories)
+				news.GET("/tags", newsHandler.GetNewsTags)
+				news.GET("/bookmarks", newsHandler.GetBookmarkedNews)
 				news.GET("/:id", newsHandler.GetNewsByID)
+				news.POST("/:id/read", newsHandler.MarkNewsAsRead)
+				news.POST("/:id/bookmark", newsHandler.BookmarkNews)
+				news.DELETE("/:id/bookmark", newsHandler.RemoveNewsBookmark)
+				news.GET("/:id/comments", newsHandler.GetNewsComments)
+				news.DELETE("/comments/:comment_id", newsHandler.DeleteNewsComment)
+
+				// Participar requiere identidad verificada
+				engagement := middleware.RequireKYCLevel(kycService, cfg.NewsEngagementKYCLevel)
+				news.PUT("/:id/reaction", engagement, newsHandler.SetNewsReaction)
+				news.DELETE("/:id/reaction", newsHandler.RemoveNewsReaction)
+				news.POST("/:id/comments", engagement, newsHandler.CreateNewsComment)
 			}
 
 			// Notificaciones para usuarios
@@ -83,6 +231,11 @@ func SetupRoutes(router *gin.Engine, db *sql.DB) {
 				notifications.PUT("/:id/read", notificationHandler.MarkAsRead)
 				notifications.PUT("/mark-all-read", notificationHandler.MarkAllAsRead)
 				notifications.DELETE("/:id", notificationHandler.DeleteNotification)
+
+				// Alertas de noticias destacadas por categoría
+				notifications.GET("/subscriptions", notificationHandler.GetNewsSubscriptions)
+				notifications.PUT("/subscriptions/:category", notificationHandler.SubscribeNewsCategory)
+				notifications.DELETE("/subscriptions/:category", notificationHandler.UnsubscribeNewsCategory)
 			}
 
 			// Área administrativa (solo admins)
@@ -93,12 +246,49 @@ func SetupRoutes(router *gin.Engine, db *sql.DB) {
 				admin.GET("/users", adminHandler.GetAllUsers)
 				admin.GET("/users/kyc", adminHandler.GetUsersByKYCStatus)
 				admin.GET("/dashboard/stats", adminHandler.GetDashboardStats)
+				admin.GET("/users/duplicates", duplicateHandler.GetDuplicateMatches)
+				admin.PUT("/users/duplicates/:id", duplicateHandler.ResolveDuplicateMatch)
+				admin.GET("/users/:id/risk", riskHandler.GetRiskHistory)
+				admin.POST("/users/:id/risk/recalculate", riskHandler.RecalculateRisk)
+				admin.GET("/risk/weights", riskHandler.GetWeights)
+				admin.PUT("/risk/weights", riskHandler.UpdateWeights)
 
 				// KYC
 				admin.GET("/kyc/pending", adminHandler.GetPendingDocuments)
 				admin.GET("/kyc/documents/:id/preview", adminHandler.ServeDocument)
 				admin.PUT("/kyc/:id/approve", adminHandler.ApproveDocument)
 				admin.PUT("/kyc/:id/reject", adminHandler.RejectDocument)
+				admin.POST("/kyc/bulk/approve", adminHandler.BulkApproveDocuments)
+				admin.POST("/kyc/bulk/reject", adminHandler.BulkRejectDocuments)
+				admin.PUT("/kyc/:id/expiry", adminHandler.SetDocumentExpiry)
+				admin.GET("/kyc/quarantine", adminHandler.GetQuarantinedDocuments)
+				admin.GET("/kyc/tiers", adminHandler.GetKYCTiers)
+				admin.PUT("/kyc/tiers/:level", adminHandler.UpdateKYCTier)
+				admin.POST("/users/:id/reverification", adminHandler.RequireReverification)
+
+				// Catálogo de motivos de rechazo KYC
+				admin.GET("/kyc/rejection-reasons", adminHandler.GetRejectionReasons)
+				admin.POST("/kyc/rejection-reasons", adminHandler.CreateRejectionReason)
+				admin.PUT("/kyc/rejection-reasons/:code", adminHandler.UpdateRejectionReason)
+				admin.DELETE("/kyc/rejection-reasons/:code", adminHandler.DeleteRejectionReason)
+
+				// Screening de sanciones y PEP
+				admin.GET("/screening/watchlists", screeningHandler.GetWatchlists)
+				admin.POST("/screening/watchlists/import", screeningHandler.ImportWatchlist)
+				admin.GET("/screening/hits", screeningHandler.GetHits)
+				admin.PUT("/screening/hits/:id", screeningHandler.ResolveHit)
+				admin.POST("/screening/users/:id", screeningHandler.ScreenUser)
+
+				// Retención de archivos KYC
+				admin.GET("/retention/report", retentionHandler.GetReport)
+				admin.POST("/retention/purge", retentionHandler.Purge)
+				admin.GET("/retention/tombstones", retentionHandler.GetTombstones)
+				admin.PUT("/users/:id/legal-hold", retentionHandler.SetLegalHold)
+				admin.POST("/users/:id/close", adminHandler.CloseAccount)
+
+				// Verificación de identidad externa
+				admin.GET("/users/:id/idv", idvHandler.GetVerifications)
+				admin.POST("/users/:id/idv", idvHandler.Submit)
 
 				// Noticias (CRUD completo)
 				// Registrar rutas tanto con como sin trailing slash
@@ -107,9 +297,48 @@ func SetupRoutes(router *gin.Engine, db *sql.DB) {
 				admin.GET("/news", newsHandler.GetNews)
 				admin.GET("/news/", newsHandler.GetNews)
 				admin.GET("/news/stats", newsHandler.GetNewsStats)
+				admin.GET("/news/stats/timeseries", newsHandler.GetNewsTimeSeries)
+				admin.GET("/news/scheduled", newsHandler.GetScheduledNews)
 				admin.GET("/news/:id", newsHandler.GetNewsByID)
 				admin.PUT("/news/:id", newsHandler.UpdateNews)
 				admin.DELETE("/news/:id", newsHandler.DeleteNews)
+				admin.PUT("/news/:id/status", newsHandler.ChangeNewsStatus)
+				admin.GET("/news/:id/stats", newsHandler.GetNewsArticleStats)
+				admin.GET("/news/:id/revisions", newsHandler.GetNewsRevisions)
+				admin.GET("/news/:id/revisions/:revision/diff", newsHandler.DiffNewsRevisions)
+				admin.POST("/news/:id/revisions/:revision/restore", newsHandler.RestoreNewsRevision)
+				admin.GET("/news/:id/translations", newsHandler.GetNewsTranslations)
+				admin.PUT("/news/:id/translations/:lang", newsHandler.UpsertNewsTranslation)
+				admin.DELETE("/news/:id/translations/:lang", newsHandler.DeleteNewsTranslation)
+				admin.GET("/news/:id/translations/:lang/revisions", newsHandler.GetNewsTranslationRevisions)
+
+				// Categorías y etiquetas de noticias
+				admin.GET("/news/categories", newsHandler.GetAllNewsCategories)
+				admin.POST("/news/categories", newsHandler.CreateNewsCategory)
+				admin.PUT("/news/categories/:slug", newsHandler.UpdateNewsCategory)
+				admin.DELETE("/news/categories/:slug", newsHandler.DeleteNewsCategory)
+				admin.GET("/news/tags", newsHandler.GetNewsTags)
+				admin.DELETE("/news/tags/:slug", newsHandler.DeleteNewsTag)
+
+				// Moderación de comentarios
+				admin.GET("/news/comments", newsHandler.GetCommentModerationQueue)
+				admin.PUT("/news/comments/:id", newsHandler.ModerateNewsComment)
+				admin.GET("/news/muted-users", newsHandler.GetMutedUsers)
+				admin.POST("/users/:id/mute", newsHandler.MuteUser)
+				admin.DELETE("/users/:id/mute", newsHandler.UnmuteUser)
+
+				// Fuentes externas de noticias (los artículos importados quedan como borradores)
+				admin.GET("/news/sources", newsSourceHandler.GetSources)
+				admin.POST("/news/sources", newsSourceHandler.CreateSource)
+				admin.PUT("/news/sources/:id", newsSourceHandler.UpdateSource)
+				admin.DELETE("/news/sources/:id", newsSourceHandler.DeleteSource)
+				admin.POST("/news/sources/:id/poll", newsSourceHandler.PollSource)
+
+				// Biblioteca de imágenes para noticias
+				admin.POST("/media", mediaHandler.UploadMedia)
+				admin.GET("/media", mediaHandler.GetMedia)
+				admin.GET("/media/:id", mediaHandler.GetMediaByID)
+				admin.DELETE("/media/:id", mediaHandler.DeleteMedia)
 
 				// Notificaciones (gestión completa)
 				// Registrar rutas tanto con como sin trailing slash
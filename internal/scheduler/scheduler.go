@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+)
+
+// Job representa una tarea que se ejecuta periódicamente en segundo plano
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+// Start lanza cada job en su propia goroutine: se ejecuta al iniciar y luego en cada intervalo
+func Start(jobs ...Job) {
+	for _, job := range jobs {
+		if job.Interval <= 0 {
+			log.Printf("Job %s deshabilitado (intervalo %v)", job.Name, job.Interval)
+			continue
+		}
+		go run(job)
+	}
+}
+
+func run(job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		execute(job)
+		<-ticker.C
+	}
+}
+
+// execute ejecuta el job protegiendo al proceso de un panic
+func execute(job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("❌ Job %s falló con panic: %v", job.Name, r)
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(); err != nil {
+		log.Printf("❌ Job %s falló: %v", job.Name, err)
+		return
+	}
+	log.Printf("✅ Job %s completado en %v", job.Name, time.Since(start))
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// defaultBlockedWords groserías que envían un comentario a moderación; se comparan palabras
+// completas, en minúsculas y sin acentos
+var defaultBlockedWords = []string{
+	"boludo", "cabron", "carajo", "cojudo", "concha", "coño", "culero", "estupido", "gilipollas",
+	"hijueputa", "hdp", "idiota", "imbecil", "joder", "malparido", "marica", "mierda", "pendejo",
+	"pelotudo", "puta", "puto", "verga", "zorra",
+	"asshole", "bastard", "bitch", "cunt", "dick", "fuck", "fucking", "motherfucker", "shit",
+}
+
+// commentLinkPattern URLs y dominios escritos en el texto ("www.x.com", "t.me/canal", "https://...")
+var commentLinkPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+|\b[a-z0-9][a-z0-9-]*\.(com|net|org|io|co|me|info|biz|xyz|ru|ly|gg|app|dev|link|site|online|top)\b`)
+
+// leetReplacer sustituciones habituales para evadir el filtro ("m1erd4")
+var leetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s")
+
+// Motivos por los que el filtro envía un comentario a moderación
+const (
+	commentFlagProfanity = "profanity"
+	commentFlagLink      = "link"
+)
+
+// commentFilter detecta groserías y enlaces en los comentarios
+type commentFilter struct {
+	words map[string]bool
+}
+
+// newCommentFilter crea el filtro con la lista por defecto más las palabras indicadas
+func newCommentFilter(extra []string) *commentFilter {
+	f := &commentFilter{words: make(map[string]bool)}
+	for _, word := range append(append([]string{}, defaultBlockedWords...), extra...) {
+		if normalized := normalizeFilterText(word); normalized != "" {
+			f.words[normalized] = true
+		}
+	}
+	return f
+}
+
+// check devuelve los motivos por los que el comentario requiere moderación (vacío si está limpio)
+func (f *commentFilter) check(content string) []string {
+	var reasons []string
+
+	for _, word := range strings.Fields(normalizeFilterText(leetReplacer.Replace(strings.ToLower(content)))) {
+		if f.words[word] {
+			reasons = append(reasons, commentFlagProfanity)
+			break
+		}
+	}
+
+	if commentLinkPattern.MatchString(content) {
+		reasons = append(reasons, commentFlagLink)
+	}
+
+	return reasons
+}
+
+// normalizeFilterText pasa el texto a minúsculas sin acentos y reemplaza lo que no sea letra
+// o dígito por espacios. La ñ se conserva para no confundir palabras ("coño" y "cono").
+func normalizeFilterText(text string) string {
+	var b strings.Builder
+	for _, r := range norm.NFC.String(strings.ToLower(text)) {
+		switch {
+		case r == 'ñ':
+			b.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			for _, d := range norm.NFD.String(string(r)) {
+				if !unicode.Is(unicode.Mn, d) {
+					b.WriteRune(d)
+				}
+			}
+		default:
+			b.WriteByte(' ')
+		}
+	}
+	return b.String()
+}
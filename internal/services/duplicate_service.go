@@ -0,0 +1,266 @@
+package services
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// duplicateHashThreshold distancia de Hamming máxima para considerar dos imágenes como la misma
+const duplicateHashThreshold = 10
+
+// duplicateHashBands bandas del hash indexadas; con una más que el umbral, toda imagen a distancia
+// <= duplicateHashThreshold comparte al menos una banda
+const duplicateHashBands = duplicateHashThreshold + 1
+
+// MatchResolutionListener recibe los usuarios afectados cuando un admin resuelve una coincidencia
+// (posible duplicado o screening), para recalcular lo que dependa de las coincidencias abiertas
+type MatchResolutionListener interface {
+	HandleMatchResolved(trigger string, userIDs ...uuid.UUID) error
+}
+
+// notifyMatchResolved avisa a los listeners; los errores se registran pero no interrumpen la resolución
+func notifyMatchResolved(listeners []MatchResolutionListener, trigger string, userIDs ...uuid.UUID) {
+	for _, listener := range listeners {
+		if err := listener.HandleMatchResolved(trigger, userIDs...); err != nil {
+			fmt.Printf("Error procesando resolución %s para usuarios %v: %v\n", trigger, userIDs, err)
+		}
+	}
+}
+
+// DuplicateService detecta posibles identidades duplicadas entre cuentas
+type DuplicateService struct {
+	DB        *sql.DB
+	listeners []MatchResolutionListener
+}
+
+func NewDuplicateService(db *sql.DB) *DuplicateService {
+	return &DuplicateService{DB: db}
+}
+
+// AddResolutionListener registra un listener de coincidencias resueltas
+func (s *DuplicateService) AddResolutionListener(listener MatchResolutionListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// HandleUserEvent compara los datos de registro con las cuentas existentes
+func (s *DuplicateService) HandleUserEvent(event models.UserEvent) error {
+	if event.Type != models.UserEventRegistered {
+		return nil
+	}
+	return s.CheckRegistration(event.UserID)
+}
+
+// HandleKYCEvent calcula el hash de cada documento subido y lo compara con los de otras cuentas
+func (s *DuplicateService) HandleKYCEvent(event models.KYCEvent) error {
+	if event.Type != models.KYCEventDocumentUploaded || event.DocumentID == nil {
+		return nil
+	}
+	return s.CheckDocument(*event.DocumentID)
+}
+
+// CheckRegistration registra coincidencias por número de documento normalizado o nombre completo
+func (s *DuplicateService) CheckRegistration(userID uuid.UUID) error {
+	rows, err := s.DB.Query(`
+		SELECT o.id,
+		       CASE WHEN o.document_number_normalized = u.document_number_normalized
+		            THEN 'document_number' ELSE 'name' END
+		FROM users u
+		JOIN users o ON o.id <> u.id AND (
+		     o.document_number_normalized = u.document_number_normalized
+		     OR (LOWER(TRIM(o.first_name)) = LOWER(TRIM(u.first_name))
+		         AND LOWER(TRIM(o.last_name)) = LOWER(TRIM(u.last_name)))
+		)
+		WHERE u.id = $1
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("error buscando cuentas similares: %v", err)
+	}
+	defer rows.Close()
+
+	type candidate struct {
+		userID    uuid.UUID
+		matchType string
+	}
+	var candidates []candidate
+	for rows.Next() {
+		var c candidate
+		if err := rows.Scan(&c.userID, &c.matchType); err != nil {
+			return fmt.Errorf("error escaneando cuenta similar: %v", err)
+		}
+		candidates = append(candidates, c)
+	}
+
+	for _, c := range candidates {
+		if err := s.recordMatch(userID, c.userID, c.matchType, nil, nil, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckDocument calcula el hash perceptual del documento y busca imágenes similares de otros usuarios
+func (s *DuplicateService) CheckDocument(docID uuid.UUID) error {
+	var userID uuid.UUID
+	var documentType, filePath string
+	err := s.DB.QueryRow(
+		"SELECT user_id, document_type, file_path FROM kyc_documents WHERE id = $1", docID,
+	).Scan(&userID, &documentType, &filePath)
+	if err != nil {
+		return fmt.Errorf("error obteniendo documento: %v", err)
+	}
+
+	hash, err := computeFileDHash(filePath)
+	if err != nil {
+		return err
+	}
+
+	bands := pq.Array(hashBands(hash, duplicateHashBands))
+	_, err = s.DB.Exec(
+		"UPDATE kyc_documents SET perceptual_hash = $1, perceptual_hash_bands = $2::integer[] WHERE id = $3",
+		hash, bands, docID,
+	)
+	if err != nil {
+		return fmt.Errorf("error guardando hash del documento: %v", err)
+	}
+
+	// Comparar solo contra documentos del mismo tipo (foto con foto, cédula con cédula) que comparten
+	// alguna banda del hash; la distancia exacta se calcula sobre esos candidatos
+	rows, err := s.DB.Query(`
+		SELECT id, user_id, perceptual_hash
+		FROM kyc_documents
+		WHERE perceptual_hash_bands && $1::integer[]
+		  AND document_type = $2 AND user_id <> $3 AND perceptual_hash IS NOT NULL
+	`, bands, documentType, userID)
+	if err != nil {
+		return fmt.Errorf("error obteniendo documentos a comparar: %v", err)
+	}
+	defer rows.Close()
+
+	type candidate struct {
+		docID    uuid.UUID
+		userID   uuid.UUID
+		distance int
+	}
+	var candidates []candidate
+	for rows.Next() {
+		var c candidate
+		var otherHash int64
+		if err := rows.Scan(&c.docID, &c.userID, &otherHash); err != nil {
+			return fmt.Errorf("error escaneando documento: %v", err)
+		}
+		c.distance = hammingDistance(hash, otherHash)
+		if c.distance <= duplicateHashThreshold {
+			candidates = append(candidates, c)
+		}
+	}
+
+	for _, c := range candidates {
+		distance := c.distance
+		if err := s.recordMatch(userID, c.userID, documentType, &docID, &c.docID, &distance); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordMatch guarda una coincidencia; si ya existía se actualiza con los datos más recientes.
+// Cada par de usuarios se guarda una sola vez, con el menor ID como user_id.
+func (s *DuplicateService) recordMatch(userID, matchedUserID uuid.UUID, matchType string, docID, matchedDocID *uuid.UUID, distance *int) error {
+	if bytes.Compare(userID[:], matchedUserID[:]) > 0 {
+		userID, matchedUserID = matchedUserID, userID
+		docID, matchedDocID = matchedDocID, docID
+	}
+
+	_, err := s.DB.Exec(`
+		INSERT INTO identity_duplicate_matches (
+			user_id, matched_user_id, match_type, document_id, matched_document_id, distance
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (LEAST(user_id, matched_user_id), GREATEST(user_id, matched_user_id), match_type) DO UPDATE SET
+			document_id = EXCLUDED.document_id,
+			matched_document_id = EXCLUDED.matched_document_id,
+			distance = EXCLUDED.distance
+	`, userID, matchedUserID, matchType, docID, matchedDocID, distance)
+	if err != nil {
+		return fmt.Errorf("error registrando posible duplicado: %v", err)
+	}
+	return nil
+}
+
+// GetDuplicateMatches obtiene el reporte de posibles duplicados filtrado por estado
+func (s *DuplicateService) GetDuplicateMatches(status string) ([]models.DuplicateMatch, error) {
+	query := `
+		SELECT m.id, m.user_id, m.matched_user_id, m.match_type, m.document_id,
+		       m.matched_document_id, m.distance, m.status, m.note, m.reviewed_by,
+		       m.reviewed_at, m.created_at,
+		       u.first_name, u.last_name, u.email, u.document_type, u.document_number, u.kyc_status, u.created_at,
+		       o.first_name, o.last_name, o.email, o.document_type, o.document_number, o.kyc_status, o.created_at
+		FROM identity_duplicate_matches m
+		JOIN users u ON u.id = m.user_id
+		JOIN users o ON o.id = m.matched_user_id
+	`
+
+	args := []interface{}{}
+	if status != "" {
+		query += " WHERE m.status = $1"
+		args = append(args, status)
+	}
+
+	query += " ORDER BY m.created_at DESC"
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo posibles duplicados: %v", err)
+	}
+	defer rows.Close()
+
+	var matches []models.DuplicateMatch
+	for rows.Next() {
+		var m models.DuplicateMatch
+		err := rows.Scan(
+			&m.ID, &m.UserID, &m.MatchedUserID, &m.MatchType, &m.DocumentID,
+			&m.MatchedDocumentID, &m.Distance, &m.Status, &m.Note, &m.ReviewedBy,
+			&m.ReviewedAt, &m.CreatedAt,
+			&m.User.FirstName, &m.User.LastName, &m.User.Email, &m.User.DocumentType,
+			&m.User.DocumentNumber, &m.User.KYCStatus, &m.User.CreatedAt,
+			&m.MatchedUser.FirstName, &m.MatchedUser.LastName, &m.MatchedUser.Email, &m.MatchedUser.DocumentType,
+			&m.MatchedUser.DocumentNumber, &m.MatchedUser.KYCStatus, &m.MatchedUser.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando posible duplicado: %v", err)
+		}
+		m.User.ID = m.UserID
+		m.MatchedUser.ID = m.MatchedUserID
+		matches = append(matches, m)
+	}
+
+	return matches, nil
+}
+
+// ResolveDuplicateMatch registra la decisión del administrador sobre una coincidencia
+func (s *DuplicateService) ResolveDuplicateMatch(id uuid.UUID, req models.ResolveDuplicateMatchRequest, adminID uuid.UUID) error {
+	var userID, matchedUserID uuid.UUID
+	err := s.DB.QueryRow(`
+		UPDATE identity_duplicate_matches
+		SET status = $1, note = $2, reviewed_by = $3, reviewed_at = $4
+		WHERE id = $5
+		RETURNING user_id, matched_user_id
+	`, req.Status, req.Note, adminID, time.Now(), id).Scan(&userID, &matchedUserID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("coincidencia no encontrada")
+	}
+	if err != nil {
+		return fmt.Errorf("error actualizando posible duplicado: %v", err)
+	}
+
+	notifyMatchResolved(s.listeners, "duplicate_resolved", userID, matchedUserID)
+	return nil
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// feedItem artículo leído de un feed RSS o Atom externo
+type feedItem struct {
+	GUID       string
+	Title      string
+	Link       string
+	Content    string // HTML del artículo o de su descripción
+	Categories []string
+	ImageURL   string
+}
+
+type rssFeedXML struct {
+	Items []struct {
+		GUID        string   `xml:"guid"`
+		Title       string   `xml:"title"`
+		Link        string   `xml:"link"`
+		Description string   `xml:"description"`
+		Encoded     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+		Categories  []string `xml:"category"`
+		Enclosures  []struct {
+			URL  string `xml:"url,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"enclosure"`
+		MediaContent []struct {
+			URL    string `xml:"url,attr"`
+			Medium string `xml:"medium,attr"`
+			Type   string `xml:"type,attr"`
+		} `xml:"http://search.yahoo.com/mrss/ content"`
+	} `xml:"channel>item"`
+}
+
+type atomFeedXML struct {
+	Entries []struct {
+		ID    string `xml:"id"`
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"link"`
+		Summary    string `xml:"summary"`
+		Content    string `xml:"content"`
+		Categories []struct {
+			Term string `xml:"term,attr"`
+		} `xml:"category"`
+	} `xml:"entry"`
+}
+
+// parseFeed lee un feed RSS 2.0 o Atom 1.0 según su elemento raíz
+func parseFeed(r io.Reader) ([]feedItem, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo feed: %v", err)
+	}
+
+	root, err := feedRootElement(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch root {
+	case "rss":
+		var feed rssFeedXML
+		if err := decodeFeed(data, &feed); err != nil {
+			return nil, err
+		}
+		items := make([]feedItem, 0, len(feed.Items))
+		for _, entry := range feed.Items {
+			item := feedItem{
+				GUID:       strings.TrimSpace(entry.GUID),
+				Title:      strings.Join(strings.Fields(entry.Title), " "),
+				Link:       strings.TrimSpace(entry.Link),
+				Content:    entry.Encoded,
+				Categories: entry.Categories,
+			}
+			if strings.TrimSpace(item.Content) == "" {
+				item.Content = entry.Description
+			}
+			for _, enclosure := range entry.Enclosures {
+				if strings.HasPrefix(enclosure.Type, "image/") {
+					item.ImageURL = enclosure.URL
+					break
+				}
+			}
+			for _, media := range entry.MediaContent {
+				if item.ImageURL == "" && (media.Medium == "image" || strings.HasPrefix(media.Type, "image/")) {
+					item.ImageURL = media.URL
+				}
+			}
+			items = append(items, item)
+		}
+		return items, nil
+
+	case "feed":
+		var feed atomFeedXML
+		if err := decodeFeed(data, &feed); err != nil {
+			return nil, err
+		}
+		items := make([]feedItem, 0, len(feed.Entries))
+		for _, entry := range feed.Entries {
+			item := feedItem{
+				GUID:    strings.TrimSpace(entry.ID),
+				Title:   strings.Join(strings.Fields(entry.Title), " "),
+				Content: entry.Content,
+			}
+			if strings.TrimSpace(item.Content) == "" {
+				item.Content = entry.Summary
+			}
+			for _, link := range entry.Links {
+				switch {
+				case (link.Rel == "" || link.Rel == "alternate") && item.Link == "":
+					item.Link = strings.TrimSpace(link.Href)
+				case link.Rel == "enclosure" && strings.HasPrefix(link.Type, "image/") && item.ImageURL == "":
+					item.ImageURL = link.Href
+				}
+			}
+			for _, category := range entry.Categories {
+				item.Categories = append(item.Categories, category.Term)
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+
+	return nil, fmt.Errorf("formato de feed no soportado: <%s>", root)
+}
+
+// feedRootElement obtiene el nombre del elemento raíz del documento
+func feedRootElement(data []byte) (string, error) {
+	decoder := newFeedDecoder(data)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("feed inválido: %v", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func decodeFeed(data []byte, v interface{}) error {
+	if err := newFeedDecoder(data).Decode(v); err != nil {
+		return fmt.Errorf("feed inválido: %v", err)
+	}
+	return nil
+}
+
+// newFeedDecoder crea un decoder que acepta codificaciones distintas de UTF-8 (ISO-8859-1, etc.)
+// y las entidades HTML que muchos feeds usan sin declararlas
+func newFeedDecoder(data []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.CharsetReader = charset.NewReaderLabel
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	return decoder
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// IdentityVerificationService envía los documentos KYC a un proveedor externo y aplica su veredicto
+type IdentityVerificationService struct {
+	DB         *sql.DB
+	KYCService *KYCService
+	Provider   VerificationProvider
+
+	// Confianza mínima (0-1) para aprobar o rechazar sin revisión manual
+	AutoApproveConfidence float64
+	AutoRejectConfidence  float64
+}
+
+// idvVerdictClaimTimeout tiempo tras el cual un veredicto registrado sin resultado se considera
+// abandonado (el proceso cayó mientras lo aplicaba) y un reintento del webhook puede retomarlo
+const idvVerdictClaimTimeout = 5 * time.Minute
+
+func NewIdentityVerificationService(db *sql.DB, kycService *KYCService, provider VerificationProvider, autoApprove, autoReject float64) *IdentityVerificationService {
+	return &IdentityVerificationService{
+		DB:                    db,
+		KYCService:            kycService,
+		Provider:              provider,
+		AutoApproveConfidence: autoApprove,
+		AutoRejectConfidence:  autoReject,
+	}
+}
+
+// HandleKYCEvent envía los documentos al proveedor cuando el usuario completa la carga
+func (s *IdentityVerificationService) HandleKYCEvent(event models.KYCEvent) error {
+	if event.Type != models.KYCEventDocumentsReceived {
+		return nil
+	}
+	_, err := s.Submit(event.UserID)
+	return err
+}
+
+// Submit envía los documentos pendientes del usuario al proveedor y registra el envío
+func (s *IdentityVerificationService) Submit(userID uuid.UUID) (*models.IDVVerification, error) {
+	if s.Provider == nil {
+		return nil, fmt.Errorf("verificación externa no configurada")
+	}
+
+	submission := models.IDVSubmission{
+		VerificationID: uuid.New(),
+		UserID:         userID,
+	}
+	err := s.DB.QueryRow("SELECT first_name, last_name, document_type, document_number FROM users WHERE id = $1", userID).
+		Scan(&submission.FirstName, &submission.LastName, &submission.DocumentType, &submission.DocumentNumber)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("usuario no encontrado")
+		}
+		return nil, fmt.Errorf("error obteniendo usuario: %v", err)
+	}
+
+	documents, err := s.KYCService.GetUserDocuments(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo documentos: %v", err)
+	}
+	for _, doc := range documents {
+		if doc.Status == models.KYCStatusPending {
+			submission.Documents = append(submission.Documents, doc)
+		}
+	}
+	if len(submission.Documents) == 0 {
+		return nil, fmt.Errorf("el usuario no tiene documentos pendientes")
+	}
+
+	verification := &models.IDVVerification{
+		ID:       submission.VerificationID,
+		UserID:   userID,
+		Provider: s.Provider.Name(),
+		Status:   "submitted",
+	}
+	for _, doc := range submission.Documents {
+		verification.DocumentIDs = append(verification.DocumentIDs, doc.ID)
+	}
+
+	// submitted_at lo fija la base de datos: se compara con el updated_at que escribe el trigger
+	// de kyc_documents, así que ambos deben venir del mismo reloj
+	err = s.DB.QueryRow(`
+		INSERT INTO idv_verifications (id, user_id, provider, document_ids, status)
+		VALUES ($1, $2, $3, $4::uuid[], $5)
+		RETURNING submitted_at
+	`, verification.ID, verification.UserID, verification.Provider, pq.Array(uuidStrings(verification.DocumentIDs)),
+		verification.Status).Scan(&verification.SubmittedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error registrando verificación: %v", err)
+	}
+
+	externalID, submitErr := s.Provider.Submit(submission)
+	if submitErr != nil {
+		message := submitErr.Error()
+		verification.Status = "failed"
+		verification.Error = &message
+		_, err = s.DB.Exec("UPDATE idv_verifications SET status = 'failed', error = $1 WHERE id = $2", message, verification.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error actualizando verificación: %v", err)
+		}
+		return verification, fmt.Errorf("error enviando documentos al proveedor: %v", submitErr)
+	}
+
+	verification.ExternalID = &externalID
+	_, err = s.DB.Exec("UPDATE idv_verifications SET external_id = $1 WHERE id = $2", externalID, verification.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error actualizando verificación: %v", err)
+	}
+
+	return verification, nil
+}
+
+// HandleWebhook valida y registra el veredicto del proveedor; luego aprueba, rechaza o deja
+// los documentos para revisión manual según la decisión y la confianza
+func (s *IdentityVerificationService) HandleWebhook(providerName string, header http.Header, body []byte) error {
+	if s.Provider == nil || providerName != s.Provider.Name() {
+		return fmt.Errorf("proveedor no encontrado")
+	}
+
+	verdict, err := s.Provider.ParseWebhook(header, body)
+	if err != nil {
+		return err
+	}
+
+	// Los proveedores reintentan los webhooks: solo el primero que marca la verificación como
+	// completada aplica el veredicto. Si el proceso cae antes de guardar el resultado, la fila
+	// queda completada sin outcome y un reintento posterior a idvVerdictClaimTimeout la retoma.
+	var verificationID, userID uuid.UUID
+	var documentIDs []uuid.UUID
+	var submittedAt time.Time
+	err = s.DB.QueryRow(`
+		UPDATE idv_verifications SET
+			status = 'completed', decision = $1, confidence = $2,
+			reason_codes = $3, payload = $4, completed_at = NOW()
+		WHERE provider = $5 AND external_id = $6
+		  AND (status <> 'completed'
+		       OR (outcome IS NULL AND completed_at <= NOW() - make_interval(secs => $7)))
+		RETURNING id, user_id, document_ids, submitted_at
+	`, verdict.Decision, verdict.Confidence, pq.Array(verdict.ReasonCodes), string(verdict.Payload),
+		providerName, verdict.ExternalID, idvVerdictClaimTimeout.Seconds(),
+	).Scan(&verificationID, &userID, pq.Array(&documentIDs), &submittedAt)
+	if err == sql.ErrNoRows {
+		var applied sql.NullBool
+		err = s.DB.QueryRow(
+			"SELECT outcome IS NOT NULL FROM idv_verifications WHERE provider = $1 AND external_id = $2",
+			providerName, verdict.ExternalID,
+		).Scan(&applied)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("verificación no encontrada")
+		}
+		if err != nil {
+			return fmt.Errorf("error obteniendo verificación: %v", err)
+		}
+		if !applied.Bool {
+			// Otro intento está aplicando el veredicto; el proveedor debe reintentar
+			return fmt.Errorf("veredicto en proceso")
+		}
+		// Veredicto ya registrado
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error guardando veredicto: %v", err)
+	}
+
+	outcome := s.applyVerdict(userID, documentIDs, submittedAt, providerName, verdict)
+
+	if _, err := s.DB.Exec("UPDATE idv_verifications SET outcome = $1 WHERE id = $2", outcome, verificationID); err != nil {
+		return fmt.Errorf("error guardando resultado del veredicto: %v", err)
+	}
+
+	return nil
+}
+
+// applyVerdict aplica la decisión a los documentos enviados al proveedor y devuelve el resultado.
+// Cualquier problema (confianza baja, screening abierto, motivos inválidos, documentos reemplazados
+// después del envío) deja el caso para revisión manual.
+func (s *IdentityVerificationService) applyVerdict(userID uuid.UUID, documentIDs []uuid.UUID, submittedAt time.Time, providerName string, verdict *models.IDVVerdict) string {
+	var canApply bool
+	switch verdict.Decision {
+	case models.IDVDecisionApproved:
+		canApply = verdict.Confidence >= s.AutoApproveConfidence
+	case models.IDVDecisionRejected:
+		canApply = verdict.Confidence >= s.AutoRejectConfidence && len(verdict.ReasonCodes) > 0
+	}
+	if !canApply {
+		return models.IDVOutcomeManualReview
+	}
+
+	// Solo se deciden los documentos que vio el proveedor, y solo si ninguno cambió desde el envío
+	unchanged, err := s.unchangedDocuments(documentIDs, submittedAt)
+	if err != nil {
+		fmt.Printf("Veredicto IDV de %s no aplicado al usuario %v: %v\n", providerName, userID, err)
+		return models.IDVOutcomeManualReview
+	}
+	if len(documentIDs) == 0 || unchanged != len(documentIDs) {
+		fmt.Printf("Veredicto IDV de %s no aplicado al usuario %v: documentos modificados después del envío\n",
+			providerName, userID)
+		return models.IDVOutcomeManualReview
+	}
+
+	request := models.BulkKYCRequest{DocumentIDs: documentIDs}
+	var result *models.BulkKYCResult
+	if verdict.Decision == models.IDVDecisionApproved {
+		result, err = s.KYCService.BulkApprove(request)
+	} else {
+		note := fmt.Sprintf("Rechazo automático de %s (confianza %.2f)", providerName, verdict.Confidence)
+		result, err = s.KYCService.BulkReject(models.BulkRejectRequest{
+			BulkKYCRequest: request,
+			ReasonCodes:    verdict.ReasonCodes,
+			InternalNote:   &note,
+		}, uuid.Nil)
+	}
+	if err != nil {
+		fmt.Printf("Veredicto IDV de %s no aplicado al usuario %v: %v\n", providerName, userID, err)
+		return models.IDVOutcomeManualReview
+	}
+	if result.Processed == 0 || result.Failed > 0 {
+		fmt.Printf("Veredicto IDV de %s aplicado parcialmente al usuario %v: %d de %d documentos\n",
+			providerName, userID, result.Succeeded, result.Processed)
+		return models.IDVOutcomeManualReview
+	}
+
+	if verdict.Decision == models.IDVDecisionApproved {
+		return models.IDVOutcomeAutoApproved
+	}
+	return models.IDVOutcomeAutoRejected
+}
+
+// unchangedDocuments cuenta los documentos que siguen pendientes y no se reemplazaron desde el envío
+func (s *IdentityVerificationService) unchangedDocuments(documentIDs []uuid.UUID, submittedAt time.Time) (int, error) {
+	var count int
+	err := s.DB.QueryRow(`
+		SELECT COUNT(*) FROM kyc_documents
+		WHERE id = ANY($1::uuid[]) AND status = 'pending' AND updated_at <= $2
+	`, pq.Array(uuidStrings(documentIDs)), submittedAt).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error verificando documentos enviados: %v", err)
+	}
+	return count, nil
+}
+
+// GetVerifications obtiene los envíos IDV de un usuario, del más reciente al más antiguo
+func (s *IdentityVerificationService) GetVerifications(userID uuid.UUID) ([]models.IDVVerification, error) {
+	rows, err := s.DB.Query(`
+		SELECT id, user_id, provider, external_id, document_ids, status, decision, confidence,
+		       reason_codes, outcome, error, submitted_at, completed_at
+		FROM idv_verifications WHERE user_id = $1
+		ORDER BY submitted_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo verificaciones: %v", err)
+	}
+	defer rows.Close()
+
+	var verifications []models.IDVVerification
+	for rows.Next() {
+		var v models.IDVVerification
+		err := rows.Scan(&v.ID, &v.UserID, &v.Provider, &v.ExternalID, pq.Array(&v.DocumentIDs), &v.Status, &v.Decision, &v.Confidence,
+			pq.Array(&v.ReasonCodes), &v.Outcome, &v.Error, &v.SubmittedAt, &v.CompletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando verificación: %v", err)
+		}
+		verifications = append(verifications, v)
+	}
+
+	return verifications, nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"image"
+	"image/draw"
+)
+
+// resizeImage reduce la imagen al ancho indicado manteniendo la proporción. Cada píxel de destino
+// es el promedio del área que cubre en la imagen original, lo que evita el aliasing al achicar.
+// Nunca agranda: si width es mayor o igual al ancho original devuelve una copia del mismo tamaño.
+func resizeImage(src image.Image, width int) *image.RGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	// Copiar a RGBA (premultiplicado) para promediar sin oscurecer los bordes transparentes
+	rgba := image.NewRGBA(image.Rect(0, 0, srcW, srcH))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	if width >= srcW {
+		return rgba
+	}
+
+	height := max(1, (srcH*width+srcW/2)/srcW)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcH / height
+		y1 := max(y0+1, (y+1)*srcH/height)
+		for x := 0; x < width; x++ {
+			x0 := x * srcW / width
+			x1 := max(x0+1, (x+1)*srcW/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride+x0*4 : sy*rgba.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					r += uint64(row[i])
+					g += uint64(row[i+1])
+					b += uint64(row[i+2])
+					a += uint64(row[i+3])
+					n++
+				}
+			}
+
+			offset := y*dst.Stride + x*4
+			dst.Pix[offset] = uint8((r + n/2) / n)
+			dst.Pix[offset+1] = uint8((g + n/2) / n)
+			dst.Pix[offset+2] = uint8((b + n/2) / n)
+			dst.Pix[offset+3] = uint8((a + n/2) / n)
+		}
+	}
+
+	return dst
+}
+
+// isOpaque indica si la imagen no tiene píxeles transparentes
+func isOpaque(img *image.RGBA) bool {
+	for i := 3; i < len(img.Pix); i += 4 {
+		if img.Pix[i] != 0xff {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// maxBulkDocuments límite de documentos por decisión en lote
+const maxBulkDocuments = 500
+
+// bulkTarget documento incluido en una decisión en lote
+type bulkTarget struct {
+	docID        uuid.UUID
+	userID       uuid.UUID
+	documentType string
+	status       models.KYCStatus
+}
+
+// BulkApprove aprueba en una sola transacción los documentos pendientes indicados.
+// Los usuarios bloqueados por una verificación previa (ej: screening) se reportan como fallidos.
+func (s *KYCService) BulkApprove(req models.BulkKYCRequest) (*models.BulkKYCResult, error) {
+	guardErrors := make(map[uuid.UUID]error)
+
+	return s.bulkDecide(req, nil,
+		func(t bulkTarget) error {
+			err, checked := guardErrors[t.userID]
+			if !checked {
+				err = s.checkApprovalGuards(t.userID)
+				guardErrors[t.userID] = err
+			}
+			return err
+		},
+		func(tx *sql.Tx, t bulkTarget) error {
+			return markDocumentApproved(tx, t.docID)
+		},
+		nil,
+	)
+}
+
+// BulkReject rechaza en una sola transacción los documentos pendientes indicados con los mismos motivos
+func (s *KYCService) BulkReject(req models.BulkRejectRequest, adminID uuid.UUID) (*models.BulkKYCResult, error) {
+	req.ReasonCodes = normalizeRejectionCodes(req.ReasonCodes)
+	rejection := models.RejectDocumentRequest{
+		ReasonCodes:  req.ReasonCodes,
+		InternalNote: req.InternalNote,
+	}
+
+	// El mensaje depende del tipo de documento; se arma una vez por tipo
+	messages := make(map[string]string)
+
+	return s.bulkDecide(req.BulkKYCRequest,
+		func(tx *sql.Tx) error {
+			return validateRejectionCodes(tx, req.ReasonCodes)
+		},
+		func(t bulkTarget) error {
+			if _, ok := messages[t.documentType]; ok {
+				return nil
+			}
+			message, err := s.RenderRejectionMessage(req.ReasonCodes, t.documentType, models.DefaultLanguage)
+			if err != nil {
+				return err
+			}
+			messages[t.documentType] = message
+			return nil
+		},
+		func(tx *sql.Tx, t bulkTarget) error {
+			return markDocumentRejected(tx, t.docID, messages[t.documentType], rejection, adminID)
+		},
+		func(t bulkTarget, userStatus models.KYCStatus) {
+			docID := t.docID
+			s.emit(models.KYCEvent{
+				Type:       models.KYCEventDocumentRejected,
+				UserID:     t.userID,
+				Status:     userStatus,
+				DocumentID: &docID,
+				Reasons:    []string{messages[t.documentType]},
+			})
+		},
+	)
+}
+
+// bulkDecide aplica una decisión a cada documento dentro de una transacción. Cada documento usa un
+// savepoint, de modo que un fallo individual no revierte el resto. Al confirmar, el estado KYC de
+// cada usuario afectado se recalcula una sola vez. Un error de prepare aborta todo el lote.
+func (s *KYCService) bulkDecide(
+	req models.BulkKYCRequest,
+	prepare func(tx *sql.Tx) error,
+	check func(t bulkTarget) error,
+	apply func(tx *sql.Tx, t bulkTarget) error,
+	afterCommit func(t bulkTarget, userStatus models.KYCStatus),
+) (*models.BulkKYCResult, error) {
+	if len(req.DocumentIDs) == 0 && len(req.UserIDs) == 0 {
+		return nil, fmt.Errorf("debe indicar document_ids o user_ids")
+	}
+
+	targets, missing, err := s.resolveBulkTargets(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) > maxBulkDocuments {
+		return nil, fmt.Errorf("demasiados documentos en el lote (máximo %d)", maxBulkDocuments)
+	}
+
+	result := &models.BulkKYCResult{Results: []models.BulkKYCItemResult{}}
+	for _, docID := range missing {
+		result.Results = append(result.Results, models.BulkKYCItemResult{DocumentID: docID, Error: "documento no encontrado"})
+	}
+
+	previousStatus, err := s.userKYCStatuses(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if prepare != nil {
+		if err := prepare(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	var applied []bulkTarget
+	for _, t := range targets {
+		userID := t.userID
+		item := models.BulkKYCItemResult{DocumentID: t.docID, UserID: &userID}
+
+		if t.status != models.KYCStatusPending {
+			item.Error = "el documento no está pendiente"
+		} else if err := check(t); err != nil {
+			item.Error = err.Error()
+		} else if err := applyWithSavepoint(tx, func() error { return apply(tx, t) }); err != nil {
+			item.Error = err.Error()
+		} else {
+			item.Success = true
+			applied = append(applied, t)
+		}
+
+		result.Results = append(result.Results, item)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error confirmando decisiones: %v", err)
+	}
+
+	affected := make(map[uuid.UUID]bool)
+	for _, t := range applied {
+		if afterCommit != nil {
+			afterCommit(t, previousStatus[t.userID])
+		}
+		affected[t.userID] = true
+	}
+	for userID := range affected {
+		if err := s.recomputeUserKYCStatus(userID, previousStatus[userID]); err != nil {
+			fmt.Printf("Error recalculando estado KYC del usuario %v: %v\n", userID, err)
+		}
+	}
+
+	for _, item := range result.Results {
+		result.Processed++
+		if item.Success {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+
+	return result, nil
+}
+
+// applyWithSavepoint ejecuta fn y revierte solo sus cambios si falla
+func applyWithSavepoint(tx *sql.Tx, fn func() error) error {
+	if _, err := tx.Exec("SAVEPOINT bulk_item"); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		if _, rbErr := tx.Exec("ROLLBACK TO SAVEPOINT bulk_item"); rbErr != nil {
+			return rbErr
+		}
+		return err
+	}
+	_, err := tx.Exec("RELEASE SAVEPOINT bulk_item")
+	return err
+}
+
+// resolveBulkTargets obtiene los documentos indicados por ID y los pendientes de los usuarios indicados;
+// devuelve además los IDs de documento que no existen
+func (s *KYCService) resolveBulkTargets(req models.BulkKYCRequest) ([]bulkTarget, []uuid.UUID, error) {
+	rows, err := s.DB.Query(`
+		SELECT id, user_id, document_type, status
+		FROM kyc_documents
+		WHERE purged_at IS NULL
+		  AND (id = ANY($1::uuid[]) OR (user_id = ANY($2::uuid[]) AND status = 'pending'))
+		ORDER BY user_id, document_type
+	`, pq.Array(uuidStrings(req.DocumentIDs)), pq.Array(uuidStrings(req.UserIDs)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error obteniendo documentos: %v", err)
+	}
+	defer rows.Close()
+
+	var targets []bulkTarget
+	found := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var t bulkTarget
+		if err := rows.Scan(&t.docID, &t.userID, &t.documentType, &t.status); err != nil {
+			return nil, nil, fmt.Errorf("error escaneando documento: %v", err)
+		}
+		targets = append(targets, t)
+		found[t.docID] = true
+	}
+
+	var missing []uuid.UUID
+	for _, docID := range req.DocumentIDs {
+		if !found[docID] {
+			missing = append(missing, docID)
+			found[docID] = true // no repetir IDs duplicados
+		}
+	}
+
+	return targets, missing, nil
+}
+
+// userKYCStatuses obtiene el estado KYC actual de los usuarios de los documentos
+func (s *KYCService) userKYCStatuses(targets []bulkTarget) (map[uuid.UUID]models.KYCStatus, error) {
+	var userIDs []uuid.UUID
+	for _, t := range targets {
+		userIDs = append(userIDs, t.userID)
+	}
+
+	rows, err := s.DB.Query("SELECT id, kyc_status FROM users WHERE id = ANY($1::uuid[])", pq.Array(uuidStrings(userIDs)))
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo usuarios: %v", err)
+	}
+	defer rows.Close()
+
+	statuses := make(map[uuid.UUID]models.KYCStatus)
+	for rows.Next() {
+		var id uuid.UUID
+		var status models.KYCStatus
+		if err := rows.Scan(&id, &status); err != nil {
+			return nil, fmt.Errorf("error escaneando usuario: %v", err)
+		}
+		statuses[id] = status
+	}
+
+	return statuses, nil
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+	return values
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// KYCEventListener recibe los eventos emitidos por KYCService.
+// Permite reaccionar a cambios de estado sin que KYCService dependa de otros servicios.
+type KYCEventListener interface {
+	HandleKYCEvent(event models.KYCEvent) error
+}
+
+// KYCApprovalGuard puede impedir la aprobación de documentos de un usuario (ej: screening pendiente)
+type KYCApprovalGuard interface {
+	CheckKYCApproval(userID uuid.UUID) error
+}
+
+// AddApprovalGuard registra una verificación previa a la aprobación de documentos
+func (s *KYCService) AddApprovalGuard(guard KYCApprovalGuard) {
+	s.guards = append(s.guards, guard)
+}
+
+// checkApprovalGuards ejecuta las verificaciones registradas antes de aprobar
+func (s *KYCService) checkApprovalGuards(userID uuid.UUID) error {
+	for _, guard := range s.guards {
+		if err := guard.CheckKYCApproval(userID); err != nil {
+			return fmt.Errorf("aprobación bloqueada: %v", err)
+		}
+	}
+	return nil
+}
+
+// AddEventListener registra un listener de eventos KYC
+func (s *KYCService) AddEventListener(listener KYCEventListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// emit notifica un evento a todos los listeners; los errores se registran pero no interrumpen el flujo KYC
+func (s *KYCService) emit(event models.KYCEvent) {
+	for _, listener := range s.listeners {
+		if err := listener.HandleKYCEvent(event); err != nil {
+			fmt.Printf("Error procesando evento KYC %s para usuario %v: %v\n", event.Type, event.UserID, err)
+		}
+	}
+}
+
+// KYCNotifier crea notificaciones de categoría "kyc" a partir de los eventos KYC
+type KYCNotifier struct {
+	NotificationService *NotificationService
+}
+
+func NewKYCNotifier(notificationService *NotificationService) *KYCNotifier {
+	return &KYCNotifier{NotificationService: notificationService}
+}
+
+// HandleKYCEvent crea la notificación correspondiente al evento y la envía por push
+func (n *KYCNotifier) HandleKYCEvent(event models.KYCEvent) error {
+	req := models.CreateNotificationRequest{
+		UserID:   &event.UserID,
+		Category: "kyc",
+		SendPush: true,
+	}
+
+	switch event.Type {
+	case models.KYCEventApproved:
+		req.Type = "success"
+		req.Title = "Verificación aprobada"
+		req.Message = "Tu identidad ha sido verificada. Ya puedes usar todas las funciones de TradeOptix."
+	case models.KYCEventRejected:
+		req.Type = "error"
+		req.Title = "Verificación rechazada"
+		req.Message = "No pudimos verificar tu identidad."
+		if len(event.Reasons) > 0 {
+			req.Message += " " + strings.Join(event.Reasons, " ")
+		}
+		req.Message += " Por favor sube nuevamente los documentos indicados."
+	case models.KYCEventDocumentRejected:
+		// Si el usuario pasa a rechazado ya se notifica con kyc_rejected; solo se avisa aquí cuando
+		// ya estaba rechazado y el estado no cambia
+		if event.Status != models.KYCStatusRejected {
+			return nil
+		}
+		req.Type = "error"
+		req.Title = "Documento rechazado"
+		req.Message = "Uno de tus documentos fue rechazado."
+		if len(event.Reasons) > 0 {
+			req.Message = strings.Join(event.Reasons, " ")
+		}
+		req.Message += " Por favor súbelo nuevamente."
+	case models.KYCEventExpiryReminder:
+		req.Type = "warning"
+		req.Title = "Tu documento está por vencer"
+		req.Message = "Uno de tus documentos de identidad está por vencer."
+		if event.ExpiresAt != nil {
+			req.Message = fmt.Sprintf("Uno de tus documentos de identidad vence el %s.", event.ExpiresAt.Format("02/01/2006"))
+		}
+		req.Message += " Actualízalo para mantener tu cuenta verificada."
+	case models.KYCEventReverification:
+		req.Type = "warning"
+		req.Title = "Necesitamos verificar tu identidad nuevamente"
+		req.Message = "Por seguridad debes volver a subir tus documentos de identidad vigentes."
+	case models.KYCEventDocumentQuarantined:
+		req.Type = "error"
+		req.Title = "No pudimos procesar tu documento"
+		req.Message = "El archivo que subiste no superó nuestro control de seguridad. Por favor sube una nueva foto del documento."
+	case models.KYCEventLevelChanged:
+		if event.Level == nil || event.Silent {
+			return nil
+		}
+		req.Type = "info"
+		req.Title = "Tu nivel de verificación cambió"
+		req.Message = fmt.Sprintf("Tu cuenta ahora tiene nivel de verificación %d.", *event.Level)
+	case models.KYCEventDocumentsReceived:
+		req.Type = "info"
+		req.Title = "Documentos recibidos"
+		req.Message = "Recibimos todos tus documentos. Te avisaremos cuando termine la revisión."
+	default:
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"event":      event.Type,
+		"kyc_status": event.Status,
+	}
+	if event.Level != nil {
+		data["kyc_level"] = *event.Level
+	}
+	if event.DocumentID != nil {
+		data["document_id"] = *event.DocumentID
+	}
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	dataStr := string(dataJSON)
+	req.Data = &dataStr
+
+	_, err = n.NotificationService.CreateNotification(req)
+	return err
+}
+
+// rejectionReasonsForUser obtiene los motivos de rechazo vigentes de los documentos del usuario
+func (s *KYCService) rejectionReasonsForUser(userID uuid.UUID) ([]string, error) {
+	rows, err := s.DB.Query(`
+		SELECT rejection_reason FROM kyc_documents
+		WHERE user_id = $1 AND status = 'rejected' AND rejection_reason IS NOT NULL
+		ORDER BY updated_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reasons []string
+	for rows.Next() {
+		var reason string
+		if err := rows.Scan(&reason); err != nil {
+			return nil, err
+		}
+		reasons = append(reasons, reason)
+	}
+
+	return reasons, nil
+}
@@ -0,0 +1,336 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// documentTypeLabels nombres legibles de cada tipo de documento por idioma
+var documentTypeLabels = map[string]map[string]string{
+	"es": {
+		"cedula_front": "la parte frontal de tu cédula",
+		"cedula_back":  "la parte trasera de tu cédula",
+		"face_photo":   "tu foto facial",
+	},
+	"en": {
+		"cedula_front": "the front of your ID card",
+		"cedula_back":  "the back of your ID card",
+		"face_photo":   "your face photo",
+	},
+}
+
+// GetRejectionReasons obtiene el catálogo de motivos de rechazo con sus plantillas
+func (s *KYCService) GetRejectionReasons(activeOnly bool) ([]models.RejectionReason, error) {
+	query := `
+		SELECT code, description, is_active, created_at, updated_at
+		FROM kyc_rejection_reasons
+	`
+	if activeOnly {
+		query += " WHERE is_active = true"
+	}
+	query += " ORDER BY code"
+
+	rows, err := s.DB.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo motivos de rechazo: %v", err)
+	}
+	defer rows.Close()
+
+	var reasons []models.RejectionReason
+	index := make(map[string]int)
+	for rows.Next() {
+		var reason models.RejectionReason
+		if err := rows.Scan(&reason.Code, &reason.Description, &reason.IsActive, &reason.CreatedAt, &reason.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error escaneando motivo de rechazo: %v", err)
+		}
+		reason.Templates = make(map[string]string)
+		index[reason.Code] = len(reasons)
+		reasons = append(reasons, reason)
+	}
+
+	templateRows, err := s.DB.Query("SELECT reason_code, language, message FROM kyc_rejection_reason_templates")
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo plantillas de rechazo: %v", err)
+	}
+	defer templateRows.Close()
+
+	for templateRows.Next() {
+		var code, language, message string
+		if err := templateRows.Scan(&code, &language, &message); err != nil {
+			return nil, fmt.Errorf("error escaneando plantilla de rechazo: %v", err)
+		}
+		if i, ok := index[code]; ok {
+			reasons[i].Templates[language] = message
+		}
+	}
+
+	return reasons, nil
+}
+
+// CreateRejectionReason agrega un motivo al catálogo
+func (s *KYCService) CreateRejectionReason(req models.CreateRejectionReasonRequest) (*models.RejectionReason, error) {
+	if _, ok := req.Templates[models.DefaultLanguage]; !ok {
+		return nil, fmt.Errorf("se requiere la plantilla en idioma '%s'", models.DefaultLanguage)
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	reason := models.RejectionReason{
+		Code:        strings.ToLower(strings.TrimSpace(req.Code)),
+		Description: req.Description,
+		IsActive:    true,
+		Templates:   req.Templates,
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO kyc_rejection_reasons (code, description)
+		VALUES ($1, $2)
+		RETURNING created_at, updated_at
+	`, reason.Code, reason.Description).Scan(&reason.CreatedAt, &reason.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error creando motivo de rechazo: %v", err)
+	}
+
+	if err := upsertRejectionTemplates(tx, reason.Code, req.Templates); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &reason, nil
+}
+
+// UpdateRejectionReason actualiza la descripción, el estado o las plantillas de un motivo
+func (s *KYCService) UpdateRejectionReason(code string, req models.UpdateRejectionReasonRequest) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE kyc_rejection_reasons
+		SET description = COALESCE($1, description),
+		    is_active = COALESCE($2, is_active),
+		    updated_at = $3
+		WHERE code = $4
+	`, req.Description, req.IsActive, time.Now(), code)
+	if err != nil {
+		return fmt.Errorf("error actualizando motivo de rechazo: %v", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("motivo de rechazo no encontrado")
+	}
+
+	if err := upsertRejectionTemplates(tx, code, req.Templates); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeactivateRejectionReason desactiva un motivo; se conserva por los rechazos históricos que lo usan
+func (s *KYCService) DeactivateRejectionReason(code string) error {
+	result, err := s.DB.Exec(
+		"UPDATE kyc_rejection_reasons SET is_active = false, updated_at = $1 WHERE code = $2",
+		time.Now(), code,
+	)
+	if err != nil {
+		return fmt.Errorf("error desactivando motivo de rechazo: %v", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("motivo de rechazo no encontrado")
+	}
+
+	return nil
+}
+
+// RenderRejectionMessage compone el mensaje para el usuario a partir de los códigos de rechazo
+func (s *KYCService) RenderRejectionMessage(codes []string, documentType, language string) (string, error) {
+	rows, err := s.DB.Query(`
+		SELECT reason_code, language, message
+		FROM kyc_rejection_reason_templates
+		WHERE reason_code = ANY($1) AND language IN ($2, $3)
+	`, pq.Array(codes), language, models.DefaultLanguage)
+	if err != nil {
+		return "", fmt.Errorf("error obteniendo plantillas de rechazo: %v", err)
+	}
+	defer rows.Close()
+
+	templates := make(map[string]map[string]string)
+	for rows.Next() {
+		var code, lang, message string
+		if err := rows.Scan(&code, &lang, &message); err != nil {
+			return "", fmt.Errorf("error escaneando plantilla de rechazo: %v", err)
+		}
+		if templates[code] == nil {
+			templates[code] = make(map[string]string)
+		}
+		templates[code][lang] = message
+	}
+
+	labels, ok := documentTypeLabels[language]
+	if !ok {
+		labels = documentTypeLabels[models.DefaultLanguage]
+	}
+	label, ok := labels[documentType]
+	if !ok {
+		label = documentType
+	}
+	replacer := strings.NewReplacer("{document}", label)
+
+	var messages []string
+	for _, code := range codes {
+		message, ok := templates[code][language]
+		if !ok {
+			message, ok = templates[code][models.DefaultLanguage]
+		}
+		if !ok {
+			return "", fmt.Errorf("motivo de rechazo sin plantilla: %s", code)
+		}
+		messages = append(messages, replacer.Replace(message))
+	}
+
+	return strings.Join(messages, " "), nil
+}
+
+// LocalizeRejections reemplaza el motivo de rechazo de cada documento por su versión en el primer
+// idioma de la lista que tenga plantillas; si ninguno las tiene se deja el original
+func (s *KYCService) LocalizeRejections(documents []models.KYCDocument, languages []string) error {
+	language, err := s.rejectionLanguage(languages)
+	if err != nil {
+		return err
+	}
+	if language == models.DefaultLanguage {
+		return nil
+	}
+
+	for i := range documents {
+		if len(documents[i].RejectionCodes) == 0 {
+			continue
+		}
+		message, err := s.RenderRejectionMessage(documents[i].RejectionCodes, documents[i].DocumentType, language)
+		if err != nil {
+			continue
+		}
+		documents[i].RejectionReason = &message
+	}
+	return nil
+}
+
+// rejectionLanguage elige el primer idioma preferido con plantillas de rechazo; el idioma por
+// defecto corta la búsqueda porque el mensaje original ya está en él
+func (s *KYCService) rejectionLanguage(languages []string) (string, error) {
+	if len(languages) == 0 {
+		return models.DefaultLanguage, nil
+	}
+
+	rows, err := s.DB.Query(
+		"SELECT DISTINCT language FROM kyc_rejection_reason_templates WHERE language = ANY($1)", pq.Array(languages),
+	)
+	if err != nil {
+		return "", fmt.Errorf("error obteniendo idiomas de las plantillas de rechazo: %v", err)
+	}
+	defer rows.Close()
+
+	available := make(map[string]bool)
+	for rows.Next() {
+		var language string
+		if err := rows.Scan(&language); err != nil {
+			return "", fmt.Errorf("error escaneando idioma de las plantillas de rechazo: %v", err)
+		}
+		available[language] = true
+	}
+
+	for _, language := range languages {
+		if language == models.DefaultLanguage || available[language] {
+			return language, nil
+		}
+	}
+	return models.DefaultLanguage, nil
+}
+
+// normalizeRejectionCodes pasa los códigos a minúsculas y quita espacios y repetidos,
+// conservando el orden en que se indicaron
+func normalizeRejectionCodes(codes []string) []string {
+	seen := make(map[string]bool)
+	normalized := make([]string, 0, len(codes))
+	for _, code := range codes {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		normalized = append(normalized, code)
+	}
+	return normalized
+}
+
+// validateRejectionCodes verifica que todos los códigos (ya normalizados) existan y estén activos
+func validateRejectionCodes(tx *sql.Tx, codes []string) error {
+	var active int
+	err := tx.QueryRow(
+		"SELECT COUNT(*) FROM kyc_rejection_reasons WHERE code = ANY($1) AND is_active = true",
+		pq.Array(codes),
+	).Scan(&active)
+	if err != nil {
+		return fmt.Errorf("error validando motivos de rechazo: %v", err)
+	}
+
+	if len(codes) == 0 || active != len(codes) {
+		return fmt.Errorf("motivo de rechazo inválido o inactivo")
+	}
+
+	return nil
+}
+
+// recordRejection guarda los motivos aplicados para estadísticas e historial
+func recordRejection(tx *sql.Tx, docID uuid.UUID, codes []string, adminID uuid.UUID) error {
+	// uuid.Nil indica un rechazo automático (sin revisor)
+	var rejectedBy interface{}
+	if adminID != uuid.Nil {
+		rejectedBy = adminID
+	}
+
+	for _, code := range codes {
+		_, err := tx.Exec(
+			"INSERT INTO kyc_document_rejections (document_id, reason_code, rejected_by) VALUES ($1, $2, $3)",
+			docID, code, rejectedBy,
+		)
+		if err != nil {
+			return fmt.Errorf("error registrando motivo de rechazo: %v", err)
+		}
+	}
+	return nil
+}
+
+func upsertRejectionTemplates(tx *sql.Tx, code string, templates map[string]string) error {
+	for language, message := range templates {
+		_, err := tx.Exec(`
+			INSERT INTO kyc_rejection_reason_templates (reason_code, language, message)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (reason_code, language) DO UPDATE SET message = EXCLUDED.message
+		`, code, strings.ToLower(language), message)
+		if err != nil {
+			return fmt.Errorf("error guardando plantilla de rechazo: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ReverificationPolicy define cuándo un usuario aprobado debe volver a verificar su identidad
+type ReverificationPolicy struct {
+	LeadDays        int                      // días antes del vencimiento en que se exige re-verificar
+	ReminderOffsets []int                    // días antes del vencimiento en que se envían recordatorios
+	CadenceMonths   map[models.RiskLevel]int // re-verificación periódica según nivel de riesgo (0 = deshabilitada)
+}
+
+// SetReverificationPolicy configura la política de vencimientos y re-verificación
+func (s *KYCService) SetReverificationPolicy(policy ReverificationPolicy) {
+	offsets := append([]int(nil), policy.ReminderOffsets...)
+	sort.Ints(offsets)
+	policy.ReminderOffsets = offsets
+	s.reverification = policy
+}
+
+// SetDocumentExpiry guarda la fecha de vencimiento capturada por el revisor
+func (s *KYCService) SetDocumentExpiry(docID uuid.UUID, expiresAt time.Time) error {
+	result, err := s.DB.Exec("UPDATE kyc_documents SET expires_at = $1, updated_at = $2 WHERE id = $3",
+		expiresAt, time.Now(), docID)
+	if err != nil {
+		return fmt.Errorf("error guardando vencimiento: %v", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("documento no encontrado")
+	}
+
+	return nil
+}
+
+// RequireReverification marca los documentos aprobados del usuario como vencidos,
+// lo que obliga a subirlos de nuevo y deja al usuario en estado reverification_required
+func (s *KYCService) RequireReverification(userID uuid.UUID) error {
+	return s.expireDocuments(userID, nil)
+}
+
+// expireDocuments marca como vencidos los documentos aprobados indicados del usuario
+// (todos si documentIDs es nil) y recalcula su estado KYC
+func (s *KYCService) expireDocuments(userID uuid.UUID, documentIDs []uuid.UUID) error {
+	var previousStatus models.KYCStatus
+	if err := s.DB.QueryRow("SELECT kyc_status FROM users WHERE id = $1", userID).Scan(&previousStatus); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE kyc_documents SET status = $1, updated_at = $2
+		WHERE user_id = $3 AND status = 'approved'
+	`
+	args := []interface{}{models.KYCStatusExpired, time.Now(), userID}
+	if documentIDs != nil {
+		query += " AND id = ANY($4)"
+		args = append(args, pq.Array(documentIDs))
+	}
+
+	if _, err := s.DB.Exec(query, args...); err != nil {
+		return fmt.Errorf("error marcando documentos como vencidos: %v", err)
+	}
+
+	return s.recomputeUserKYCStatus(userID, previousStatus)
+}
+
+// RunReverificationCheck envía recordatorios de vencimiento y exige re-verificación
+// por documentos próximos a vencer o por la cadencia periódica según riesgo
+func (s *KYCService) RunReverificationCheck() error {
+	if err := s.sendExpiryReminders(); err != nil {
+		return err
+	}
+
+	due, err := s.usersDueForReverification()
+	if err != nil {
+		return err
+	}
+
+	for _, d := range due {
+		// La cadencia periódica exige re-verificar todo; un vencimiento, solo los documentos que vencen
+		documentIDs := d.expiringDocumentIDs
+		if d.cadenceDue {
+			documentIDs = nil
+		}
+		if err := s.expireDocuments(d.userID, documentIDs); err != nil {
+			return fmt.Errorf("error exigiendo re-verificación al usuario %v: %v", d.userID, err)
+		}
+	}
+
+	return nil
+}
+
+// sendExpiryReminders notifica a los usuarios aprobados cuyos documentos vencen pronto
+func (s *KYCService) sendExpiryReminders() error {
+	offsets := s.reverification.ReminderOffsets
+	if len(offsets) == 0 {
+		return nil
+	}
+	maxOffset := offsets[len(offsets)-1]
+
+	rows, err := s.DB.Query(`
+		SELECT d.id, d.user_id, d.expires_at, (d.expires_at - CURRENT_DATE) AS days_left
+		FROM kyc_documents d
+		JOIN users u ON u.id = d.user_id
+		WHERE u.kyc_status = 'approved' AND d.status = 'approved'
+		  AND d.expires_at IS NOT NULL
+		  AND d.expires_at >= CURRENT_DATE
+		  AND d.expires_at <= CURRENT_DATE + $1::int
+	`, maxOffset)
+	if err != nil {
+		return fmt.Errorf("error obteniendo documentos por vencer: %v", err)
+	}
+	defer rows.Close()
+
+	type expiring struct {
+		docID     uuid.UUID
+		userID    uuid.UUID
+		expiresAt time.Time
+		daysLeft  int
+	}
+	var docs []expiring
+	for rows.Next() {
+		var d expiring
+		if err := rows.Scan(&d.docID, &d.userID, &d.expiresAt, &d.daysLeft); err != nil {
+			return fmt.Errorf("error escaneando documento por vencer: %v", err)
+		}
+		docs = append(docs, d)
+	}
+
+	for _, d := range docs {
+		// El recordatorio vigente es el menor offset que aún cubre los días restantes
+		offset := -1
+		for _, o := range offsets {
+			if o >= d.daysLeft {
+				offset = o
+				break
+			}
+		}
+		if offset < 0 {
+			continue
+		}
+
+		result, err := s.DB.Exec(`
+			INSERT INTO kyc_expiry_reminders (document_id, expires_at, offset_days)
+			VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING
+		`, d.docID, d.expiresAt, offset)
+		if err != nil {
+			return fmt.Errorf("error registrando recordatorio: %v", err)
+		}
+		if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+			continue // ya enviado
+		}
+
+		docID, expiresAt := d.docID, d.expiresAt
+		s.emit(models.KYCEvent{
+			Type:       models.KYCEventExpiryReminder,
+			UserID:     d.userID,
+			Status:     models.KYCStatusApproved,
+			DocumentID: &docID,
+			ExpiresAt:  &expiresAt,
+		})
+	}
+
+	return nil
+}
+
+// reverificationDue usuario que debe re-verificar y por qué
+type reverificationDue struct {
+	userID              uuid.UUID
+	expiringDocumentIDs []uuid.UUID
+	cadenceDue          bool
+}
+
+// usersDueForReverification obtiene usuarios aprobados con documentos por vencer o con verificación antigua
+func (s *KYCService) usersDueForReverification() ([]reverificationDue, error) {
+	cadence := s.reverification.CadenceMonths
+	rows, err := s.DB.Query(`
+		SELECT id, expiring, cadence_due FROM (
+			SELECT u.id,
+			       ARRAY(
+			           SELECT d.id FROM kyc_documents d
+			           WHERE d.user_id = u.id AND d.status = 'approved'
+			             AND d.expires_at IS NOT NULL AND d.expires_at <= CURRENT_DATE + $1::int
+			       ) AS expiring,
+			       COALESCE(c.cadence_months > 0 AND u.kyc_verified_at IS NOT NULL
+			                AND u.kyc_verified_at + make_interval(months => c.cadence_months) <= NOW(), false) AS cadence_due
+			FROM users u
+			CROSS JOIN LATERAL (
+			    SELECT CASE COALESCE(u.risk_level, 'low')
+			                WHEN 'high' THEN $4::int
+			                WHEN 'medium' THEN $3::int
+			                ELSE $2::int
+			           END AS cadence_months
+			) c
+			WHERE u.kyc_status = 'approved' AND u.role = 'user'
+		) due
+		WHERE cardinality(due.expiring) > 0 OR due.cadence_due
+	`, s.reverification.LeadDays, cadence[models.RiskLevelLow], cadence[models.RiskLevelMedium], cadence[models.RiskLevelHigh])
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo usuarios para re-verificación: %v", err)
+	}
+	defer rows.Close()
+
+	var due []reverificationDue
+	for rows.Next() {
+		var d reverificationDue
+		if err := rows.Scan(&d.userID, pq.Array(&d.expiringDocumentIDs), &d.cadenceDue); err != nil {
+			return nil, fmt.Errorf("error escaneando usuario: %v", err)
+		}
+		due = append(due, d)
+	}
+
+	return due, nil
+}
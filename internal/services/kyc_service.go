@@ -8,62 +8,124 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 	"tradeoptix-back/internal/models"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type KYCService struct {
 	DB        *sql.DB
 	UploadDir string
+	listeners []KYCEventListener
+	guards    []KYCApprovalGuard
+
+	reverification ReverificationPolicy
+
+	scanner       Scanner
+	quarantineDir string
+
+	partialDir       string
+	uploadSessionTTL time.Duration
+	uploadsMu        sync.Mutex
+	activeUploads    map[uuid.UUID]bool
 }
 
+// maxDocumentSize tamaño máximo de un documento KYC (5MB)
+const maxDocumentSize = 5 * 1024 * 1024
+
+// maxOriginalNameLength largo máximo del nombre original del archivo (kyc_documents.original_name)
+const maxOriginalNameLength = 200
+
+// requiredDocumentTypes documentos necesarios para completar el KYC
+var requiredDocumentTypes = []string{"cedula_front", "cedula_back", "face_photo"}
+
+// allowedDocumentTypes documentos que se pueden subir (los opcionales habilitan niveles KYC superiores)
+var allowedDocumentTypes = append(append([]string{}, requiredDocumentTypes...), "proof_of_address")
+
 func NewKYCService(db *sql.DB, uploadDir string) *KYCService {
 	return &KYCService{
 		DB:        db,
 		UploadDir: uploadDir,
+		scanner:   NoopScanner{},
+
+		partialDir:       filepath.Clean(uploadDir) + "_partial",
+		uploadSessionTTL: 24 * time.Hour,
+		activeUploads:    make(map[uuid.UUID]bool),
 	}
 }
 
+// SetScanner configura el antivirus que analiza cada archivo subido y el directorio
+// (fuera de UploadDir) donde se aíslan los archivos infectados
+func (s *KYCService) SetScanner(scanner Scanner, quarantineDir string) {
+	s.scanner = scanner
+	s.quarantineDir = quarantineDir
+}
+
 func (s *KYCService) UploadDocument(userID uuid.UUID, documentType string, file *multipart.FileHeader) (*models.KYCDocument, error) {
+	mimeType := file.Header.Get("Content-Type")
+	if err := validateUpload(documentType, file.Filename, mimeType, file.Size); err != nil {
+		return nil, err
+	}
+
+	// Abrir archivo cargado
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo archivo: %v", err)
+	}
+	defer src.Close()
+
+	return s.storeDocument(userID, documentType, file.Filename, mimeType, file.Size, src)
+}
+
+// validateUpload valida el tipo de documento, el nombre, el tipo de archivo y el tamaño de una subida
+func validateUpload(documentType, originalName, mimeType string, size int64) error {
 	// Validar tipo de documento
-	validTypes := []string{"cedula_front", "cedula_back", "face_photo"}
-	if !contains(validTypes, documentType) {
-		return nil, fmt.Errorf("tipo de documento inválido: %s", documentType)
+	if !contains(allowedDocumentTypes, documentType) {
+		return fmt.Errorf("tipo de documento inválido: %s", documentType)
+	}
+
+	// Validar nombre de archivo: debe caber en el registro del documento
+	if utf8.RuneCountInString(originalName) > maxOriginalNameLength {
+		return fmt.Errorf("nombre de archivo demasiado largo. Máximo %d caracteres", maxOriginalNameLength)
 	}
 
 	// Validar tipo de archivo
-	if !isValidImageType(file.Header.Get("Content-Type")) {
-		return nil, fmt.Errorf("tipo de archivo no permitido. Solo se permiten imágenes JPG, PNG")
+	if !isValidImageType(mimeType) {
+		return fmt.Errorf("tipo de archivo no permitido. Solo se permiten imágenes JPG, PNG")
 	}
 
 	// Validar tamaño de archivo (max 5MB)
-	if file.Size > 5*1024*1024 {
-		return nil, fmt.Errorf("archivo demasiado grande. Máximo 5MB")
+	if size > maxDocumentSize {
+		return fmt.Errorf("archivo demasiado grande. Máximo 5MB")
 	}
 
+	return nil
+}
+
+// storeDocument guarda el contenido de un documento ya validado, lo analiza y crea o reemplaza su registro
+func (s *KYCService) storeDocument(userID uuid.UUID, documentType, originalName, mimeType string, size int64, src io.Reader) (*models.KYCDocument, error) {
 	// Verificar si ya existe un documento de este tipo para este usuario
 	var existingDoc models.KYCDocument
-	checkQuery := `SELECT id, file_path FROM kyc_documents WHERE user_id = $1 AND document_type = $2`
-	err := s.DB.QueryRow(checkQuery, userID, documentType).Scan(&existingDoc.ID, &existingDoc.FilePath)
+	checkQuery := `SELECT id, file_path, status FROM kyc_documents WHERE user_id = $1 AND document_type = $2`
+	err := s.DB.QueryRow(checkQuery, userID, documentType).Scan(&existingDoc.ID, &existingDoc.FilePath, &existingDoc.Status)
 
 	var shouldReplace bool
 	if err == nil {
-		// El documento existe, lo reemplazaremos
+		// El documento existe, lo reemplazaremos; el archivo anterior se elimina recién cuando el
+		// nuevo quedó analizado y registrado, para no dejar el registro apuntando a un archivo borrado
 		shouldReplace = true
-		// Eliminar el archivo anterior si existe
-		if _, err := os.Stat(existingDoc.FilePath); err == nil {
-			os.Remove(existingDoc.FilePath)
-		}
 	} else if err != sql.ErrNoRows {
 		// Error diferente a "no rows found"
 		return nil, fmt.Errorf("error verificando documento existente: %v", err)
 	}
 
 	// Generar nombre único para el archivo
-	ext := filepath.Ext(file.Filename)
-	filename := fmt.Sprintf("%s_%s_%d%s", userID.String(), documentType, time.Now().Unix(), ext)
+	ext := filepath.Ext(originalName)
+	filename := fmt.Sprintf("%s_%s_%d%s", userID.String(), documentType, time.Now().UnixNano(), ext)
 
 	// Crear directorio del usuario si no existe
 	userDir := filepath.Join(s.UploadDir, userID.String())
@@ -73,13 +135,6 @@ func (s *KYCService) UploadDocument(userID uuid.UUID, documentType string, file
 
 	filePath := filepath.Join(userDir, filename)
 
-	// Abrir archivo cargado
-	src, err := file.Open()
-	if err != nil {
-		return nil, fmt.Errorf("error abriendo archivo: %v", err)
-	}
-	defer src.Close()
-
 	// Crear archivo destino
 	dst, err := os.Create(filePath)
 	if err != nil {
@@ -91,6 +146,14 @@ func (s *KYCService) UploadDocument(userID uuid.UUID, documentType string, file
 	if _, err = io.Copy(dst, src); err != nil {
 		return nil, fmt.Errorf("error guardando archivo: %v", err)
 	}
+	dst.Close()
+
+	// Analizar el archivo antes de que quede disponible para revisión
+	status, scanSignature, filePath, err := s.scanUpload(filePath)
+	if err != nil {
+		return nil, err
+	}
+	scannedAt := time.Now()
 
 	// Crear o actualizar registro en base de datos
 	var doc *models.KYCDocument
@@ -102,22 +165,24 @@ func (s *KYCService) UploadDocument(userID uuid.UUID, documentType string, file
 			UserID:       userID,
 			DocumentType: documentType,
 			FilePath:     filePath,
-			OriginalName: file.Filename,
-			FileSize:     file.Size,
-			MimeType:     file.Header.Get("Content-Type"),
-			Status:       models.KYCStatusPending,
+			OriginalName: originalName,
+			FileSize:     size,
+			MimeType:     mimeType,
+			Status:       status,
 			UpdatedAt:    time.Now(),
 		}
 
 		query := `
 			UPDATE kyc_documents SET
 				file_path = $1, original_name = $2, file_size = $3,
-				mime_type = $4, status = $5, rejection_reason = NULL, updated_at = $6
-			WHERE id = $7
+				mime_type = $4, status = $5, rejection_reason = NULL,
+				rejection_codes = NULL, rejection_note = NULL, expires_at = NULL, purged_at = NULL,
+				scan_signature = $6, scanned_at = $7, updated_at = $8
+			WHERE id = $9
 		`
 
 		_, err = s.DB.Exec(query, doc.FilePath, doc.OriginalName, doc.FileSize,
-			doc.MimeType, doc.Status, doc.UpdatedAt, doc.ID)
+			doc.MimeType, doc.Status, scanSignature, scannedAt, doc.UpdatedAt, doc.ID)
 	} else {
 		// Crear nuevo documento
 		doc = &models.KYCDocument{
@@ -125,10 +190,10 @@ func (s *KYCService) UploadDocument(userID uuid.UUID, documentType string, file
 			UserID:       userID,
 			DocumentType: documentType,
 			FilePath:     filePath,
-			OriginalName: file.Filename,
-			FileSize:     file.Size,
-			MimeType:     file.Header.Get("Content-Type"),
-			Status:       models.KYCStatusPending,
+			OriginalName: originalName,
+			FileSize:     size,
+			MimeType:     mimeType,
+			Status:       status,
 			CreatedAt:    time.Now(),
 			UpdatedAt:    time.Now(),
 		}
@@ -136,13 +201,13 @@ func (s *KYCService) UploadDocument(userID uuid.UUID, documentType string, file
 		query := `
 			INSERT INTO kyc_documents (
 				id, user_id, document_type, file_path, original_name,
-				file_size, mime_type, status, created_at, updated_at
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+				file_size, mime_type, status, scan_signature, scanned_at, created_at, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		`
 
 		_, err = s.DB.Exec(query,
 			doc.ID, doc.UserID, doc.DocumentType, doc.FilePath, doc.OriginalName,
-			doc.FileSize, doc.MimeType, doc.Status, doc.CreatedAt, doc.UpdatedAt,
+			doc.FileSize, doc.MimeType, doc.Status, scanSignature, scannedAt, doc.CreatedAt, doc.UpdatedAt,
 		)
 	}
 
@@ -152,14 +217,106 @@ func (s *KYCService) UploadDocument(userID uuid.UUID, documentType string, file
 		return nil, fmt.Errorf("error guardando en base de datos: %v", err)
 	}
 
+	// Eliminar el archivo anterior ahora que el registro apunta al nuevo
+	if shouldReplace && existingDoc.FilePath != "" && existingDoc.FilePath != filePath {
+		os.Remove(existingDoc.FilePath)
+	}
+
+	if doc.Status == models.KYCStatusQuarantined {
+		s.emit(models.KYCEvent{
+			Type:       models.KYCEventDocumentQuarantined,
+			UserID:     userID,
+			Status:     doc.Status,
+			DocumentID: &doc.ID,
+		})
+		return doc, nil
+	}
+
+	s.emit(models.KYCEvent{
+		Type:       models.KYCEventDocumentUploaded,
+		UserID:     userID,
+		Status:     doc.Status,
+		DocumentID: &doc.ID,
+	})
+	var previousStatus *models.KYCStatus
+	if shouldReplace {
+		previousStatus = &existingDoc.Status
+	}
+	s.checkDocumentsReceived(userID, documentType, previousStatus)
+
 	return doc, nil
 }
 
+// scanUpload analiza un archivo recién guardado. Si está infectado lo mueve a cuarentena y
+// devuelve el estado quarantined con la firma detectada y la nueva ruta; si el análisis falla
+// el archivo se elimina y la subida se rechaza.
+func (s *KYCService) scanUpload(filePath string) (models.KYCStatus, *string, string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("error abriendo archivo: %v", err)
+	}
+	result, err := s.scanner.Scan(f)
+	f.Close()
+	if err != nil {
+		os.Remove(filePath)
+		fmt.Printf("Error analizando archivo %s: %v\n", filePath, err)
+		return "", nil, "", fmt.Errorf("no se pudo analizar el archivo, intenta de nuevo más tarde")
+	}
+
+	if !result.Infected {
+		return models.KYCStatusPending, nil, filePath, nil
+	}
+
+	quarantineDir := filepath.Join(s.quarantineDir, filepath.Base(filepath.Dir(filePath)))
+	if err := os.MkdirAll(quarantineDir, 0700); err != nil {
+		os.Remove(filePath)
+		return "", nil, "", fmt.Errorf("error creando directorio de cuarentena: %v", err)
+	}
+
+	quarantinePath := filepath.Join(quarantineDir, filepath.Base(filePath))
+	if err := moveFile(filePath, quarantinePath); err != nil {
+		os.Remove(filePath)
+		return "", nil, "", fmt.Errorf("error moviendo archivo a cuarentena: %v", err)
+	}
+
+	fmt.Printf("Archivo en cuarentena %s: %s\n", quarantinePath, result.Signature)
+	return models.KYCStatusQuarantined, &result.Signature, quarantinePath, nil
+}
+
+// moveFile mueve un archivo, copiándolo si origen y destino están en distintos sistemas de archivos
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(src)
+}
+
 func (s *KYCService) GetUserDocuments(userID uuid.UUID) ([]models.KYCDocument, error) {
 	query := `
 		SELECT id, user_id, document_type, file_path, original_name,
-		       file_size, mime_type, status, rejection_reason, created_at, updated_at
-		FROM kyc_documents WHERE user_id = $1 ORDER BY created_at DESC
+		       file_size, mime_type, status, rejection_reason, rejection_codes, expires_at,
+		       created_at, updated_at
+		FROM kyc_documents WHERE user_id = $1 AND purged_at IS NULL ORDER BY created_at DESC
 	`
 
 	rows, err := s.DB.Query(query, userID)
@@ -173,7 +330,8 @@ func (s *KYCService) GetUserDocuments(userID uuid.UUID) ([]models.KYCDocument, e
 		var doc models.KYCDocument
 		err := rows.Scan(
 			&doc.ID, &doc.UserID, &doc.DocumentType, &doc.FilePath, &doc.OriginalName,
-			&doc.FileSize, &doc.MimeType, &doc.Status, &doc.RejectionReason, &doc.CreatedAt, &doc.UpdatedAt,
+			&doc.FileSize, &doc.MimeType, &doc.Status, &doc.RejectionReason, pq.Array(&doc.RejectionCodes),
+			&doc.ExpiresAt, &doc.CreatedAt, &doc.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -185,55 +343,166 @@ func (s *KYCService) GetUserDocuments(userID uuid.UUID) ([]models.KYCDocument, e
 }
 
 func (s *KYCService) ApproveDocument(docID uuid.UUID, adminID uuid.UUID) error {
+	var userID uuid.UUID
+	err := s.DB.QueryRow("SELECT user_id FROM kyc_documents WHERE id = $1", docID).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("documento no encontrado")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkApprovalGuards(userID); err != nil {
+		return err
+	}
+
+	if err := markDocumentApproved(s.DB, docID); err != nil {
+		return err
+	}
+
+	// Verificar si todos los documentos del usuario están aprobados
+	return s.updateUserKYCStatus(docID)
+}
+
+func (s *KYCService) RejectDocument(docID uuid.UUID, req models.RejectDocumentRequest, adminID uuid.UUID) error {
+	var userID uuid.UUID
+	var documentType string
+	var userStatus models.KYCStatus
+	err := s.DB.QueryRow(`
+		SELECT d.user_id, d.document_type, u.kyc_status
+		FROM kyc_documents d JOIN users u ON u.id = d.user_id
+		WHERE d.id = $1
+	`, docID).Scan(&userID, &documentType, &userStatus)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("documento no encontrado")
+	}
+	if err != nil {
+		return err
+	}
+
+	req.ReasonCodes = normalizeRejectionCodes(req.ReasonCodes)
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Validar antes de componer el mensaje, para que un código desconocido sea un error del cliente
+	if err := validateRejectionCodes(tx, req.ReasonCodes); err != nil {
+		return err
+	}
+
+	// El mensaje para el usuario se compone a partir del catálogo, nunca de texto libre
+	message, err := s.RenderRejectionMessage(req.ReasonCodes, documentType, models.DefaultLanguage)
+	if err != nil {
+		return err
+	}
+
+	if err := markDocumentRejected(tx, docID, message, req, adminID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.emit(models.KYCEvent{
+		Type:       models.KYCEventDocumentRejected,
+		UserID:     userID,
+		Status:     userStatus,
+		DocumentID: &docID,
+		Reasons:    []string{message},
+	})
+
+	// Actualizar estado KYC del usuario
+	return s.recomputeUserKYCStatus(userID, userStatus)
+}
+
+// execer permite ejecutar la misma actualización dentro o fuera de una transacción
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// markDocumentApproved marca un documento pendiente como aprobado y limpia los datos de rechazo.
+// Un documento en cualquier otro estado (ej: en cuarentena) no se aprueba.
+func markDocumentApproved(db execer, docID uuid.UUID) error {
 	query := `
 		UPDATE kyc_documents 
-		SET status = $1, updated_at = $2, rejection_reason = NULL
-		WHERE id = $3
+		SET status = $1, updated_at = $2, rejection_reason = NULL,
+		    rejection_codes = NULL, rejection_note = NULL
+		WHERE id = $3 AND status = $4
 	`
 
-	_, err := s.DB.Exec(query, models.KYCStatusApproved, time.Now(), docID)
+	result, err := db.Exec(query, models.KYCStatusApproved, time.Now(), docID, models.KYCStatusPending)
 	if err != nil {
 		return err
 	}
 
-	// Verificar si todos los documentos del usuario están aprobados
-	return s.updateUserKYCStatus(docID)
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("el documento no está pendiente")
+	}
+	return nil
 }
 
-func (s *KYCService) RejectDocument(docID uuid.UUID, reason string, adminID uuid.UUID) error {
+// markDocumentRejected marca un documento pendiente como rechazado y registra los motivos aplicados.
+// Como en markDocumentApproved, un documento en cualquier otro estado no se rechaza.
+func markDocumentRejected(tx *sql.Tx, docID uuid.UUID, message string, req models.RejectDocumentRequest, adminID uuid.UUID) error {
 	query := `
 		UPDATE kyc_documents 
-		SET status = $1, rejection_reason = $2, updated_at = $3
-		WHERE id = $4
+		SET status = $1, rejection_reason = $2, rejection_codes = $3, rejection_note = $4, updated_at = $5
+		WHERE id = $6 AND status = $7
 	`
 
-	_, err := s.DB.Exec(query, models.KYCStatusRejected, reason, time.Now(), docID)
+	result, err := tx.Exec(query, models.KYCStatusRejected, message, pq.Array(req.ReasonCodes),
+		req.InternalNote, time.Now(), docID, models.KYCStatusPending)
 	if err != nil {
 		return err
 	}
 
-	// Actualizar estado KYC del usuario
-	return s.updateUserKYCStatus(docID)
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("el documento no está pendiente")
+	}
+
+	return recordRejection(tx, docID, req.ReasonCodes, adminID)
 }
 
 func (s *KYCService) updateUserKYCStatus(docID uuid.UUID) error {
-	// Obtener user_id del documento
+	// Obtener user_id del documento y el estado KYC actual del usuario
 	var userID uuid.UUID
-	err := s.DB.QueryRow("SELECT user_id FROM kyc_documents WHERE id = $1", docID).Scan(&userID)
+	var previousStatus models.KYCStatus
+	err := s.DB.QueryRow(`
+		SELECT d.user_id, u.kyc_status
+		FROM kyc_documents d JOIN users u ON u.id = d.user_id
+		WHERE d.id = $1
+	`, docID).Scan(&userID, &previousStatus)
 	if err != nil {
 		return err
 	}
 
 	// Verificar el estado de todos los documentos del usuario
+	return s.recomputeUserKYCStatus(userID, previousStatus)
+}
+
+// recomputeUserKYCStatus calcula el estado KYC del usuario a partir de sus documentos
+func (s *KYCService) recomputeUserKYCStatus(userID uuid.UUID, previousStatus models.KYCStatus) error {
+	// Los documentos opcionales (ej: comprobante de domicilio) no bloquean la aprobación
 	query := `
-		SELECT COUNT(*) as total,
-		       COUNT(CASE WHEN status = 'approved' THEN 1 END) as approved,
-		       COUNT(CASE WHEN status = 'rejected' THEN 1 END) as rejected
+		SELECT COUNT(CASE WHEN status = 'approved' AND document_type = ANY($2) THEN 1 END) as approved,
+		       COUNT(CASE WHEN status = 'rejected' THEN 1 END) as rejected,
+		       COUNT(CASE WHEN status = 'expired' THEN 1 END) as expired
 		FROM kyc_documents WHERE user_id = $1
 	`
 
-	var total, approved, rejected int
-	err = s.DB.QueryRow(query, userID).Scan(&total, &approved, &rejected)
+	var approved, rejected, expired int
+	err := s.DB.QueryRow(query, userID, pq.Array(requiredDocumentTypes)).Scan(&approved, &rejected, &expired)
+	if err != nil {
+		return err
+	}
+
+	level, levelChanged, err := s.updateUserKYCLevel(userID)
 	if err != nil {
 		return err
 	}
@@ -241,8 +510,13 @@ func (s *KYCService) updateUserKYCStatus(docID uuid.UUID) error {
 	var kycStatus models.KYCStatus
 	if rejected > 0 {
 		kycStatus = models.KYCStatusRejected
-	} else if total >= 3 && approved == total { // Requiere cédula frente, atrás y foto
+	} else if expired > 0 {
+		kycStatus = models.KYCStatusReverificationRequired
+	} else if approved == len(requiredDocumentTypes) { // Requiere cédula frente, atrás y foto
 		kycStatus = models.KYCStatusApproved
+	} else if previousStatus == models.KYCStatusReverificationRequired {
+		// Sigue en re-verificación hasta que se aprueben los documentos renovados
+		kycStatus = models.KYCStatusReverificationRequired
 	} else {
 		kycStatus = models.KYCStatusPending
 	}
@@ -250,7 +524,120 @@ func (s *KYCService) updateUserKYCStatus(docID uuid.UUID) error {
 	// Actualizar estado KYC del usuario
 	_, err = s.DB.Exec("UPDATE users SET kyc_status = $1, updated_at = $2 WHERE id = $3",
 		kycStatus, time.Now(), userID)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if kycStatus == models.KYCStatusApproved && previousStatus != models.KYCStatusApproved {
+		if _, err := s.DB.Exec("UPDATE users SET kyc_verified_at = $1 WHERE id = $2", time.Now(), userID); err != nil {
+			return err
+		}
+	}
+
+	statusNotified := false
+	if kycStatus != previousStatus {
+		statusNotified = s.emitStatusChange(userID, kycStatus)
+	}
+
+	// El aviso de aprobación o rechazo ya informa al usuario; el cambio de nivel no se avisa aparte
+	if levelChanged {
+		s.emitLevelChange(userID, level, kycStatus, statusNotified)
+	}
+
+	return nil
+}
+
+// emitStatusChange emite el evento correspondiente al nuevo estado KYC del usuario e indica si lo hizo
+func (s *KYCService) emitStatusChange(userID uuid.UUID, status models.KYCStatus) bool {
+	event := models.KYCEvent{UserID: userID, Status: status}
+
+	switch status {
+	case models.KYCStatusApproved:
+		event.Type = models.KYCEventApproved
+	case models.KYCStatusRejected:
+		event.Type = models.KYCEventRejected
+		reasons, err := s.rejectionReasonsForUser(userID)
+		if err != nil {
+			fmt.Printf("Error obteniendo motivos de rechazo del usuario %v: %v\n", userID, err)
+		}
+		event.Reasons = reasons
+	case models.KYCStatusReverificationRequired:
+		event.Type = models.KYCEventReverification
+	default:
+		return false
+	}
+
+	s.emit(event)
+	return true
+}
+
+// checkDocumentsReceived emite un evento cuando la subida de documentType completa los documentos
+// requeridos: cada tipo requerido tiene un documento pendiente o aprobado, ninguno rechazado, y
+// antes de la subida no era así (previousStatus es el estado del documento reemplazado, nil si no había)
+func (s *KYCService) checkDocumentsReceived(userID uuid.UUID, documentType string, previousStatus *models.KYCStatus) {
+	if !contains(requiredDocumentTypes, documentType) {
+		return
+	}
+
+	rows, err := s.DB.Query(`
+		SELECT document_type, status FROM kyc_documents
+		WHERE user_id = $1 AND document_type = ANY($2) AND purged_at IS NULL
+	`, userID, pq.Array(requiredDocumentTypes))
+	if err != nil {
+		fmt.Printf("Error verificando documentos del usuario %v: %v\n", userID, err)
+		return
+	}
+	defer rows.Close()
+
+	statuses := make(map[string]models.KYCStatus)
+	for rows.Next() {
+		var docType string
+		var status models.KYCStatus
+		if err := rows.Scan(&docType, &status); err != nil {
+			fmt.Printf("Error verificando documentos del usuario %v: %v\n", userID, err)
+			return
+		}
+		statuses[docType] = status
+	}
+
+	if !documentsComplete(statuses) {
+		return
+	}
+
+	// Con el estado anterior del documento subido, ¿ya estaban completos? Entonces no es una transición
+	before := make(map[string]models.KYCStatus, len(statuses))
+	for docType, status := range statuses {
+		before[docType] = status
+	}
+	delete(before, documentType)
+	if previousStatus != nil {
+		before[documentType] = *previousStatus
+	}
+	if documentsComplete(before) {
+		return
+	}
+
+	s.emit(models.KYCEvent{
+		Type:   models.KYCEventDocumentsReceived,
+		UserID: userID,
+		Status: models.KYCStatusPending,
+	})
+}
+
+// documentsComplete indica si cada tipo requerido tiene un documento pendiente o aprobado y al
+// menos uno espera revisión
+func documentsComplete(statuses map[string]models.KYCStatus) bool {
+	pending := false
+	for _, documentType := range requiredDocumentTypes {
+		switch statuses[documentType] {
+		case models.KYCStatusPending:
+			pending = true
+		case models.KYCStatusApproved:
+		default:
+			return false
+		}
+	}
+	return pending
 }
 
 func contains(slice []string, item string) bool {
@@ -277,9 +664,9 @@ func (s *KYCService) GetAllPendingDocuments() ([]models.KYCDocument, error) {
 	query := `
 		SELECT id, user_id, document_type, file_path, original_name, 
 		       file_size, mime_type, status, rejection_reason, 
-		       created_at, updated_at
+		       rejection_codes, rejection_note, expires_at, created_at, updated_at
 		FROM kyc_documents 
-		WHERE status = 'pending'
+		WHERE status = 'pending' AND purged_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -296,7 +683,7 @@ func (s *KYCService) GetAllPendingDocuments() ([]models.KYCDocument, error) {
 		err := rows.Scan(
 			&doc.ID, &doc.UserID, &doc.DocumentType, &doc.FilePath, &doc.OriginalName,
 			&doc.FileSize, &doc.MimeType, &doc.Status, &doc.RejectionReason,
-			&doc.CreatedAt, &doc.UpdatedAt,
+			pq.Array(&doc.RejectionCodes), &doc.RejectionNote, &doc.ExpiresAt, &doc.CreatedAt, &doc.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -314,6 +701,7 @@ func (s *KYCService) GetDocumentByID(docID uuid.UUID) (*models.KYCDocument, erro
 	query := `
 		SELECT d.id, d.user_id, d.document_type, d.file_path, d.original_name,
 		       d.file_size, d.mime_type, d.status, d.rejection_reason,
+		       d.rejection_codes, d.rejection_note, d.expires_at, d.purged_at, d.scan_signature,
 		       d.created_at, d.updated_at
 		FROM kyc_documents d
 		WHERE d.id = $1
@@ -322,6 +710,7 @@ func (s *KYCService) GetDocumentByID(docID uuid.UUID) (*models.KYCDocument, erro
 	err := s.DB.QueryRow(query, docID).Scan(
 		&doc.ID, &doc.UserID, &doc.DocumentType, &doc.FilePath, &doc.OriginalName,
 		&doc.FileSize, &doc.MimeType, &doc.Status, &doc.RejectionReason,
+		pq.Array(&doc.RejectionCodes), &doc.RejectionNote, &doc.ExpiresAt, &doc.PurgedAt, &doc.ScanSignature,
 		&doc.CreatedAt, &doc.UpdatedAt,
 	)
 	if err != nil {
@@ -337,9 +726,9 @@ func (s *KYCService) GetPendingDocuments() ([]models.KYCDocument, error) {
 	query := `
 		SELECT d.id, d.user_id, d.document_type, d.file_path, d.original_name,
 		       d.file_size, d.mime_type, d.status, d.rejection_reason,
-		       d.created_at, d.updated_at
+		       d.rejection_codes, d.rejection_note, d.expires_at, d.created_at, d.updated_at
 		FROM kyc_documents d
-		WHERE d.status = 'pending'
+		WHERE d.status = 'pending' AND d.purged_at IS NULL
 		ORDER BY d.created_at ASC
 	`
 
@@ -354,7 +743,7 @@ func (s *KYCService) GetPendingDocuments() ([]models.KYCDocument, error) {
 		err := rows.Scan(
 			&doc.ID, &doc.UserID, &doc.DocumentType, &doc.FilePath, &doc.OriginalName,
 			&doc.FileSize, &doc.MimeType, &doc.Status, &doc.RejectionReason,
-			&doc.CreatedAt, &doc.UpdatedAt,
+			pq.Array(&doc.RejectionCodes), &doc.RejectionNote, &doc.ExpiresAt, &doc.CreatedAt, &doc.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -365,3 +754,31 @@ func (s *KYCService) GetPendingDocuments() ([]models.KYCDocument, error) {
 
 	return documents, nil
 }
+
+// GetQuarantinedDocuments obtiene los documentos aislados por el antivirus
+func (s *KYCService) GetQuarantinedDocuments() ([]models.KYCDocument, error) {
+	rows, err := s.DB.Query(`
+		SELECT id, user_id, document_type, original_name, file_size, mime_type, status,
+		       scan_signature, created_at, updated_at
+		FROM kyc_documents
+		WHERE status = 'quarantined' AND purged_at IS NULL
+		ORDER BY updated_at DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo documentos en cuarentena: %v", err)
+	}
+	defer rows.Close()
+
+	var documents []models.KYCDocument
+	for rows.Next() {
+		var doc models.KYCDocument
+		err := rows.Scan(&doc.ID, &doc.UserID, &doc.DocumentType, &doc.OriginalName, &doc.FileSize,
+			&doc.MimeType, &doc.Status, &doc.ScanSignature, &doc.CreatedAt, &doc.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando documento en cuarentena: %v", err)
+		}
+		documents = append(documents, doc)
+	}
+
+	return documents, nil
+}
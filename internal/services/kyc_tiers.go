@@ -0,0 +1,192 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// GetTiers obtiene los niveles KYC ordenados de menor a mayor
+func (s *KYCService) GetTiers() ([]models.KYCTier, error) {
+	rows, err := s.DB.Query(`
+		SELECT level, name, description, required_document_types, updated_at
+		FROM kyc_tiers ORDER BY level ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo niveles KYC: %v", err)
+	}
+	defer rows.Close()
+
+	var tiers []models.KYCTier
+	for rows.Next() {
+		var tier models.KYCTier
+		err := rows.Scan(&tier.Level, &tier.Name, &tier.Description, pq.Array(&tier.RequiredDocumentTypes), &tier.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando nivel KYC: %v", err)
+		}
+		tiers = append(tiers, tier)
+	}
+
+	return tiers, nil
+}
+
+// UpdateTier cambia el nombre, la descripción o los documentos exigidos por un nivel
+// y recalcula el nivel de todos los usuarios
+func (s *KYCService) UpdateTier(level int, req models.UpdateKYCTierRequest) (*models.KYCTier, error) {
+	for _, documentType := range req.RequiredDocumentTypes {
+		if !contains(allowedDocumentTypes, documentType) {
+			return nil, fmt.Errorf("tipo de documento inválido: %s", documentType)
+		}
+	}
+
+	var requiredTypes interface{}
+	if req.RequiredDocumentTypes != nil {
+		requiredTypes = pq.Array(req.RequiredDocumentTypes)
+	}
+
+	var tier models.KYCTier
+	err := s.DB.QueryRow(`
+		UPDATE kyc_tiers SET
+		    name = COALESCE($1, name),
+		    description = COALESCE($2, description),
+		    required_document_types = COALESCE($3, required_document_types)
+		WHERE level = $4
+		RETURNING level, name, description, required_document_types, updated_at
+	`, req.Name, req.Description, requiredTypes, level).Scan(
+		&tier.Level, &tier.Name, &tier.Description, pq.Array(&tier.RequiredDocumentTypes), &tier.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("nivel KYC no encontrado")
+		}
+		return nil, fmt.Errorf("error actualizando nivel KYC: %v", err)
+	}
+
+	if req.RequiredDocumentTypes != nil {
+		if err := s.RecalculateKYCLevels(); err != nil {
+			return &tier, err
+		}
+	}
+
+	return &tier, nil
+}
+
+// RecalculateKYCLevels recalcula el nivel KYC de todos los usuarios
+func (s *KYCService) RecalculateKYCLevels() error {
+	rows, err := s.DB.Query("SELECT id, kyc_status FROM users WHERE role = 'user'")
+	if err != nil {
+		return fmt.Errorf("error obteniendo usuarios: %v", err)
+	}
+	defer rows.Close()
+
+	type userStatus struct {
+		id     uuid.UUID
+		status models.KYCStatus
+	}
+	var users []userStatus
+	for rows.Next() {
+		var u userStatus
+		if err := rows.Scan(&u.id, &u.status); err != nil {
+			return fmt.Errorf("error escaneando usuario: %v", err)
+		}
+		users = append(users, u)
+	}
+
+	// Un cambio en la configuración de niveles no se avisa a cada usuario
+	for _, u := range users {
+		level, changed, err := s.updateUserKYCLevel(u.id)
+		if err != nil {
+			return fmt.Errorf("error recalculando nivel KYC del usuario %v: %v", u.id, err)
+		}
+		if changed {
+			s.emitLevelChange(u.id, level, u.status, true)
+		}
+	}
+
+	return nil
+}
+
+// CurrentKYCLevel obtiene el nivel KYC guardado del usuario
+func (s *KYCService) CurrentKYCLevel(userID uuid.UUID) (int, error) {
+	var level int
+	err := s.DB.QueryRow("SELECT kyc_level FROM users WHERE id = $1", userID).Scan(&level)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error obteniendo nivel KYC: %v", err)
+	}
+	return level, nil
+}
+
+// updateUserKYCLevel guarda el nivel KYC del usuario e indica si cambió
+func (s *KYCService) updateUserKYCLevel(userID uuid.UUID) (int, bool, error) {
+	level, err := s.computeKYCLevel(userID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var previous int
+	err = s.DB.QueryRow(`
+		UPDATE users u SET kyc_level = $1
+		FROM (SELECT kyc_level FROM users WHERE id = $2) prev
+		WHERE u.id = $2
+		RETURNING prev.kyc_level
+	`, level, userID).Scan(&previous)
+	if err != nil {
+		return 0, false, fmt.Errorf("error actualizando nivel KYC: %v", err)
+	}
+
+	return level, level != previous, nil
+}
+
+// emitLevelChange emite el cambio de nivel KYC del usuario; silent evita el aviso al usuario
+func (s *KYCService) emitLevelChange(userID uuid.UUID, level int, status models.KYCStatus, silent bool) {
+	s.emit(models.KYCEvent{
+		Type:   models.KYCEventLevelChanged,
+		UserID: userID,
+		Status: status,
+		Level:  &level,
+		Silent: silent,
+	})
+}
+
+// computeKYCLevel obtiene el nivel más alto cuyos documentos, y los de todos los niveles anteriores,
+// están aprobados
+func (s *KYCService) computeKYCLevel(userID uuid.UUID) (int, error) {
+	tiers, err := s.GetTiers()
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := s.DB.Query("SELECT document_type FROM kyc_documents WHERE user_id = $1 AND status = 'approved'", userID)
+	if err != nil {
+		return 0, fmt.Errorf("error obteniendo documentos aprobados: %v", err)
+	}
+	defer rows.Close()
+
+	approved := make(map[string]bool)
+	for rows.Next() {
+		var documentType string
+		if err := rows.Scan(&documentType); err != nil {
+			return 0, fmt.Errorf("error escaneando documento: %v", err)
+		}
+		approved[documentType] = true
+	}
+
+	level := 0
+	for _, tier := range tiers {
+		for _, documentType := range tier.RequiredDocumentTypes {
+			if !approved[documentType] {
+				return level, nil
+			}
+		}
+		level = tier.Level
+	}
+
+	return level, nil
+}
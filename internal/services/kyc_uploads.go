@@ -0,0 +1,282 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// SetUploadSessions configura el directorio de las cargas parciales (no debe servirse públicamente)
+// y el tiempo sin actividad tras el cual una carga incompleta se descarta
+func (s *KYCService) SetUploadSessions(partialDir string, ttl time.Duration) {
+	s.partialDir = partialDir
+	s.uploadSessionTTL = ttl
+}
+
+// CreateUploadSession abre una carga reanudable para un documento del tamaño indicado
+func (s *KYCService) CreateUploadSession(userID uuid.UUID, documentType, originalName, mimeType string, length int64) (*models.KYCUploadSession, error) {
+	if length <= 0 {
+		return nil, fmt.Errorf("tamaño de archivo inválido")
+	}
+	if err := validateUpload(documentType, originalName, mimeType, length); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(s.partialDir, 0700); err != nil {
+		return nil, fmt.Errorf("error creando directorio: %v", err)
+	}
+
+	now := time.Now()
+	session := &models.KYCUploadSession{
+		ID:           uuid.New(),
+		UserID:       userID,
+		DocumentType: documentType,
+		OriginalName: originalName,
+		MimeType:     mimeType,
+		Length:       length,
+		ExpiresAt:    now.Add(s.uploadSessionTTL),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	session.FilePath = filepath.Join(s.partialDir, session.ID.String())
+
+	f, err := os.OpenFile(session.FilePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("error creando archivo: %v", err)
+	}
+	f.Close()
+
+	_, err = s.DB.Exec(`
+		INSERT INTO kyc_upload_sessions (
+			id, user_id, document_type, original_name, mime_type,
+			upload_length, file_path, expires_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, session.ID, session.UserID, session.DocumentType, session.OriginalName, session.MimeType,
+		session.Length, session.FilePath, session.ExpiresAt, session.CreatedAt, session.UpdatedAt)
+	if err != nil {
+		os.Remove(session.FilePath)
+		return nil, fmt.Errorf("error guardando sesión de carga: %v", err)
+	}
+
+	return session, nil
+}
+
+// GetUploadSession obtiene una carga del usuario; las cargas incompletas vencidas ya no son accesibles
+func (s *KYCService) GetUploadSession(userID, sessionID uuid.UUID) (*models.KYCUploadSession, error) {
+	var session models.KYCUploadSession
+	err := s.DB.QueryRow(`
+		SELECT id, user_id, document_type, original_name, mime_type, upload_length, upload_offset,
+		       file_path, document_id, expires_at, completed_at, created_at, updated_at
+		FROM kyc_upload_sessions WHERE id = $1 AND user_id = $2
+	`, sessionID, userID).Scan(
+		&session.ID, &session.UserID, &session.DocumentType, &session.OriginalName, &session.MimeType,
+		&session.Length, &session.Offset, &session.FilePath, &session.DocumentID, &session.ExpiresAt,
+		&session.CompletedAt, &session.CreatedAt, &session.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("sesión de carga no encontrada")
+		}
+		return nil, fmt.Errorf("error obteniendo sesión de carga: %v", err)
+	}
+
+	if session.CompletedAt == nil && time.Now().After(session.ExpiresAt) {
+		return nil, fmt.Errorf("sesión de carga vencida")
+	}
+
+	return &session, nil
+}
+
+// AppendUploadChunk escribe una parte del archivo a partir de offset, que debe coincidir con lo ya
+// recibido. Si la conexión se corta se conserva lo escrito para reanudar desde ahí. Al recibir el
+// último byte el archivo se registra como documento KYC; si el registro falla (ej: antivirus no
+// disponible) puede reintentarse enviando una parte vacía en el offset final.
+func (s *KYCService) AppendUploadChunk(userID, sessionID uuid.UUID, offset int64, chunk io.Reader) (*models.KYCUploadSession, *models.KYCDocument, error) {
+	if !s.lockUpload(sessionID) {
+		return nil, nil, fmt.Errorf("carga en curso")
+	}
+	defer s.unlockUpload(sessionID)
+
+	session, err := s.GetUploadSession(userID, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if offset != session.Offset {
+		return session, nil, fmt.Errorf("offset no coincide: se esperaba %d", session.Offset)
+	}
+
+	// Reintento de una parte ya confirmada cuya respuesta no llegó al cliente
+	if session.CompletedAt != nil {
+		return session, nil, nil
+	}
+
+	if offset < session.Length {
+		written, copyErr := s.writeUploadChunk(session, chunk)
+		session.Offset += written
+		session.ExpiresAt = time.Now().Add(s.uploadSessionTTL)
+
+		_, err = s.DB.Exec("UPDATE kyc_upload_sessions SET upload_offset = $1, expires_at = $2 WHERE id = $3",
+			session.Offset, session.ExpiresAt, session.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error actualizando sesión de carga: %v", err)
+		}
+		if copyErr != nil {
+			return session, nil, fmt.Errorf("carga interrumpida: %v", copyErr)
+		}
+	}
+
+	if session.Offset < session.Length {
+		return session, nil, nil
+	}
+
+	doc, err := s.completeUpload(session)
+	if err != nil {
+		return session, nil, err
+	}
+	return session, doc, nil
+}
+
+// writeUploadChunk agrega la parte al archivo parcial y devuelve los bytes escritos, aun si la lectura falla
+func (s *KYCService) writeUploadChunk(session *models.KYCUploadSession, chunk io.Reader) (int64, error) {
+	f, err := os.OpenFile(session.FilePath, os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("error abriendo archivo: %v", err)
+	}
+	defer f.Close()
+
+	// Descartar bytes de una escritura anterior que no llegó a confirmarse
+	if err := f.Truncate(session.Offset); err != nil {
+		return 0, err
+	}
+	if _, err := f.Seek(session.Offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	written, copyErr := io.Copy(f, io.LimitReader(chunk, session.Length-session.Offset))
+	if err := f.Sync(); err != nil && copyErr == nil {
+		copyErr = err
+	}
+	return written, copyErr
+}
+
+// completeUpload registra el archivo completo como documento KYC y cierra la sesión
+func (s *KYCService) completeUpload(session *models.KYCUploadSession) (*models.KYCDocument, error) {
+	f, err := os.Open(session.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo archivo: %v", err)
+	}
+	doc, err := s.storeDocument(session.UserID, session.DocumentType, session.OriginalName, session.MimeType, session.Length, f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session.CompletedAt = &now
+	session.DocumentID = &doc.ID
+	_, err = s.DB.Exec("UPDATE kyc_upload_sessions SET completed_at = $1, document_id = $2 WHERE id = $3",
+		now, doc.ID, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error actualizando sesión de carga: %v", err)
+	}
+
+	os.Remove(session.FilePath)
+	return doc, nil
+}
+
+// CancelUploadSession descarta una carga incompleta y su archivo parcial
+func (s *KYCService) CancelUploadSession(userID, sessionID uuid.UUID) error {
+	if !s.lockUpload(sessionID) {
+		return fmt.Errorf("carga en curso")
+	}
+	defer s.unlockUpload(sessionID)
+
+	session, err := s.GetUploadSession(userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.CompletedAt != nil {
+		return fmt.Errorf("la carga ya fue completada")
+	}
+
+	if _, err := s.DB.Exec("DELETE FROM kyc_upload_sessions WHERE id = $1", session.ID); err != nil {
+		return fmt.Errorf("error eliminando sesión de carga: %v", err)
+	}
+	os.Remove(session.FilePath)
+
+	return nil
+}
+
+// RunUploadSessionCleanup elimina las sesiones de carga vencidas y los archivos parciales abandonados
+func (s *KYCService) RunUploadSessionCleanup() error {
+	rows, err := s.DB.Query(`
+		SELECT id, file_path, completed_at IS NOT NULL
+		FROM kyc_upload_sessions WHERE expires_at < NOW()
+	`)
+	if err != nil {
+		return fmt.Errorf("error obteniendo sesiones de carga vencidas: %v", err)
+	}
+
+	type expiredSession struct {
+		id        uuid.UUID
+		filePath  string
+		completed bool
+	}
+	var expired []expiredSession
+	for rows.Next() {
+		var e expiredSession
+		if err := rows.Scan(&e.id, &e.filePath, &e.completed); err != nil {
+			rows.Close()
+			return fmt.Errorf("error escaneando sesión de carga: %v", err)
+		}
+		expired = append(expired, e)
+	}
+	rows.Close()
+
+	removed := 0
+	for _, e := range expired {
+		if !s.lockUpload(e.id) {
+			continue
+		}
+		_, err := s.DB.Exec("DELETE FROM kyc_upload_sessions WHERE id = $1 AND expires_at < NOW()", e.id)
+		if err == nil && !e.completed {
+			os.Remove(e.filePath)
+		}
+		s.unlockUpload(e.id)
+		if err != nil {
+			return fmt.Errorf("error eliminando sesión de carga: %v", err)
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		fmt.Printf("Sesiones de carga vencidas eliminadas: %d\n", removed)
+	}
+	return nil
+}
+
+// lockUpload evita que dos peticiones escriban la misma carga a la vez
+func (s *KYCService) lockUpload(sessionID uuid.UUID) bool {
+	s.uploadsMu.Lock()
+	defer s.uploadsMu.Unlock()
+
+	if s.activeUploads[sessionID] {
+		return false
+	}
+	s.activeUploads[sessionID] = true
+	return true
+}
+
+func (s *KYCService) unlockUpload(sessionID uuid.UUID) {
+	s.uploadsMu.Lock()
+	defer s.uploadsMu.Unlock()
+
+	delete(s.activeUploads, sessionID)
+}
@@ -0,0 +1,447 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// renderMarkdown convierte Markdown a HTML. Soporta encabezados, párrafos, énfasis, tachado,
+// código (en línea y bloques ```), citas, listas (anidadas), enlaces, imágenes y separadores.
+// El HTML escrito dentro del Markdown se escapa: se muestra como texto, nunca se interpreta.
+func renderMarkdown(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\t", "    ")
+
+	var b strings.Builder
+	renderBlocks(&b, strings.Split(source, "\n"))
+	return strings.TrimSpace(b.String())
+}
+
+var (
+	mdHeading   = regexp.MustCompile(`^(#{1,6})[ ]+(.*?)[ #]*$`)
+	mdRule      = regexp.MustCompile(`^ {0,3}(?:(?:- *){3,}|(?:\* *){3,}|(?:_ *){3,})$`)
+	mdFence     = regexp.MustCompile("^ {0,3}```[ ]*([A-Za-z0-9_+-]*)[ ]*$")
+	mdBullet    = regexp.MustCompile(`^( {0,3})[-*+][ ]+(.*)$`)
+	mdOrdered   = regexp.MustCompile(`^( {0,3})(\d{1,9})[.)][ ]+(.*)$`)
+	mdQuote     = regexp.MustCompile(`^ {0,3}> ?(.*)$`)
+	mdHardBreak = regexp.MustCompile(`( {2,}|\\)$`)
+	mdLinkDest  = regexp.MustCompile(`^\(\s*<?([^\s<>()]*)>?(?:\s+"([^"]*)")?\s*\)`)
+	mdAutolink  = regexp.MustCompile(`^<((?:https?://|mailto:)[^\s<>]+)>`)
+)
+
+const (
+	// mdEscapable caracteres que se pueden escapar con "\"
+	mdEscapable = "\\`*_{}[]()#+-.!~>|"
+	// mdMaxNesting profundidad máxima de citas, listas y énfasis anidados
+	mdMaxNesting = 16
+	// mdListIndent indentación mínima de las líneas que continúan un ítem de lista
+	mdListIndent = 2
+	// mdCodeIndent indentación máxima considerada al continuar un ítem tras una línea en blanco
+	mdCodeIndent = 4
+)
+
+func renderBlocks(b *strings.Builder, lines []string) {
+	renderBlocksDepth(b, lines, 0)
+}
+
+func renderBlocksDepth(b *strings.Builder, lines []string, depth int) {
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>")
+			b.WriteString(renderInlineLines(paragraph))
+			b.WriteString("</p>\n")
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		if m := mdFence.FindStringSubmatch(line); m != nil {
+			flush()
+			var code []string
+			for i++; i < len(lines) && !mdFence.MatchString(lines[i]); i++ {
+				code = append(code, lines[i])
+			}
+			b.WriteString("<pre><code")
+			if m[1] != "" {
+				fmt.Fprintf(b, ` class="language-%s"`, html.EscapeString(m[1]))
+			}
+			b.WriteString(">")
+			b.WriteString(html.EscapeString(strings.Join(code, "\n")))
+			b.WriteString("</code></pre>\n")
+			continue
+		}
+
+		if m := mdHeading.FindStringSubmatch(line); m != nil {
+			flush()
+			level := len(m[1])
+			fmt.Fprintf(b, "<h%d>%s</h%d>\n", level, renderInline(m[2]), level)
+			continue
+		}
+
+		if mdRule.MatchString(line) {
+			flush()
+			b.WriteString("<hr>\n")
+			continue
+		}
+
+		if mdQuote.MatchString(line) && depth < mdMaxNesting {
+			flush()
+			var quoted []string
+			for ; i < len(lines); i++ {
+				m := mdQuote.FindStringSubmatch(lines[i])
+				if m == nil {
+					// Continuación perezosa: una línea de texto sigue dentro de la cita
+					if strings.TrimSpace(lines[i]) == "" || len(quoted) == 0 || isBlockStart(lines[i]) {
+						break
+					}
+					quoted = append(quoted, lines[i])
+					continue
+				}
+				quoted = append(quoted, m[1])
+			}
+			i--
+			b.WriteString("<blockquote>\n")
+			renderBlocksDepth(b, quoted, depth+1)
+			b.WriteString("</blockquote>\n")
+			continue
+		}
+
+		if (mdBullet.MatchString(line) || mdOrdered.MatchString(line)) && depth < mdMaxNesting {
+			flush()
+			i = renderList(b, lines, i, depth) - 1
+			continue
+		}
+
+		paragraph = append(paragraph, strings.TrimLeft(line, " "))
+	}
+	flush()
+}
+
+// isBlockStart indica si la línea abre un bloque que interrumpe un párrafo
+func isBlockStart(line string) bool {
+	return mdHeading.MatchString(line) || mdRule.MatchString(line) || mdFence.MatchString(line) ||
+		mdQuote.MatchString(line) || mdBullet.MatchString(line) || mdOrdered.MatchString(line)
+}
+
+// renderList escribe la lista que empieza en lines[start] y devuelve el índice de la primera línea posterior
+func renderList(b *strings.Builder, lines []string, start, depth int) int {
+	ordered := mdOrdered.MatchString(lines[start])
+	itemMarker := func(line string) (content string, indent int, ok bool) {
+		if ordered {
+			if m := mdOrdered.FindStringSubmatch(line); m != nil {
+				return m[3], len(line) - len(m[3]), true
+			}
+			return "", 0, false
+		}
+		if m := mdBullet.FindStringSubmatch(line); m != nil {
+			return m[2], len(line) - len(m[2]), true
+		}
+		return "", 0, false
+	}
+
+	if ordered {
+		m := mdOrdered.FindStringSubmatch(lines[start])
+		if m[2] != "1" {
+			fmt.Fprintf(b, "<ol start=\"%s\">\n", strings.TrimLeft(m[2], "0"))
+		} else {
+			b.WriteString("<ol>\n")
+		}
+	} else {
+		b.WriteString("<ul>\n")
+	}
+
+	i := start
+	loose := false
+	var items [][]string
+	for i < len(lines) {
+		content, indent, ok := itemMarker(lines[i])
+		if !ok {
+			break
+		}
+		item := []string{content}
+		i++
+
+		// Líneas del ítem: indentadas (sublistas, párrafos) o continuación perezosa del texto
+		for i < len(lines) {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				// Un blanco seguido de contenido indentado sigue en el ítem y lo vuelve "suelto"
+				if i+1 < len(lines) && leadingSpaces(lines[i+1]) >= min(indent, mdCodeIndent) && strings.TrimSpace(lines[i+1]) != "" {
+					item = append(item, "")
+					loose = true
+					i++
+					continue
+				}
+				break
+			}
+			if spaces := leadingSpaces(line); spaces >= mdListIndent {
+				item = append(item, line[min(spaces, indent):])
+				i++
+				continue
+			}
+			if isBlockStart(line) {
+				break
+			}
+			item = append(item, line)
+			i++
+		}
+		items = append(items, item)
+
+		// Un blanco entre ítems de la misma lista también la vuelve suelta
+		if i+1 < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			if _, _, next := itemMarker(lines[i+1]); next {
+				loose = true
+				i++
+			}
+		}
+	}
+
+	for _, item := range items {
+		b.WriteString("<li>")
+		if !loose && !hasNestedBlock(item) {
+			b.WriteString(renderInlineLines(item))
+		} else if !loose {
+			// Lista compacta con sublista: el texto inicial va sin <p>
+			split := 1
+			for split < len(item) && !isBlockStart(item[split]) {
+				split++
+			}
+			b.WriteString(renderInlineLines(item[:split]))
+			b.WriteString("\n")
+			renderBlocksDepth(b, item[split:], depth+1)
+		} else {
+			b.WriteString("\n")
+			renderBlocksDepth(b, item, depth+1)
+		}
+		b.WriteString("</li>\n")
+	}
+
+	if ordered {
+		b.WriteString("</ol>\n")
+	} else {
+		b.WriteString("</ul>\n")
+	}
+	return i
+}
+
+func hasNestedBlock(item []string) bool {
+	for _, line := range item[1:] {
+		if isBlockStart(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// renderInlineLines une las líneas de un párrafo respetando los saltos forzados (dos espacios o \ al final)
+func renderInlineLines(lines []string) string {
+	var b strings.Builder
+	for i, line := range lines {
+		line = strings.TrimLeft(line, " ")
+		hardBreak := i < len(lines)-1 && mdHardBreak.MatchString(line)
+		if i < len(lines)-1 {
+			line = mdHardBreak.ReplaceAllString(line, "")
+		}
+		b.WriteString(renderInline(strings.TrimRight(line, " ")))
+		if i < len(lines)-1 {
+			if hardBreak {
+				b.WriteString("<br>")
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// renderInline convierte los elementos en línea: código, enlaces, imágenes, énfasis y tachado
+func renderInline(text string) string {
+	var b strings.Builder
+	renderInlineDepth(&b, text, 0)
+	return b.String()
+}
+
+func renderInlineDepth(b *strings.Builder, text string, depth int) {
+	for i := 0; i < len(text); {
+		c := text[i]
+		rest := text[i:]
+
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte(mdEscapable, text[i+1]) >= 0:
+			b.WriteString(html.EscapeString(text[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			ticks := len(rest) - len(strings.TrimLeft(rest, "`"))
+			fence := rest[:ticks]
+			if end := strings.Index(rest[ticks:], fence); end >= 0 {
+				code := strings.TrimSpace(rest[ticks : ticks+end])
+				b.WriteString("<code>")
+				b.WriteString(html.EscapeString(code))
+				b.WriteString("</code>")
+				i += ticks + end + ticks
+				continue
+			}
+			b.WriteString(fence)
+			i += ticks
+			continue
+
+		case c == '!' && strings.HasPrefix(rest, "!["):
+			if label, dest, title, n, ok := parseLink(rest[1:]); ok {
+				fmt.Fprintf(b, `<img src="%s" alt="%s"`, html.EscapeString(dest), html.EscapeString(plainInline(label)))
+				if title != "" {
+					fmt.Fprintf(b, ` title="%s"`, html.EscapeString(title))
+				}
+				b.WriteString(">")
+				i += 1 + n
+				continue
+			}
+
+		case c == '[' && depth < mdMaxNesting:
+			if label, dest, title, n, ok := parseLink(rest); ok {
+				fmt.Fprintf(b, `<a href="%s"`, html.EscapeString(dest))
+				if title != "" {
+					fmt.Fprintf(b, ` title="%s"`, html.EscapeString(title))
+				}
+				b.WriteString(">")
+				renderInlineDepth(b, label, depth+1)
+				b.WriteString("</a>")
+				i += n
+				continue
+			}
+
+		case c == '<':
+			if m := mdAutolink.FindStringSubmatch(rest); m != nil {
+				fmt.Fprintf(b, `<a href="%s">%s</a>`, html.EscapeString(m[1]), html.EscapeString(strings.TrimPrefix(m[1], "mailto:")))
+				i += len(m[0])
+				continue
+			}
+
+		case (c == '*' || c == '_' || c == '~') && depth < mdMaxNesting:
+			if n := emphasis(b, text, i, depth); n > 0 {
+				i += n
+				continue
+			}
+		}
+
+		_, size := utf8.DecodeRuneInString(rest)
+		b.WriteString(html.EscapeString(rest[:size]))
+		i += size
+	}
+}
+
+// emphasis intenta renderizar **fuerte**, *énfasis* o ~~tachado~~ en text[i:] y devuelve los bytes consumidos
+func emphasis(b *strings.Builder, text string, i, depth int) int {
+	c := text[i]
+	run := 1
+	for i+run < len(text) && text[i+run] == c && run < 3 {
+		run++
+	}
+	if c == '~' && run != 2 {
+		return 0
+	}
+	// El delimitador de apertura debe preceder texto, y "_" no marca énfasis dentro de palabras
+	if i+run >= len(text) || text[i+run] == ' ' {
+		return 0
+	}
+	if c == '_' && i > 0 && isWordByte(text[i-1]) {
+		return 0
+	}
+
+	delim := text[i : i+run]
+	body := text[i+run:]
+	for search := 0; search < len(body); {
+		end := strings.Index(body[search:], delim)
+		if end < 0 {
+			return 0
+		}
+		end += search
+		closeOK := end > 0 && body[end-1] != ' '
+		if c == '_' && end+run < len(body) && isWordByte(body[end+run]) {
+			closeOK = false
+		}
+		// No cerrar dentro de un delimitador más largo (ej: "*" dentro de "**")
+		if end+run < len(body) && body[end+run] == c {
+			closeOK = false
+		}
+		if !closeOK {
+			search = end + 1
+			continue
+		}
+
+		inner := body[:end]
+		var open, close string
+		switch {
+		case c == '~':
+			open, close = "<del>", "</del>"
+		case run == 1:
+			open, close = "<em>", "</em>"
+		case run == 2:
+			open, close = "<strong>", "</strong>"
+		default:
+			open, close = "<em><strong>", "</strong></em>"
+		}
+		b.WriteString(open)
+		renderInlineDepth(b, inner, depth+1)
+		b.WriteString(close)
+		return run + end + run
+	}
+	return 0
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// parseLink reconoce [texto](destino "título") al inicio de text
+func parseLink(text string) (label, dest, title string, n int, ok bool) {
+	if !strings.HasPrefix(text, "[") {
+		return
+	}
+	level := 0
+	closeIdx := -1
+	for j := 0; j < len(text); j++ {
+		switch text[j] {
+		case '\\':
+			j++
+		case '[':
+			level++
+		case ']':
+			level--
+			if level == 0 {
+				closeIdx = j
+			}
+		}
+		if closeIdx >= 0 {
+			break
+		}
+	}
+	if closeIdx < 0 {
+		return
+	}
+	m := mdLinkDest.FindStringSubmatch(text[closeIdx+1:])
+	if m == nil {
+		return
+	}
+	return text[1:closeIdx], m[1], m[2], closeIdx + 1 + len(m[0]), true
+}
+
+// plainInline texto de un fragmento en línea sin marcas (para el alt de imágenes)
+func plainInline(text string) string {
+	return htmlToText(renderInline(text))
+}
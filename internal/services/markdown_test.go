@@ -0,0 +1,85 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name, source, want string
+	}{
+		{"párrafo", "hola mundo", "<p>hola mundo</p>"},
+		{"encabezado", "## Título ##", "<h2>Título</h2>"},
+		{"énfasis", "**fuerte** y *suave* y ~~tachado~~", "<p><strong>fuerte</strong> y <em>suave</em> y <del>tachado</del></p>"},
+		{"guion bajo dentro de palabra", "snake_case_name", "<p>snake_case_name</p>"},
+		{"código en línea", "usar `a < b`", "<p>usar <code>a &lt; b</code></p>"},
+		{"bloque de código", "```go\nx := <-ch\n```", "<pre><code class=\"language-go\">x := &lt;-ch</code></pre>"},
+		{"enlace", `[sitio](https://example.com "Título")`, `<p><a href="https://example.com" title="Título">sitio</a></p>`},
+		{"imagen", "![logo *TO*](/img/logo.png)", `<p><img src="/img/logo.png" alt="logo TO"></p>`},
+		{"autoenlace", "<mailto:hola@example.com>", `<p><a href="mailto:hola@example.com">hola@example.com</a></p>`},
+		{"lista", "- uno\n- dos", "<ul>\n<li>uno</li>\n<li>dos</li>\n</ul>"},
+		{"lista ordenada", "3. tres\n4. cuatro", "<ol start=\"3\">\n<li>tres</li>\n<li>cuatro</li>\n</ol>"},
+		{"cita", "> citado", "<blockquote>\n<p>citado</p>\n</blockquote>"},
+		{"salto forzado", "línea  \nsiguiente", "<p>línea<br>\nsiguiente</p>"},
+		{"escape", `\*no es énfasis\*`, "<p>*no es énfasis*</p>"},
+	}
+	for _, tt := range tests {
+		if got := renderMarkdown(tt.source); got != tt.want {
+			t.Errorf("%s: renderMarkdown(%q) = %q, se esperaba %q", tt.name, tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestRenderMarkdownEscapesRawHTML(t *testing.T) {
+	tests := []struct {
+		source, want string
+	}{
+		{"<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{`<img src=x onerror="alert(1)">`, "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>"},
+		{"# <b>título</b>", "<h1>&lt;b&gt;título&lt;/b&gt;</h1>"},
+		{`[x](https://example.com"onmouseover="alert)`, `<p><a href="https://example.com&#34;onmouseover=&#34;alert">x</a></p>`},
+	}
+	for _, tt := range tests {
+		if got := renderMarkdown(tt.source); got != tt.want {
+			t.Errorf("renderMarkdown(%q) = %q, se esperaba %q", tt.source, got, tt.want)
+		}
+	}
+}
+
+// El contenido de las noticias pasa por Markdown y luego por el sanitizador
+func TestRenderNewsContentRejectsDangerousURLs(t *testing.T) {
+	tests := []struct {
+		source, want string
+	}{
+		{"[clic](javascript:alert%281%29)", "<p><a>clic</a></p>"},
+		{"[clic](JavaScript:void)", "<p><a>clic</a></p>"},
+		{"[clic](%6Aavascript:void)", "<p><a>clic</a></p>"},
+		{"[clic](data:text/html;base64,PHNjcmlwdD4=)", "<p><a>clic</a></p>"},
+		{"[clic](vbscript:msgbox)", "<p><a>clic</a></p>"},
+		{"![img](javascript:alert%281%29)", "<p></p>"},
+		{"![img](data:image/svg+xml;base64,PHN2Zz4=)", "<p></p>"},
+		{"<javascript:alert(1)>", "<p>&lt;javascript:alert(1)&gt;</p>"},
+	}
+	for _, tt := range tests {
+		if got := renderNewsContent(tt.source); got != tt.want {
+			t.Errorf("renderNewsContent(%q) = %q, se esperaba %q", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestRenderNewsContentKeepsSafeLinks(t *testing.T) {
+	got := renderNewsContent("[sitio](https://example.com) y [relativo](/noticias/1)")
+	want := `<p><a href="https://example.com" rel="nofollow noopener noreferrer">sitio</a> y <a href="/noticias/1" rel="nofollow noopener noreferrer">relativo</a></p>`
+	if got != want {
+		t.Errorf("renderNewsContent = %q, se esperaba %q", got, want)
+	}
+}
+
+func TestRenderMarkdownDeepNesting(t *testing.T) {
+	// Anidamiento excesivo no debe recursar sin límite
+	source := strings.Repeat(">", 10000) + " x\n" + strings.Repeat("[", 10000) + "x" + strings.Repeat("*", 10000)
+	if got := renderMarkdown(source); got == "" {
+		t.Error("se esperaba contenido renderizado")
+	}
+}
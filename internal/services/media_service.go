@@ -0,0 +1,273 @@
+package services
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// maxMediaSize tamaño máximo de una imagen subida a la biblioteca (10MB)
+const maxMediaSize = 10 * 1024 * 1024
+
+// maxMediaPixels resolución máxima aceptada, para no descomprimir imágenes gigantes en memoria
+const maxMediaPixels = 40_000_000
+
+// mediaJPEGQuality calidad de las versiones guardadas como JPEG
+const mediaJPEGQuality = 85
+
+// mediaRenditionWidths anchos de las versiones generadas; solo se generan las menores que el original
+var mediaRenditionWidths = []struct {
+	Name  string
+	Width int
+}{
+	{"large", 1600},
+	{"medium", 800},
+	{"thumbnail", 320},
+}
+
+// allowedMediaTypes tipos de imagen aceptados, detectados por contenido y no por la cabecera del cliente
+var allowedMediaTypes = []string{"image/jpeg", "image/png", "image/gif"}
+
+type MediaService struct {
+	DB      *sql.DB
+	Dir     string // Directorio donde se guardan las imágenes, servido públicamente
+	BaseURL string // URL pública de Dir (ruta relativa o CDN)
+}
+
+func NewMediaService(db *sql.DB, dir, baseURL string) *MediaService {
+	return &MediaService{
+		DB:      db,
+		Dir:     dir,
+		BaseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// UploadMedia guarda una imagen en la biblioteca junto con sus versiones redimensionadas.
+// Todas las versiones, incluida la original, se vuelven a codificar para descartar metadatos (EXIF/GPS).
+func (s *MediaService) UploadMedia(file *multipart.FileHeader, altText *string, adminID uuid.UUID) (*models.Media, error) {
+	if file.Size > maxMediaSize {
+		return nil, fmt.Errorf("archivo demasiado grande. Máximo 10MB")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo archivo: %v", err)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, maxMediaSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo archivo: %v", err)
+	}
+	if len(data) > maxMediaSize {
+		return nil, fmt.Errorf("archivo demasiado grande. Máximo 10MB")
+	}
+
+	if !contains(allowedMediaTypes, http.DetectContentType(data)) {
+		return nil, fmt.Errorf("tipo de archivo no permitido. Solo se permiten imágenes JPG, PNG, GIF")
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("imagen inválida: %v", err)
+	}
+	if config.Width*config.Height > maxMediaPixels {
+		return nil, fmt.Errorf("imagen demasiado grande. Máximo %d megapíxeles", maxMediaPixels/1_000_000)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("imagen inválida: %v", err)
+	}
+
+	media := models.Media{
+		ID:           uuid.New(),
+		OriginalName: filepath.Base(file.Filename),
+		FileSize:     int64(len(data)),
+		Width:        config.Width,
+		Height:       config.Height,
+		AltText:      altText,
+		Renditions:   make(map[string]models.MediaRendition),
+		UploadedBy:   &adminID,
+	}
+
+	dir := filepath.Join(s.Dir, media.ID.String())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creando directorio: %v", err)
+	}
+
+	original := resizeImage(img, config.Width)
+	media.MimeType, err = s.writeRendition(&media, "original", original)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	for _, rendition := range mediaRenditionWidths {
+		if rendition.Width >= config.Width {
+			continue
+		}
+		if _, err := s.writeRendition(&media, rendition.Name, resizeImage(original, rendition.Width)); err != nil {
+			os.RemoveAll(dir)
+			return nil, err
+		}
+	}
+
+	renditions, err := json.Marshal(media.Renditions)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("error serializando versiones: %v", err)
+	}
+
+	err = s.DB.QueryRow(`
+		INSERT INTO media (id, original_name, mime_type, file_size, width, height, alt_text, renditions, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at
+	`, media.ID, media.OriginalName, media.MimeType, media.FileSize, media.Width, media.Height,
+		media.AltText, renditions, media.UploadedBy).Scan(&media.CreatedAt)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("error guardando imagen: %v", err)
+	}
+
+	return &media, nil
+}
+
+// writeRendition codifica una versión como JPEG, o como PNG si tiene transparencia,
+// y la registra en media.Renditions. Devuelve el tipo MIME usado.
+func (s *MediaService) writeRendition(media *models.Media, name string, img *image.RGBA) (string, error) {
+	mimeType, ext := "image/jpeg", ".jpg"
+	if !isOpaque(img) {
+		mimeType, ext = "image/png", ".png"
+	}
+
+	filename := name + ext
+	dst, err := os.Create(filepath.Join(s.Dir, media.ID.String(), filename))
+	if err != nil {
+		return "", fmt.Errorf("error creando archivo: %v", err)
+	}
+	defer dst.Close()
+
+	if mimeType == "image/png" {
+		err = png.Encode(dst, img)
+	} else {
+		err = jpeg.Encode(dst, img, &jpeg.Options{Quality: mediaJPEGQuality})
+	}
+	if err != nil {
+		return "", fmt.Errorf("error codificando imagen: %v", err)
+	}
+
+	media.Renditions[name] = models.MediaRendition{
+		URL:    fmt.Sprintf("%s/%s/%s", s.BaseURL, media.ID, filename),
+		Width:  img.Bounds().Dx(),
+		Height: img.Bounds().Dy(),
+	}
+	return mimeType, nil
+}
+
+// mediaColumns columnas de media en el orden que espera scanMedia
+const mediaColumns = "id, original_name, mime_type, file_size, width, height, alt_text, renditions, uploaded_by, created_at"
+
+func scanMedia(row rowScanner, media *models.Media) error {
+	var renditions []byte
+	err := row.Scan(&media.ID, &media.OriginalName, &media.MimeType, &media.FileSize, &media.Width, &media.Height,
+		&media.AltText, &renditions, &media.UploadedBy, &media.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(renditions, &media.Renditions)
+}
+
+// GetMedia obtiene la biblioteca de imágenes con paginación, de la más reciente a la más antigua
+func (s *MediaService) GetMedia(page, limit int) ([]models.Media, int, error) {
+	offset := (page - 1) * limit
+
+	var total int
+	if err := s.DB.QueryRow("SELECT COUNT(*) FROM media").Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error contando imágenes: %v", err)
+	}
+
+	rows, err := s.DB.Query(`
+		SELECT `+mediaColumns+`
+		FROM media
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error obteniendo imágenes: %v", err)
+	}
+	defer rows.Close()
+
+	var items []models.Media
+	for rows.Next() {
+		var media models.Media
+		if err := scanMedia(rows, &media); err != nil {
+			return nil, 0, fmt.Errorf("error escaneando imagen: %v", err)
+		}
+		items = append(items, media)
+	}
+
+	return items, total, nil
+}
+
+// GetMediaByID obtiene una imagen de la biblioteca
+func (s *MediaService) GetMediaByID(id uuid.UUID) (*models.Media, error) {
+	var media models.Media
+	err := scanMedia(s.DB.QueryRow("SELECT "+mediaColumns+" FROM media WHERE id = $1", id), &media)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("imagen no encontrada")
+		}
+		return nil, fmt.Errorf("error obteniendo imagen: %v", err)
+	}
+	return &media, nil
+}
+
+// DeleteMedia elimina una imagen y sus archivos; no se permite si alguna noticia la usa
+func (s *MediaService) DeleteMedia(id uuid.UUID) error {
+	var inUse int
+	if err := s.DB.QueryRow("SELECT COUNT(*) FROM market_news WHERE image_media_id = $1", id).Scan(&inUse); err != nil {
+		return fmt.Errorf("error verificando uso de la imagen: %v", err)
+	}
+	if inUse > 0 {
+		return fmt.Errorf("imagen en uso por %d noticia(s)", inUse)
+	}
+
+	result, err := s.DB.Exec("DELETE FROM media WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("error eliminando imagen: %v", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("imagen no encontrada")
+	}
+
+	if err := os.RemoveAll(filepath.Join(s.Dir, id.String())); err != nil {
+		fmt.Printf("Error eliminando archivos de la imagen %v: %v\n", id, err)
+	}
+
+	return nil
+}
+
+// mediaImageURL URL de la versión usada como imagen principal de una noticia
+func mediaImageURL(renditions map[string]models.MediaRendition) string {
+	if rendition, ok := renditions["large"]; ok {
+		return rendition.URL
+	}
+	return renditions["original"].URL
+}
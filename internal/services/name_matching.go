@@ -0,0 +1,126 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizeName elimina acentos, signos y mayúsculas de un nombre ("PÉREZ, José" -> "perez jose")
+func normalizeName(name string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	clean, _, err := transform.String(t, name)
+	if err != nil {
+		clean = name
+	}
+
+	clean = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, clean)
+
+	return strings.Join(strings.Fields(clean), " ")
+}
+
+// nameTokens separa un nombre normalizado en palabras, ignorando partículas cortas ("de", "la")
+func nameTokens(name string) []string {
+	var tokens []string
+	for _, token := range strings.Fields(normalizeName(name)) {
+		if len(token) > 2 {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// nameSimilarity compara dos nombres sin importar el orden de las palabras.
+// Cada palabra del nombre más corto se empareja con la más parecida del otro (Jaro-Winkler)
+// y se promedia; se requieren al menos dos palabras para evitar coincidencias triviales.
+func nameSimilarity(a, b []string) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(a) < 2 {
+		return 0
+	}
+
+	var total float64
+	for _, ta := range a {
+		best := 0.0
+		for _, tb := range b {
+			if score := jaroWinkler(ta, tb); score > best {
+				best = score
+			}
+		}
+		total += best
+	}
+
+	return total / float64(len(a))
+}
+
+// jaroWinkler calcula la similitud Jaro-Winkler entre dos palabras (0 a 1)
+func jaroWinkler(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		start := max(0, i-window)
+		end := min(len(rb), i+window+1)
+		for j := start; j < end; j++ {
+			if matchedB[j] || ra[i] != rb[j] {
+				continue
+			}
+			matchedA[i] = true
+			matchedB[j] = true
+			matches++
+			break
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(ra), len(rb)) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"PÉREZ, José", "perez jose"},
+		{"  María-José   Núñez ", "maria jose nunez"},
+		{"O'Brien", "o brien"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeName(tt.in); got != tt.want {
+			t.Errorf("normalizeName(%q) = %q, se esperaba %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNameTokens(t *testing.T) {
+	got := nameTokens("José de la Cruz Álvarez")
+	want := []string{"jose", "cruz", "alvarez"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("nameTokens = %v, se esperaba %v", got, want)
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"martha", "marhta", 0.9611},
+		{"dwayne", "duane", 0.84},
+		{"dixon", "dicksonx", 0.8133},
+		{"perez", "perez", 1},
+		{"abc", "xyz", 0},
+		{"", "perez", 0},
+	}
+	for _, tt := range tests {
+		got := jaroWinkler(tt.a, tt.b)
+		if math.Abs(got-tt.want) > 0.0001 {
+			t.Errorf("jaroWinkler(%q, %q) = %.4f, se esperaba %.4f", tt.a, tt.b, got, tt.want)
+		}
+		if reverse := jaroWinkler(tt.b, tt.a); math.Abs(reverse-got) > 1e-9 {
+			t.Errorf("jaroWinkler no es simétrico para %q y %q: %.4f vs %.4f", tt.a, tt.b, got, reverse)
+		}
+	}
+}
+
+func TestNameSimilarityThreshold(t *testing.T) {
+	// Mismo umbral que SCREENING_MATCH_THRESHOLD por defecto
+	const threshold = 0.9
+
+	tests := []struct {
+		name  string
+		a, b  string
+		match bool
+	}{
+		{"mismo nombre", "José Pérez García", "JOSE PEREZ GARCIA", true},
+		{"orden distinto", "José Pérez García", "GARCIA, Jose Perez", true},
+		{"error de tipeo", "Joaquin Guzman Loera", "Joaquín Guzmán Lorea", true},
+		{"nombre incluido en otro más largo", "Nicolas Maduro", "Nicolás Maduro Moros", true},
+		{"nombres distintos", "Juan Pérez", "María Gómez", false},
+		{"solo coincide el apellido", "Juan Pérez", "Pedro Pérez", false},
+		{"una sola palabra", "Maduro", "Nicolás Maduro Moros", false},
+	}
+	for _, tt := range tests {
+		score := nameSimilarity(nameTokens(tt.a), nameTokens(tt.b))
+		if (score >= threshold) != tt.match {
+			t.Errorf("%s: nameSimilarity(%q, %q) = %.3f, coincidencia esperada: %v", tt.name, tt.a, tt.b, score, tt.match)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// newsAlertMaxAge antigüedad máxima de una noticia para alertar; evita avisos tardíos si el
+// job estuvo detenido o la política cambió
+const newsAlertMaxAge = 24 * time.Hour
+
+// newsAlertEvent valor de data.event en las notificaciones de noticias publicadas
+const newsAlertEvent = "news_published"
+
+// NewsAlertPolicy define qué noticias publicadas generan alertas y cuántas recibe cada usuario
+type NewsAlertPolicy struct {
+	MinPriority    int           // prioridad desde la que se alerta (0 = ninguna por prioridad)
+	Categories     []string      // categorías que siempre alertan, sin importar la prioridad
+	MaxPerUser     int           // alertas máximas por usuario dentro de ThrottleWindow (0 = sin límite)
+	ThrottleWindow time.Duration // ventana del límite de alertas por usuario
+	DeepLinkBase   string        // prefijo del enlace a la noticia en la app, ej: tradeoptix://news/
+}
+
+// NewsNotifier alerta a los usuarios suscritos a la categoría cuando se publica una noticia relevante
+type NewsNotifier struct {
+	DB                  *sql.DB
+	NotificationService *NotificationService
+	Policy              NewsAlertPolicy
+}
+
+func NewNewsNotifier(db *sql.DB, notificationService *NotificationService, policy NewsAlertPolicy) *NewsNotifier {
+	return &NewsNotifier{DB: db, NotificationService: notificationService, Policy: policy}
+}
+
+// newsAlert noticia publicada que se debe comunicar
+type newsAlert struct {
+	ID           uuid.UUID
+	Title        string
+	Summary      *string
+	Category     string
+	CategoryName string
+	Priority     int
+}
+
+// RunNewsAlerts alerta las noticias que pasaron a estar visibles desde la última ejecución,
+// incluidas las programadas. Se ejecuta periódicamente desde el scheduler.
+func (n *NewsNotifier) RunNewsAlerts() error {
+	alerts, err := n.claimPublishedNews()
+	if err != nil {
+		return err
+	}
+
+	for _, alert := range alerts {
+		sent, err := n.notifySubscribers(alert)
+		if err != nil {
+			fmt.Printf("Error alertando noticia %v: %v\n", alert.ID, err)
+			continue
+		}
+		if sent > 0 {
+			fmt.Printf("Noticia %v alertada a %d usuario(s)\n", alert.ID, sent)
+		}
+	}
+
+	return nil
+}
+
+// claimPublishedNews marca como evaluadas las noticias visibles pendientes y devuelve las que
+// cumplen la política. Marcarlas antes de enviar evita alertas duplicadas si el envío falla.
+func (n *NewsNotifier) claimPublishedNews() ([]newsAlert, error) {
+	rows, err := n.DB.Query(`
+		WITH claimed AS (
+		    UPDATE market_news SET alerted_at = NOW()
+		    WHERE alerted_at IS NULL AND `+publishedNewsCondition+`
+		    RETURNING id, title, summary, category, priority, published_at
+		)
+		SELECT claimed.id, claimed.title, claimed.summary, claimed.category, c.name, claimed.priority
+		FROM claimed JOIN news_categories c ON c.slug = claimed.category
+		WHERE claimed.published_at > NOW() - $1::interval
+		  AND (($2 > 0 AND claimed.priority >= $2) OR claimed.category = ANY($3::text[]))
+		ORDER BY claimed.published_at ASC
+	`, fmt.Sprintf("%d seconds", int(newsAlertMaxAge.Seconds())), n.Policy.MinPriority, pq.Array(n.Policy.Categories))
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo noticias publicadas: %v", err)
+	}
+	defer rows.Close()
+
+	var alerts []newsAlert
+	for rows.Next() {
+		var alert newsAlert
+		if err := rows.Scan(&alert.ID, &alert.Title, &alert.Summary, &alert.Category, &alert.CategoryName, &alert.Priority); err != nil {
+			return nil, fmt.Errorf("error escaneando noticia publicada: %v", err)
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+// notifySubscribers crea la alerta para cada suscriptor de la categoría que no superó el límite
+// de alertas recientes; devuelve cuántas se enviaron
+func (n *NewsNotifier) notifySubscribers(alert newsAlert) (int, error) {
+	rows, err := n.DB.Query(`
+		SELECT s.user_id
+		FROM news_category_subscriptions s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.category = $1 AND u.closed_at IS NULL
+		  AND ($2 <= 0 OR (
+		      SELECT COUNT(*) FROM notifications n
+		      WHERE n.user_id = s.user_id AND n.category = 'market' AND n.data->>'event' = $3
+		        AND n.created_at > NOW() - $4::interval
+		  ) < $2)
+	`, alert.Category, n.Policy.MaxPerUser, newsAlertEvent, fmt.Sprintf("%d seconds", int(n.Policy.ThrottleWindow.Seconds())))
+	if err != nil {
+		return 0, fmt.Errorf("error obteniendo suscriptores: %v", err)
+	}
+
+	var userIDs []uuid.UUID
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("error escaneando suscriptor: %v", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	rows.Close()
+
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"event":     newsAlertEvent,
+		"news_id":   alert.ID,
+		"category":  alert.Category,
+		"priority":  alert.Priority,
+		"deep_link": n.Policy.DeepLinkBase + alert.ID.String(),
+	})
+	if err != nil {
+		return 0, err
+	}
+	dataStr := string(data)
+
+	message := "Nueva noticia en " + alert.CategoryName
+	if alert.Summary != nil && *alert.Summary != "" {
+		message = *alert.Summary
+	}
+
+	sent := 0
+	for _, userID := range userIDs {
+		_, err := n.NotificationService.CreateNotification(models.CreateNotificationRequest{
+			UserID:   &userID,
+			Title:    truncateRunes(alert.Title, 255),
+			Message:  message,
+			Type:     "info",
+			Category: "market",
+			Data:     &dataStr,
+			SendPush: true,
+		})
+		if err != nil {
+			fmt.Printf("Error creando alerta de noticia para usuario %v: %v\n", userID, err)
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// GetNewsSubscriptions obtiene las categorías a las que está suscrito el usuario
+func (s *NotificationService) GetNewsSubscriptions(userID uuid.UUID) ([]models.NewsCategorySubscription, error) {
+	rows, err := s.DB.Query(`
+		SELECT s.category, c.name, s.created_at
+		FROM news_category_subscriptions s JOIN news_categories c ON c.slug = s.category
+		WHERE s.user_id = $1
+		ORDER BY c.sort_order ASC, c.name ASC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo suscripciones: %v", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []models.NewsCategorySubscription{}
+	for rows.Next() {
+		var sub models.NewsCategorySubscription
+		if err := rows.Scan(&sub.Category, &sub.CategoryName, &sub.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error escaneando suscripción: %v", err)
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+
+	return subscriptions, nil
+}
+
+// SubscribeNewsCategory suscribe al usuario a las alertas de una categoría activa
+func (s *NotificationService) SubscribeNewsCategory(userID uuid.UUID, category string) error {
+	if err := validateNewsCategory(s.DB, category); err != nil {
+		return err
+	}
+
+	_, err := s.DB.Exec(`
+		INSERT INTO news_category_subscriptions (user_id, category) VALUES ($1, $2)
+		ON CONFLICT (user_id, category) DO NOTHING
+	`, userID, category)
+	if err != nil {
+		return fmt.Errorf("error guardando suscripción: %v", err)
+	}
+	return nil
+}
+
+// UnsubscribeNewsCategory cancela la suscripción del usuario a una categoría
+func (s *NotificationService) UnsubscribeNewsCategory(userID uuid.UUID, category string) error {
+	result, err := s.DB.Exec("DELETE FROM news_category_subscriptions WHERE user_id = $1 AND category = $2", userID, category)
+	if err != nil {
+		return fmt.Errorf("error cancelando suscripción: %v", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("suscripción no encontrada")
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// mostViewedNewsLimit noticias incluidas en el ranking de GetNewsStats
+const mostViewedNewsLimit = 10
+
+// maxTimeSeriesDays días máximos de la serie temporal
+const maxTimeSeriesDays = 365
+
+// RecordView registra que el usuario abrió la noticia
+func (s *NewsService) RecordView(newsID, userID uuid.UUID) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("INSERT INTO news_views (news_id, user_id) VALUES ($1, $2)", newsID, userID); err != nil {
+		return fmt.Errorf("error registrando visita: %v", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO news_reads (news_id, user_id, view_count) VALUES ($1, $2, 1)
+		ON CONFLICT (news_id, user_id) DO UPDATE SET
+		    view_count = news_reads.view_count + 1,
+		    last_viewed_at = NOW()
+	`, newsID, userID)
+	if err != nil {
+		return fmt.Errorf("error registrando visita: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error registrando visita: %v", err)
+	}
+	return nil
+}
+
+// MarkAsRead marca la noticia como leída por el usuario; se conserva la fecha de la primera lectura
+func (s *NewsService) MarkAsRead(newsID, userID uuid.UUID) error {
+	_, err := s.DB.Exec(`
+		INSERT INTO news_reads (news_id, user_id, read_at) VALUES ($1, $2, NOW())
+		ON CONFLICT (news_id, user_id) DO UPDATE SET read_at = COALESCE(news_reads.read_at, NOW())
+	`, newsID, userID)
+	if err != nil {
+		return fmt.Errorf("error marcando noticia como leída: %v", err)
+	}
+	return nil
+}
+
+// SetUserState completa IsRead e IsBookmarked en las noticias según las lecturas y las
+// noticias guardadas del usuario
+func (s *NewsService) SetUserState(userID uuid.UUID, newsList []models.MarketNews) error {
+	if len(newsList) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(newsList))
+	for i, news := range newsList {
+		ids[i] = news.ID.String()
+	}
+
+	rows, err := s.DB.Query(`
+		SELECT n.id,
+		       EXISTS (SELECT 1 FROM news_reads r WHERE r.news_id = n.id AND r.user_id = $1 AND r.read_at IS NOT NULL),
+		       EXISTS (SELECT 1 FROM news_bookmarks b WHERE b.news_id = n.id AND b.user_id = $1)
+		FROM unnest($2::uuid[]) AS n(id)
+	`, userID, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("error obteniendo estado de las noticias: %v", err)
+	}
+	defer rows.Close()
+
+	type userState struct{ read, bookmarked bool }
+	states := make(map[uuid.UUID]userState)
+	for rows.Next() {
+		var id uuid.UUID
+		var state userState
+		if err := rows.Scan(&id, &state.read, &state.bookmarked); err != nil {
+			return fmt.Errorf("error escaneando estado de noticia: %v", err)
+		}
+		states[id] = state
+	}
+
+	for i := range newsList {
+		state := states[newsList[i].ID]
+		newsList[i].IsRead = &state.read
+		newsList[i].IsBookmarked = &state.bookmarked
+	}
+	return nil
+}
+
+// articleStatsQuery visitas totales, lectores únicos, lecturas y guardados por noticia
+const articleStatsQuery = `
+	SELECT n.id, n.title,
+	       COALESCE(SUM(r.view_count), 0),
+	       COUNT(r.user_id) FILTER (WHERE r.view_count > 0),
+	       COUNT(r.read_at),
+	       (SELECT COUNT(*) FROM news_bookmarks b WHERE b.news_id = n.id)
+	FROM market_news n
+	LEFT JOIN news_reads r ON r.news_id = n.id`
+
+func scanArticleStats(row rowScanner) (*models.NewsArticleStats, error) {
+	var stats models.NewsArticleStats
+	if err := row.Scan(&stats.NewsID, &stats.Title, &stats.TotalViews, &stats.UniqueViews, &stats.Reads, &stats.Bookmarks); err != nil {
+		return nil, err
+	}
+	// Una noticia puede marcarse como leída sin registrar visita, así que la tasa se limita a 1
+	if stats.UniqueViews > 0 {
+		stats.ReadThroughRate = math.Min(1, float64(stats.Reads)/float64(stats.UniqueViews))
+	}
+	return &stats, nil
+}
+
+// GetArticleStats obtiene las visitas y lecturas de una noticia
+func (s *NewsService) GetArticleStats(newsID uuid.UUID) (*models.NewsArticleStats, error) {
+	stats, err := scanArticleStats(s.DB.QueryRow(articleStatsQuery+" WHERE n.id = $1 GROUP BY n.id", newsID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("noticia no encontrada")
+		}
+		return nil, fmt.Errorf("error obteniendo estadísticas de la noticia: %v", err)
+	}
+	return stats, nil
+}
+
+// addAudienceStats completa los totales de audiencia y el ranking de noticias más vistas
+func (s *NewsService) addAudienceStats(stats *models.NewsStats) error {
+	err := s.DB.QueryRow(`
+		SELECT COALESCE(SUM(view_count), 0), COUNT(DISTINCT user_id), COUNT(read_at),
+		       (SELECT COUNT(*) FROM news_bookmarks)
+		FROM news_reads
+	`).Scan(&stats.TotalViews, &stats.UniqueReaders, &stats.TotalReads, &stats.TotalBookmarks)
+	if err != nil {
+		return fmt.Errorf("error obteniendo audiencia: %v", err)
+	}
+
+	rows, err := s.DB.Query(articleStatsQuery+`
+		GROUP BY n.id
+		HAVING COALESCE(SUM(r.view_count), 0) > 0
+		ORDER BY 3 DESC, 4 DESC
+		LIMIT $1
+	`, mostViewedNewsLimit)
+	if err != nil {
+		return fmt.Errorf("error obteniendo noticias más vistas: %v", err)
+	}
+	defer rows.Close()
+
+	stats.MostViewedNews = []models.NewsArticleStats{}
+	for rows.Next() {
+		article, err := scanArticleStats(rows)
+		if err != nil {
+			return fmt.Errorf("error escaneando estadísticas de noticia: %v", err)
+		}
+		stats.MostViewedNews = append(stats.MostViewedNews, *article)
+	}
+	return nil
+}
+
+// GetViewsTimeSeries obtiene visitas, lectores únicos y lecturas por día de los últimos días,
+// incluidos los días sin actividad. Con newsID se limita a una noticia.
+func (s *NewsService) GetViewsTimeSeries(days int, newsID *uuid.UUID) ([]models.NewsTimeSeriesPoint, error) {
+	if days < 1 || days > maxTimeSeriesDays {
+		return nil, fmt.Errorf("el rango debe ser de 1 a %d días", maxTimeSeriesDays)
+	}
+
+	rows, err := s.DB.Query(`
+		WITH days AS (
+		    SELECT generate_series(CURRENT_DATE - ($1::int - 1), CURRENT_DATE, INTERVAL '1 day')::date AS day
+		),
+		views AS (
+		    SELECT viewed_at::date AS day, COUNT(*) AS views, COUNT(DISTINCT user_id) AS unique_views
+		    FROM news_views
+		    WHERE viewed_at >= CURRENT_DATE - ($1::int - 1) AND ($2::uuid IS NULL OR news_id = $2)
+		    GROUP BY 1
+		),
+		reads AS (
+		    SELECT read_at::date AS day, COUNT(*) AS reads
+		    FROM news_reads
+		    WHERE read_at >= CURRENT_DATE - ($1::int - 1) AND ($2::uuid IS NULL OR news_id = $2)
+		    GROUP BY 1
+		)
+		SELECT to_char(d.day, 'YYYY-MM-DD'), COALESCE(v.views, 0), COALESCE(v.unique_views, 0), COALESCE(r.reads, 0)
+		FROM days d
+		LEFT JOIN views v ON v.day = d.day
+		LEFT JOIN reads r ON r.day = d.day
+		ORDER BY d.day ASC
+	`, days, newsID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo serie temporal: %v", err)
+	}
+	defer rows.Close()
+
+	var points []models.NewsTimeSeriesPoint
+	for rows.Next() {
+		var point models.NewsTimeSeriesPoint
+		if err := rows.Scan(&point.Date, &point.Views, &point.UniqueViews, &point.Reads); err != nil {
+			return nil, fmt.Errorf("error escaneando serie temporal: %v", err)
+		}
+		points = append(points, point)
+	}
+
+	return points, nil
+}
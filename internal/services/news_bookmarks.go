@@ -0,0 +1,77 @@
+package services
+
+import (
+	"fmt"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// AddBookmark guarda una noticia publicada para el usuario; guardarla de nuevo no tiene efecto
+func (s *NewsService) AddBookmark(newsID, userID uuid.UUID) error {
+	if err := s.ensurePublishedNews(newsID); err != nil {
+		return err
+	}
+
+	_, err := s.DB.Exec(`
+		INSERT INTO news_bookmarks (user_id, news_id) VALUES ($1, $2)
+		ON CONFLICT (user_id, news_id) DO NOTHING
+	`, userID, newsID)
+	if err != nil {
+		return fmt.Errorf("error guardando noticia: %v", err)
+	}
+	return nil
+}
+
+// RemoveBookmark quita una noticia de las guardadas por el usuario
+func (s *NewsService) RemoveBookmark(newsID, userID uuid.UUID) error {
+	result, err := s.DB.Exec("DELETE FROM news_bookmarks WHERE user_id = $1 AND news_id = $2", userID, newsID)
+	if err != nil {
+		return fmt.Errorf("error quitando noticia guardada: %v", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("noticia guardada no encontrada")
+	}
+	return nil
+}
+
+// GetBookmarks obtiene las noticias guardadas por el usuario, de la más reciente a la más antigua.
+// Las que dejaron de estar publicadas se conservan pero no se listan.
+func (s *NewsService) GetBookmarks(userID uuid.UUID, page, limit int) ([]models.MarketNews, int, error) {
+	offset := (page - 1) * limit
+
+	var total int
+	err := s.DB.QueryRow(`
+		SELECT COUNT(*) FROM market_news
+		WHERE id IN (SELECT news_id FROM news_bookmarks WHERE user_id = $1) AND `+publishedNewsCondition, userID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error contando noticias guardadas: %v", err)
+	}
+
+	rows, err := s.DB.Query(`
+		SELECT `+newsColumns+`
+		FROM market_news
+		JOIN (SELECT news_id, created_at AS bookmarked_at FROM news_bookmarks WHERE user_id = $1) b
+		  ON b.news_id = market_news.id
+		WHERE `+publishedNewsCondition+`
+		ORDER BY b.bookmarked_at DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error obteniendo noticias guardadas: %v", err)
+	}
+	defer rows.Close()
+
+	newsList := []models.MarketNews{}
+	for rows.Next() {
+		var news models.MarketNews
+		if err := scanNews(rows, &news); err != nil {
+			return nil, 0, fmt.Errorf("error escaneando noticia: %v", err)
+		}
+		newsList = append(newsList, news)
+	}
+
+	return newsList, total, nil
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// newsTransitions cambios de estado editorial permitidos desde cada estado
+var newsTransitions = map[models.NewsStatus][]models.NewsStatus{
+	models.NewsStatusDraft:     {models.NewsStatusInReview, models.NewsStatusArchived},
+	models.NewsStatusInReview:  {models.NewsStatusDraft, models.NewsStatusPublished, models.NewsStatusArchived},
+	models.NewsStatusPublished: {models.NewsStatusDraft, models.NewsStatusArchived},
+	models.NewsStatusArchived:  {models.NewsStatusDraft},
+}
+
+// ChangeStatus cambia el estado editorial de una noticia. Solo se publica desde revisión y el
+// aprobador no puede ser el autor ni quien editó la noticia o sus traducciones sin revisión.
+func (s *NewsService) ChangeStatus(id uuid.UUID, req models.ChangeNewsStatusRequest, adminID uuid.UUID) (*models.MarketNews, error) {
+	news, err := s.GetNewsByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := false
+	for _, status := range newsTransitions[news.Status] {
+		if status == req.Status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("transición no permitida: de %s a %s", news.Status, req.Status)
+	}
+
+	query := "UPDATE market_news SET status = $1, review_note = $2, updated_at = NOW()"
+	args := []interface{}{req.Status, req.Note}
+
+	switch req.Status {
+	case models.NewsStatusPublished:
+		edited, err := s.editedSinceReview(id, adminID, news.ReviewedAt)
+		if err != nil {
+			return nil, err
+		}
+		if (news.CreatedBy != nil && *news.CreatedBy == adminID) || edited {
+			return nil, fmt.Errorf("la noticia debe ser aprobada por alguien distinto de su autor")
+		}
+
+		// Sin publicación programada, la noticia se publica al aprobarse
+		publishAt := news.PublishedAt
+		if now := time.Now(); publishAt.Before(now) {
+			publishAt = now
+		}
+		if err := validatePublicationWindow(publishAt, news.ExpireAt); err != nil {
+			return nil, err
+		}
+
+		query += ", reviewed_by = $3, reviewed_at = NOW(), published_at = $4"
+		args = append(args, adminID, publishAt)
+	case models.NewsStatusDraft:
+		if news.Status == models.NewsStatusInReview {
+			query += ", reviewed_by = $3, reviewed_at = NOW()"
+			args = append(args, adminID)
+		}
+	}
+
+	// Las comprobaciones se hicieron sobre la noticia leída: si alguien la editó o cambió su estado
+	// entretanto, no se aplica el cambio
+	args = append(args, id, news.Status, news.UpdatedAt)
+	query += fmt.Sprintf(" WHERE id = $%d AND status = $%d AND updated_at = $%d", len(args)-2, len(args)-1, len(args))
+
+	result, err := s.DB.Exec(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error cambiando estado de la noticia: %v", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return nil, fmt.Errorf("la noticia fue modificada mientras se cambiaba su estado")
+	}
+
+	return s.GetNewsByID(id)
+}
+
+// recordRevision guarda el contenido actual de la noticia como nueva versión; editorID es nil
+// para las noticias importadas automáticamente
+func recordRevision(tx *sql.Tx, newsID uuid.UUID, editorID *uuid.UUID) error {
+	_, err := tx.Exec(`
+		INSERT INTO market_news_revisions (news_id, revision, title, content, summary, image_url, image_media_id, category, priority, edited_by)
+		SELECT id,
+		       COALESCE((SELECT MAX(revision) FROM market_news_revisions WHERE news_id = $1), 0) + 1,
+		       title, content, summary, image_url, image_media_id, category, priority, $2
+		FROM market_news WHERE id = $1
+	`, newsID, editorID)
+	if err != nil {
+		return fmt.Errorf("error guardando versión de la noticia: %v", err)
+	}
+	return nil
+}
+
+// statusAfterEdit estado de una noticia tras cambiar su contenido o una traducción: en revisión
+// vuelve a borrador y publicada se retira y pasa a revisión
+func statusAfterEdit(status models.NewsStatus) models.NewsStatus {
+	switch status {
+	case models.NewsStatusInReview:
+		return models.NewsStatusDraft
+	case models.NewsStatusPublished:
+		return models.NewsStatusInReview
+	}
+	return status
+}
+
+// editedSinceReview indica si el admin hizo la última versión de la noticia o de una de sus
+// traducciones, o alguna posterior a la última revisión
+func (s *NewsService) editedSinceReview(newsID, adminID uuid.UUID, reviewedAt *time.Time) (bool, error) {
+	var edited bool
+	err := s.DB.QueryRow(`
+		WITH edits AS (
+			SELECT edited_by, created_at FROM market_news_revisions WHERE news_id = $1
+			UNION ALL
+			SELECT edited_by, created_at FROM market_news_translation_revisions WHERE news_id = $1
+		)
+		SELECT EXISTS(
+			SELECT 1 FROM edits
+			WHERE edited_by = $2
+			  AND ($3::timestamptz IS NULL OR created_at > $3 OR created_at = (SELECT MAX(created_at) FROM edits))
+		)
+	`, newsID, adminID, reviewedAt).Scan(&edited)
+	if err != nil {
+		return false, fmt.Errorf("error obteniendo ediciones de la noticia: %v", err)
+	}
+	return edited, nil
+}
+
+// GetRevisions obtiene las versiones de una noticia, de la más reciente a la más antigua
+func (s *NewsService) GetRevisions(newsID uuid.UUID) ([]models.NewsRevision, error) {
+	rows, err := s.DB.Query(`
+		SELECT id, news_id, revision, title, content, summary, image_url, image_media_id, category, priority, edited_by, created_at
+		FROM market_news_revisions WHERE news_id = $1
+		ORDER BY revision DESC
+	`, newsID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo versiones: %v", err)
+	}
+	defer rows.Close()
+
+	var revisions []models.NewsRevision
+	for rows.Next() {
+		var r models.NewsRevision
+		err := rows.Scan(&r.ID, &r.NewsID, &r.Revision, &r.Title, &r.Content, &r.Summary,
+			&r.ImageURL, &r.ImageMediaID, &r.Category, &r.Priority, &r.EditedBy, &r.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando versión: %v", err)
+		}
+		revisions = append(revisions, r)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision obtiene una versión de una noticia
+func (s *NewsService) GetRevision(newsID uuid.UUID, revision int) (*models.NewsRevision, error) {
+	var r models.NewsRevision
+	err := s.DB.QueryRow(`
+		SELECT id, news_id, revision, title, content, summary, image_url, image_media_id, category, priority, edited_by, created_at
+		FROM market_news_revisions WHERE news_id = $1 AND revision = $2
+	`, newsID, revision).Scan(&r.ID, &r.NewsID, &r.Revision, &r.Title, &r.Content, &r.Summary,
+		&r.ImageURL, &r.ImageMediaID, &r.Category, &r.Priority, &r.EditedBy, &r.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("versión no encontrada")
+		}
+		return nil, fmt.Errorf("error obteniendo versión: %v", err)
+	}
+	return &r, nil
+}
+
+// DiffRevisions compara dos versiones de una noticia: cambios por campo y diff por líneas del contenido
+func (s *NewsService) DiffRevisions(newsID uuid.UUID, from, to int) (*models.NewsRevisionDiff, error) {
+	oldRev, err := s.GetRevision(newsID, from)
+	if err != nil {
+		return nil, err
+	}
+	newRev, err := s.GetRevision(newsID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.NewsRevisionDiff{
+		NewsID:      newsID,
+		From:        from,
+		To:          to,
+		Changes:     []models.NewsFieldChange{},
+		ContentDiff: diffLines(strings.Split(oldRev.Content, "\n"), strings.Split(newRev.Content, "\n")),
+	}
+
+	addChange := func(field string, oldValue, newValue interface{}) {
+		diff.Changes = append(diff.Changes, models.NewsFieldChange{Field: field, Old: oldValue, New: newValue})
+	}
+	if oldRev.Title != newRev.Title {
+		addChange("title", oldRev.Title, newRev.Title)
+	}
+	if stringValue(oldRev.Summary) != stringValue(newRev.Summary) {
+		addChange("summary", oldRev.Summary, newRev.Summary)
+	}
+	if stringValue(oldRev.ImageURL) != stringValue(newRev.ImageURL) {
+		addChange("image_url", oldRev.ImageURL, newRev.ImageURL)
+	}
+	if (oldRev.ImageMediaID == nil) != (newRev.ImageMediaID == nil) ||
+		(oldRev.ImageMediaID != nil && *oldRev.ImageMediaID != *newRev.ImageMediaID) {
+		addChange("image_media_id", oldRev.ImageMediaID, newRev.ImageMediaID)
+	}
+	if oldRev.Category != newRev.Category {
+		addChange("category", oldRev.Category, newRev.Category)
+	}
+	if oldRev.Priority != newRev.Priority {
+		addChange("priority", oldRev.Priority, newRev.Priority)
+	}
+
+	return diff, nil
+}
+
+// RestoreRevision vuelve el contenido de la noticia al de una versión anterior; la restauración
+// se guarda como una versión nueva
+func (s *NewsService) RestoreRevision(newsID uuid.UUID, revision int, adminID uuid.UUID) (*models.MarketNews, error) {
+	r, err := s.GetRevision(newsID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	req := models.UpdateNewsRequest{
+		Title:        &r.Title,
+		Content:      &r.Content,
+		Summary:      r.Summary,
+		ImageURL:     r.ImageURL,
+		ImageMediaID: r.ImageMediaID,
+		ClearImage:   r.ImageURL == nil && r.ImageMediaID == nil,
+		Category:     &r.Category,
+		Priority:     &r.Priority,
+	}
+	if err := s.UpdateNews(newsID, req, adminID); err != nil {
+		return nil, err
+	}
+
+	return s.GetNewsByID(newsID)
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
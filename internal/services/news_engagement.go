@@ -0,0 +1,435 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// maxCommentLength largo máximo de un comentario
+const maxCommentLength = 2000
+
+// maxCommentDepth niveles de respuesta permitidos bajo un comentario de primer nivel
+const maxCommentDepth = 3
+
+// commentColumns columnas de news_comments en el orden que espera scanComment; el autor se
+// muestra con el nombre y la inicial del apellido. Requiere el join de users como u.
+const commentColumns = `c.id, c.news_id, c.user_id, u.first_name || ' ' || LEFT(u.last_name, 1) || '.',
+	       c.parent_id, c.thread_id, c.depth,
+	       CASE WHEN c.status = 'deleted' THEN '' ELSE c.content END,
+	       c.status, c.flag_reasons, c.moderated_by, c.moderated_at, c.moderation_note,
+	       c.created_at, c.updated_at`
+
+func scanComment(row rowScanner, comment *models.NewsComment) error {
+	return row.Scan(
+		&comment.ID, &comment.NewsID, &comment.UserID, &comment.AuthorName,
+		&comment.ParentID, &comment.ThreadID, &comment.Depth, &comment.Content,
+		&comment.Status, pq.Array(&comment.FlagReasons), &comment.ModeratedBy, &comment.ModeratedAt, &comment.ModerationNote,
+		&comment.CreatedAt, &comment.UpdatedAt,
+	)
+}
+
+// visibleCommentCondition comentarios que ve un usuario: aprobados, eliminados (sin contenido,
+// para no romper el hilo) y los propios pendientes de moderación
+func visibleCommentCondition(viewerArg int) string {
+	return fmt.Sprintf("(c.status IN ('approved', 'deleted') OR (c.status = 'pending' AND c.user_id = $%d))", viewerArg)
+}
+
+// ensurePublishedNews verifica que la noticia esté publicada y vigente
+func (s *NewsService) ensurePublishedNews(newsID uuid.UUID) error {
+	var published bool
+	err := s.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM market_news WHERE id = $1 AND "+publishedNewsCondition+")", newsID).Scan(&published)
+	if err != nil {
+		return fmt.Errorf("error verificando noticia: %v", err)
+	}
+	if !published {
+		return fmt.Errorf("noticia no encontrada")
+	}
+	return nil
+}
+
+// SetReaction registra la reacción del usuario a una noticia publicada, reemplazando la anterior
+func (s *NewsService) SetReaction(newsID, userID uuid.UUID, reaction models.NewsReaction) error {
+	if err := s.ensurePublishedNews(newsID); err != nil {
+		return err
+	}
+
+	_, err := s.DB.Exec(`
+		INSERT INTO news_reactions (news_id, user_id, reaction) VALUES ($1, $2, $3)
+		ON CONFLICT (news_id, user_id) DO UPDATE SET reaction = EXCLUDED.reaction, created_at = NOW()
+	`, newsID, userID, reaction)
+	if err != nil {
+		return fmt.Errorf("error guardando reacción: %v", err)
+	}
+	return nil
+}
+
+// RemoveReaction quita la reacción del usuario a una noticia
+func (s *NewsService) RemoveReaction(newsID, userID uuid.UUID) error {
+	result, err := s.DB.Exec("DELETE FROM news_reactions WHERE news_id = $1 AND user_id = $2", newsID, userID)
+	if err != nil {
+		return fmt.Errorf("error quitando reacción: %v", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("reacción no encontrada")
+	}
+	return nil
+}
+
+// SetEngagement completa las reacciones por tipo, la reacción del usuario y la cantidad de
+// comentarios aprobados de una noticia
+func (s *NewsService) SetEngagement(news *models.MarketNews, userID uuid.UUID) error {
+	rows, err := s.DB.Query(`
+		SELECT reaction, COUNT(*), BOOL_OR(user_id = $2)
+		FROM news_reactions WHERE news_id = $1
+		GROUP BY reaction
+	`, news.ID, userID)
+	if err != nil {
+		return fmt.Errorf("error obteniendo reacciones: %v", err)
+	}
+	defer rows.Close()
+
+	news.Reactions = make(map[models.NewsReaction]int)
+	for _, reaction := range models.NewsReactions {
+		news.Reactions[reaction] = 0
+	}
+	news.UserReaction = nil
+	for rows.Next() {
+		var reaction models.NewsReaction
+		var count int
+		var own bool
+		if err := rows.Scan(&reaction, &count, &own); err != nil {
+			return fmt.Errorf("error escaneando reacción: %v", err)
+		}
+		news.Reactions[reaction] = count
+		if own {
+			news.UserReaction = &reaction
+		}
+	}
+
+	var comments int
+	err = s.DB.QueryRow("SELECT COUNT(*) FROM news_comments WHERE news_id = $1 AND status = 'approved'", news.ID).Scan(&comments)
+	if err != nil {
+		return fmt.Errorf("error contando comentarios: %v", err)
+	}
+	news.CommentCount = &comments
+	return nil
+}
+
+// CreateComment publica un comentario o una respuesta. Los comentarios que el filtro marca
+// (groserías o enlaces) quedan pendientes de moderación; el resto se aprueba directamente.
+func (s *NewsService) CreateComment(newsID, userID uuid.UUID, req models.CreateNewsCommentRequest) (*models.NewsComment, error) {
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, fmt.Errorf("el comentario no puede estar vacío")
+	}
+	if utf8.RuneCountInString(content) > maxCommentLength {
+		return nil, fmt.Errorf("el comentario no puede superar %d caracteres", maxCommentLength)
+	}
+
+	if err := s.checkMuted(userID); err != nil {
+		return nil, err
+	}
+	if err := s.ensurePublishedNews(newsID); err != nil {
+		return nil, err
+	}
+
+	id := uuid.New()
+	threadID := id
+	depth := 0
+	if req.ParentID != nil {
+		var parentNewsID uuid.UUID
+		var parentStatus models.NewsCommentStatus
+		var parentDepth int
+		err := s.DB.QueryRow(`
+			SELECT news_id, thread_id, depth, status FROM news_comments WHERE id = $1
+		`, *req.ParentID).Scan(&parentNewsID, &threadID, &parentDepth, &parentStatus)
+		if err == sql.ErrNoRows || (err == nil && (parentNewsID != newsID || parentStatus != models.NewsCommentStatusApproved)) {
+			return nil, fmt.Errorf("comentario a responder no encontrado")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error obteniendo comentario a responder: %v", err)
+		}
+		if parentDepth >= maxCommentDepth {
+			return nil, fmt.Errorf("no se puede responder a un comentario con más de %d niveles", maxCommentDepth)
+		}
+		depth = parentDepth + 1
+	}
+
+	status := models.NewsCommentStatusApproved
+	reasons := s.commentFilter.check(content)
+	if len(reasons) > 0 {
+		status = models.NewsCommentStatusPending
+	} else {
+		reasons = []string{}
+	}
+
+	_, err := s.DB.Exec(`
+		INSERT INTO news_comments (id, news_id, user_id, parent_id, thread_id, depth, content, status, flag_reasons)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, id, newsID, userID, req.ParentID, threadID, depth, content, status, pq.Array(reasons))
+	if err != nil {
+		return nil, fmt.Errorf("error creando comentario: %v", err)
+	}
+
+	return s.GetComment(id)
+}
+
+// GetComment obtiene un comentario por ID
+func (s *NewsService) GetComment(id uuid.UUID) (*models.NewsComment, error) {
+	var comment models.NewsComment
+	err := scanComment(s.DB.QueryRow(`
+		SELECT `+commentColumns+`
+		FROM news_comments c JOIN users u ON u.id = c.user_id
+		WHERE c.id = $1
+	`, id), &comment)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("comentario no encontrado")
+		}
+		return nil, fmt.Errorf("error obteniendo comentario: %v", err)
+	}
+	return &comment, nil
+}
+
+// GetComments obtiene los hilos de comentarios de una noticia visibles para el usuario. La
+// paginación es por comentario de primer nivel, del más reciente al más antiguo; las respuestas
+// se anidan en orden cronológico.
+func (s *NewsService) GetComments(newsID, viewerID uuid.UUID, page, limit int) ([]models.NewsComment, int, error) {
+	offset := (page - 1) * limit
+
+	var total int
+	err := s.DB.QueryRow(`
+		SELECT COUNT(*) FROM news_comments c
+		WHERE c.news_id = $1 AND c.parent_id IS NULL AND `+visibleCommentCondition(2), newsID, viewerID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error contando comentarios: %v", err)
+	}
+
+	rows, err := s.DB.Query(`
+		WITH threads AS (
+		    SELECT c.id FROM news_comments c
+		    WHERE c.news_id = $1 AND c.parent_id IS NULL AND `+visibleCommentCondition(2)+`
+		    ORDER BY c.created_at DESC
+		    LIMIT $3 OFFSET $4
+		)
+		SELECT `+commentColumns+`
+		FROM news_comments c JOIN users u ON u.id = c.user_id
+		WHERE c.thread_id IN (SELECT id FROM threads) AND `+visibleCommentCondition(2)+`
+		ORDER BY c.depth ASC, c.created_at ASC
+	`, newsID, viewerID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error obteniendo comentarios: %v", err)
+	}
+	defer rows.Close()
+
+	var comments []models.NewsComment
+	for rows.Next() {
+		var comment models.NewsComment
+		if err := scanComment(rows, &comment); err != nil {
+			return nil, 0, fmt.Errorf("error escaneando comentario: %v", err)
+		}
+		// Los motivos del filtro solo se muestran en moderación
+		comment.FlagReasons = nil
+		comments = append(comments, comment)
+	}
+
+	return buildCommentThreads(comments), total, nil
+}
+
+// buildCommentThreads anida las respuestas bajo su comentario padre. Espera los comentarios
+// ordenados por profundidad; las respuestas a comentarios no visibles se descartan.
+func buildCommentThreads(comments []models.NewsComment) []models.NewsComment {
+	children := make(map[uuid.UUID][]models.NewsComment)
+	var roots []models.NewsComment
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			roots = append(roots, comment)
+		} else {
+			children[*comment.ParentID] = append(children[*comment.ParentID], comment)
+		}
+	}
+
+	var attach func(comment *models.NewsComment)
+	attach = func(comment *models.NewsComment) {
+		comment.Replies = children[comment.ID]
+		for i := range comment.Replies {
+			attach(&comment.Replies[i])
+		}
+	}
+
+	// Más recientes primero entre los de primer nivel
+	threads := []models.NewsComment{}
+	for i := len(roots) - 1; i >= 0; i-- {
+		attach(&roots[i])
+		threads = append(threads, roots[i])
+	}
+	return threads
+}
+
+// DeleteComment elimina un comentario propio. Se conserva vacío para no romper las respuestas.
+func (s *NewsService) DeleteComment(commentID, userID uuid.UUID) error {
+	result, err := s.DB.Exec(`
+		UPDATE news_comments SET status = 'deleted'
+		WHERE id = $1 AND user_id = $2 AND status <> 'deleted'
+	`, commentID, userID)
+	if err != nil {
+		return fmt.Errorf("error eliminando comentario: %v", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("comentario no encontrado")
+	}
+	return nil
+}
+
+// GetCommentsForModeration obtiene los comentarios en el estado indicado (pending por defecto),
+// los más antiguos primero
+func (s *NewsService) GetCommentsForModeration(status models.NewsCommentStatus, page, limit int) ([]models.NewsComment, int, error) {
+	if status == "" {
+		status = models.NewsCommentStatusPending
+	}
+	offset := (page - 1) * limit
+
+	var total int
+	if err := s.DB.QueryRow("SELECT COUNT(*) FROM news_comments WHERE status = $1", status).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error contando comentarios: %v", err)
+	}
+
+	rows, err := s.DB.Query(`
+		SELECT `+commentColumns+`
+		FROM news_comments c JOIN users u ON u.id = c.user_id
+		WHERE c.status = $1
+		ORDER BY c.created_at ASC
+		LIMIT $2 OFFSET $3
+	`, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error obteniendo comentarios: %v", err)
+	}
+	defer rows.Close()
+
+	comments := []models.NewsComment{}
+	for rows.Next() {
+		var comment models.NewsComment
+		if err := scanComment(rows, &comment); err != nil {
+			return nil, 0, fmt.Errorf("error escaneando comentario: %v", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, total, nil
+}
+
+// ModerateComment aprueba o rechaza un comentario; rechazar uno aprobado lo retira de la noticia
+func (s *NewsService) ModerateComment(commentID uuid.UUID, req models.ModerateNewsCommentRequest, adminID uuid.UUID) (*models.NewsComment, error) {
+	result, err := s.DB.Exec(`
+		UPDATE news_comments SET status = $1, moderation_note = $2, moderated_by = $3, moderated_at = NOW()
+		WHERE id = $4 AND status <> 'deleted'
+	`, req.Status, req.Note, adminID, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("error moderando comentario: %v", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("comentario no encontrado")
+	}
+
+	return s.GetComment(commentID)
+}
+
+// checkMuted verifica que el usuario no esté silenciado
+func (s *NewsService) checkMuted(userID uuid.UUID) error {
+	var mutedUntil *time.Time
+	err := s.DB.QueryRow(`
+		SELECT muted_until FROM news_muted_users
+		WHERE user_id = $1 AND (muted_until IS NULL OR muted_until > NOW())
+	`, userID).Scan(&mutedUntil)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error verificando silencio del usuario: %v", err)
+	}
+	if mutedUntil != nil {
+		return fmt.Errorf("usuario silenciado hasta %s", mutedUntil.Format(time.RFC3339))
+	}
+	return fmt.Errorf("usuario silenciado")
+}
+
+// MuteUser impide que el usuario comente, por el tiempo indicado o indefinidamente;
+// silenciar de nuevo reemplaza el silencio anterior
+func (s *NewsService) MuteUser(userID uuid.UUID, req models.MuteUserRequest, adminID uuid.UUID) error {
+	var mutedUntil *time.Time
+	if req.DurationHours != nil {
+		until := time.Now().Add(time.Duration(*req.DurationHours) * time.Hour)
+		mutedUntil = &until
+	}
+
+	var exists bool
+	if err := s.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)", userID).Scan(&exists); err != nil {
+		return fmt.Errorf("error verificando usuario: %v", err)
+	}
+	if !exists {
+		return fmt.Errorf("usuario no encontrado")
+	}
+
+	_, err := s.DB.Exec(`
+		INSERT INTO news_muted_users (user_id, reason, muted_until, muted_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE SET
+		    reason = EXCLUDED.reason,
+		    muted_until = EXCLUDED.muted_until,
+		    muted_by = EXCLUDED.muted_by,
+		    created_at = NOW()
+	`, userID, req.Reason, mutedUntil, adminID)
+	if err != nil {
+		return fmt.Errorf("error silenciando usuario: %v", err)
+	}
+	return nil
+}
+
+// UnmuteUser vuelve a permitir que el usuario comente
+func (s *NewsService) UnmuteUser(userID uuid.UUID) error {
+	result, err := s.DB.Exec("DELETE FROM news_muted_users WHERE user_id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("error quitando silencio: %v", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("el usuario no está silenciado")
+	}
+	return nil
+}
+
+// GetMutedUsers obtiene los usuarios con silencio vigente
+func (s *NewsService) GetMutedUsers() ([]models.NewsMutedUser, error) {
+	rows, err := s.DB.Query(`
+		SELECT m.user_id, u.first_name || ' ' || u.last_name, u.email, m.reason, m.muted_until, m.muted_by, m.created_at
+		FROM news_muted_users m JOIN users u ON u.id = m.user_id
+		WHERE m.muted_until IS NULL OR m.muted_until > NOW()
+		ORDER BY m.created_at DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo usuarios silenciados: %v", err)
+	}
+	defer rows.Close()
+
+	users := []models.NewsMutedUser{}
+	for rows.Next() {
+		var m models.NewsMutedUser
+		if err := rows.Scan(&m.UserID, &m.UserName, &m.Email, &m.Reason, &m.MutedUntil, &m.MutedBy, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error escaneando usuario silenciado: %v", err)
+		}
+		users = append(users, m)
+	}
+
+	return users, nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"tradeoptix-back/internal/models"
+)
+
+// NewsFeed datos del canal con los que se genera un feed RSS o Atom
+type NewsFeed struct {
+	Title       string
+	Description string
+	SiteURL     string // Sitio web; los enlaces de las noticias son SiteURL/news/{id}
+	SelfURL     string // URL pública del propio feed
+	Items       []models.MarketNews
+}
+
+// Updated obtiene la fecha de la última modificación entre las noticias del feed
+func (f *NewsFeed) Updated() time.Time {
+	var updated time.Time
+	for _, news := range f.Items {
+		if t := itemUpdated(news); t.After(updated) {
+			updated = t
+		}
+	}
+	return updated
+}
+
+func (f *NewsFeed) itemURL(news models.MarketNews) string {
+	return fmt.Sprintf("%s/news/%s", strings.TrimRight(f.SiteURL, "/"), news.ID)
+}
+
+// itemUpdated fecha de actualización visible de una noticia: una noticia programada
+// se edita antes de publicarse, por lo que nunca es anterior a su publicación
+func itemUpdated(news models.MarketNews) time.Time {
+	if news.UpdatedAt.After(news.PublishedAt) {
+		return news.UpdatedAt.UTC()
+	}
+	return news.PublishedAt.UTC()
+}
+
+type rssDocument struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	AtomNS    string     `xml:"xmlns:atom,attr"`
+	ContentNS string     `xml:"xmlns:content,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Category    string  `xml:"category"`
+	Description string  `xml:"description,omitempty"`
+	Content     string  `xml:"content:encoded"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS genera el feed en formato RSS 2.0
+func (f *NewsFeed) RSS() ([]byte, error) {
+	channel := rssChannel{
+		Title:       f.Title,
+		Link:        f.SiteURL,
+		Description: f.Description,
+		Language:    "es",
+		AtomLink:    atomLink{Href: f.SelfURL, Rel: "self", Type: "application/rss+xml"},
+	}
+	if len(f.Items) > 0 {
+		channel.LastBuildDate = f.Updated().Format(time.RFC1123Z)
+	}
+
+	for _, news := range f.Items {
+		channel.Items = append(channel.Items, rssItem{
+			Title:       news.Title,
+			Link:        f.itemURL(news),
+			GUID:        rssGUID{Value: "urn:uuid:" + news.ID.String()},
+			PubDate:     news.PublishedAt.UTC().Format(time.RFC1123Z),
+			Category:    news.Category,
+			Description: stringValue(news.Summary),
+			Content:     news.ContentHTML,
+		})
+	}
+
+	return marshalFeed(rssDocument{
+		Version:   "2.0",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		Channel:   channel,
+	})
+}
+
+type atomDocument struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Author   atomAuthor  `xml:"author"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID        string       `xml:"id"`
+	Title     string       `xml:"title"`
+	Updated   string       `xml:"updated"`
+	Published string       `xml:"published"`
+	Link      atomLink     `xml:"link"`
+	Category  atomCategory `xml:"category"`
+	Summary   string       `xml:"summary,omitempty"`
+	Content   atomContent  `xml:"content"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Atom genera el feed en formato Atom 1.0
+func (f *NewsFeed) Atom() ([]byte, error) {
+	// Sin noticias se usa la fecha actual, ya que updated es obligatorio
+	updated := f.Updated()
+	if updated.IsZero() {
+		updated = time.Now().UTC()
+	}
+
+	doc := atomDocument{
+		ID:       f.SelfURL,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  updated.Format(time.RFC3339),
+		Author:   atomAuthor{Name: f.Title},
+		Links: []atomLink{
+			{Href: f.SelfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: f.SiteURL, Rel: "alternate", Type: "text/html"},
+		},
+	}
+
+	for _, news := range f.Items {
+		doc.Entries = append(doc.Entries, atomEntry{
+			ID:        "urn:uuid:" + news.ID.String(),
+			Title:     news.Title,
+			Updated:   itemUpdated(news).Format(time.RFC3339),
+			Published: news.PublishedAt.UTC().Format(time.RFC3339),
+			Link:      atomLink{Href: f.itemURL(news), Rel: "alternate", Type: "text/html"},
+			Category:  atomCategory{Term: news.Category},
+			Summary:   stringValue(news.Summary),
+			Content:   atomContent{Type: "html", Value: news.ContentHTML},
+		})
+	}
+
+	return marshalFeed(doc)
+}
+
+func marshalFeed(doc interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error generando feed: %v", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,455 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// maxFeedSize tamaño máximo de la respuesta de una fuente (5MB)
+const maxFeedSize = 5 * 1024 * 1024
+
+// maxIngestedItemsPerPoll artículos que se revisan por consulta; el resto se ignora hasta la siguiente
+const maxIngestedItemsPerPoll = 50
+
+// maxSourceURLLength largo de las columnas source_guid, source_url e image_url
+const maxSourceURLLength = 500
+
+// NewsIngestionService importa noticias desde fuentes externas RSS/Atom. Los artículos nuevos
+// quedan como borradores sin autor, por lo que cualquier admin puede aprobarlos.
+type NewsIngestionService struct {
+	DB        *sql.DB
+	Client    *http.Client
+	UserAgent string
+}
+
+func NewNewsIngestionService(db *sql.DB, timeout time.Duration) *NewsIngestionService {
+	return &NewsIngestionService{
+		DB:        db,
+		Client:    &http.Client{Timeout: timeout},
+		UserAgent: "TradeOptix-NewsIngestion/1.0",
+	}
+}
+
+// newsSourceColumns columnas de news_sources en el orden que espera scanNewsSource
+const newsSourceColumns = `id, name, feed_url, category, category_map, is_active, etag, last_modified,
+		       last_polled_at, last_error, created_by, created_at, updated_at`
+
+func scanNewsSource(row rowScanner, source *models.NewsSource) error {
+	var categoryMap []byte
+	err := row.Scan(&source.ID, &source.Name, &source.FeedURL, &source.Category, &categoryMap, &source.IsActive,
+		&source.ETag, &source.LastModified, &source.LastPolledAt, &source.LastError,
+		&source.CreatedBy, &source.CreatedAt, &source.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(categoryMap, &source.CategoryMap)
+}
+
+// validateSourceCategories verifica la categoría por defecto y los destinos del mapeo
+func (s *NewsIngestionService) validateSourceCategories(category string, categoryMap map[string]string) error {
+	if err := validateNewsCategory(s.DB, category); err != nil {
+		return err
+	}
+	for _, target := range categoryMap {
+		if err := validateNewsCategory(s.DB, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeCategoryMap pasa las claves a minúsculas para compararlas sin distinguir mayúsculas
+func normalizeCategoryMap(categoryMap map[string]string) map[string]string {
+	normalized := make(map[string]string, len(categoryMap))
+	for from, to := range categoryMap {
+		normalized[strings.ToLower(strings.TrimSpace(from))] = to
+	}
+	return normalized
+}
+
+// validateFeedURL acepta solo URLs http o https
+func validateFeedURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("URL de feed inválida")
+	}
+	return nil
+}
+
+// GetSources obtiene todas las fuentes registradas
+func (s *NewsIngestionService) GetSources() ([]models.NewsSource, error) {
+	rows, err := s.DB.Query("SELECT " + newsSourceColumns + " FROM news_sources ORDER BY name ASC")
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo fuentes: %v", err)
+	}
+	defer rows.Close()
+
+	var sources []models.NewsSource
+	for rows.Next() {
+		var source models.NewsSource
+		if err := scanNewsSource(rows, &source); err != nil {
+			return nil, fmt.Errorf("error escaneando fuente: %v", err)
+		}
+		sources = append(sources, source)
+	}
+
+	return sources, nil
+}
+
+// GetSourceByID obtiene una fuente
+func (s *NewsIngestionService) GetSourceByID(id uuid.UUID) (*models.NewsSource, error) {
+	var source models.NewsSource
+	err := scanNewsSource(s.DB.QueryRow("SELECT "+newsSourceColumns+" FROM news_sources WHERE id = $1", id), &source)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("fuente no encontrada")
+		}
+		return nil, fmt.Errorf("error obteniendo fuente: %v", err)
+	}
+	return &source, nil
+}
+
+// CreateSource registra una nueva fuente
+func (s *NewsIngestionService) CreateSource(req models.CreateNewsSourceRequest, adminID uuid.UUID) (*models.NewsSource, error) {
+	if req.Category == "" {
+		req.Category = "general"
+	}
+	if err := validateFeedURL(req.FeedURL); err != nil {
+		return nil, err
+	}
+	if err := s.validateSourceCategories(req.Category, req.CategoryMap); err != nil {
+		return nil, err
+	}
+
+	var exists bool
+	if err := s.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM news_sources WHERE feed_url = $1)", req.FeedURL).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error verificando fuente: %v", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("ya existe una fuente con esa URL")
+	}
+
+	categoryMap, err := json.Marshal(normalizeCategoryMap(req.CategoryMap))
+	if err != nil {
+		return nil, fmt.Errorf("error serializando mapeo de categorías: %v", err)
+	}
+
+	var source models.NewsSource
+	err = scanNewsSource(s.DB.QueryRow(`
+		INSERT INTO news_sources (name, feed_url, category, category_map, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+newsSourceColumns,
+		req.Name, req.FeedURL, req.Category, categoryMap, adminID,
+	), &source)
+	if err != nil {
+		return nil, fmt.Errorf("error creando fuente: %v", err)
+	}
+
+	return &source, nil
+}
+
+// UpdateSource edita una fuente; cambiar la URL reinicia la caché HTTP de la fuente
+func (s *NewsIngestionService) UpdateSource(id uuid.UUID, req models.UpdateNewsSourceRequest) (*models.NewsSource, error) {
+	source, err := s.GetSourceByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		source.Name = *req.Name
+	}
+	if req.FeedURL != nil && *req.FeedURL != source.FeedURL {
+		if err := validateFeedURL(*req.FeedURL); err != nil {
+			return nil, err
+		}
+		source.FeedURL = *req.FeedURL
+		source.ETag = nil
+		source.LastModified = nil
+	}
+	if req.Category != nil {
+		source.Category = *req.Category
+	}
+	if req.CategoryMap != nil {
+		source.CategoryMap = normalizeCategoryMap(req.CategoryMap)
+	}
+	if req.IsActive != nil {
+		source.IsActive = *req.IsActive
+	}
+	if err := s.validateSourceCategories(source.Category, source.CategoryMap); err != nil {
+		return nil, err
+	}
+
+	categoryMap, err := json.Marshal(source.CategoryMap)
+	if err != nil {
+		return nil, fmt.Errorf("error serializando mapeo de categorías: %v", err)
+	}
+
+	err = scanNewsSource(s.DB.QueryRow(`
+		UPDATE news_sources SET name = $1, feed_url = $2, category = $3, category_map = $4, is_active = $5,
+		       etag = $6, last_modified = $7
+		WHERE id = $8
+		RETURNING `+newsSourceColumns,
+		source.Name, source.FeedURL, source.Category, categoryMap, source.IsActive,
+		source.ETag, source.LastModified, id,
+	), source)
+	if err != nil {
+		return nil, fmt.Errorf("error actualizando fuente: %v", err)
+	}
+
+	return source, nil
+}
+
+// DeleteSource elimina una fuente; las noticias ya importadas se conservan
+func (s *NewsIngestionService) DeleteSource(id uuid.UUID) error {
+	result, err := s.DB.Exec("DELETE FROM news_sources WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("error eliminando fuente: %v", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("fuente no encontrada")
+	}
+	return nil
+}
+
+// PollSource consulta una fuente de inmediato, aunque esté desactivada
+func (s *NewsIngestionService) PollSource(id uuid.UUID) (*models.NewsIngestionResult, error) {
+	source, err := s.GetSourceByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.pollSource(source)
+}
+
+// RunIngestion consulta todas las fuentes activas. Se ejecuta periódicamente desde el scheduler;
+// el error de una fuente queda registrado en ella y no detiene a las demás.
+func (s *NewsIngestionService) RunIngestion() error {
+	sources, err := s.GetSources()
+	if err != nil {
+		return err
+	}
+
+	for i := range sources {
+		if !sources[i].IsActive {
+			continue
+		}
+		result, err := s.pollSource(&sources[i])
+		if err != nil {
+			fmt.Printf("Error importando noticias de %s: %v\n", sources[i].Name, err)
+			continue
+		}
+		if result.Imported > 0 {
+			fmt.Printf("Importadas %d noticias de %s\n", result.Imported, sources[i].Name)
+		}
+	}
+
+	return nil
+}
+
+// pollSource descarga el feed con GET condicional, importa los artículos nuevos y registra el resultado
+func (s *NewsIngestionService) pollSource(source *models.NewsSource) (*models.NewsIngestionResult, error) {
+	result, etag, lastModified, err := s.fetchAndImport(source)
+
+	var lastError *string
+	if err != nil {
+		message := err.Error()
+		lastError = &message
+	} else if !result.NotModified {
+		source.ETag, source.LastModified = etag, lastModified
+		if result.Failed > 0 {
+			message := fmt.Sprintf("%d artículos no se pudieron importar", result.Failed)
+			lastError = &message
+		}
+	}
+
+	_, dbErr := s.DB.Exec(`
+		UPDATE news_sources SET last_polled_at = NOW(), last_error = $1, etag = $2, last_modified = $3
+		WHERE id = $4
+	`, lastError, source.ETag, source.LastModified, source.ID)
+	if dbErr != nil && err == nil {
+		err = fmt.Errorf("error registrando consulta de la fuente: %v", dbErr)
+	}
+
+	return result, err
+}
+
+func (s *NewsIngestionService) fetchAndImport(source *models.NewsSource) (*models.NewsIngestionResult, *string, *string, error) {
+	result := &models.NewsIngestionResult{SourceID: source.ID}
+
+	req, err := http.NewRequest(http.MethodGet, source.FeedURL, nil)
+	if err != nil {
+		return result, nil, nil, fmt.Errorf("URL de feed inválida: %v", err)
+	}
+	req.Header.Set("User-Agent", s.UserAgent)
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.8")
+	if source.ETag != nil {
+		req.Header.Set("If-None-Match", *source.ETag)
+	}
+	if source.LastModified != nil {
+		req.Header.Set("If-Modified-Since", *source.LastModified)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return result, nil, nil, fmt.Errorf("error consultando feed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		result.NotModified = true
+		return result, nil, nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return result, nil, nil, fmt.Errorf("el feed respondió %d", resp.StatusCode)
+	}
+
+	items, err := parseFeed(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return result, nil, nil, err
+	}
+	if len(items) > maxIngestedItemsPerPoll {
+		items = items[:maxIngestedItemsPerPoll]
+	}
+	result.Fetched = len(items)
+
+	// Un artículo que falla no impide importar el resto ni guardar la caché HTTP de la fuente
+	for _, item := range items {
+		imported, err := s.importItem(source, item)
+		if err != nil {
+			fmt.Printf("Error importando artículo %q de %s: %v\n", item.Title, source.Name, err)
+			result.Failed++
+			continue
+		}
+		if imported {
+			result.Imported++
+		} else {
+			result.Duplicates++
+		}
+	}
+
+	return result, optionalHeader(resp, "ETag"), optionalHeader(resp, "Last-Modified"), nil
+}
+
+func optionalHeader(resp *http.Response, key string) *string {
+	if value := resp.Header.Get(key); value != "" {
+		return &value
+	}
+	return nil
+}
+
+// importItem crea el borrador de un artículo si no fue importado antes, ya sea desde esta fuente
+// (mismo GUID) o desde otra (misma URL). Devuelve false si es un duplicado o no tiene título.
+// Los enlaces e imágenes más largos que sus columnas se descartan: truncados no serían URLs válidas.
+func (s *NewsIngestionService) importItem(source *models.NewsSource, item feedItem) (bool, error) {
+	var link *string
+	if item.Link != "" && len(item.Link) <= maxSourceURLLength && isSafeURL(item.Link) {
+		link = &item.Link
+	}
+
+	guid := item.GUID
+	if guid == "" && link != nil {
+		guid = *link
+	}
+	if guid == "" || item.Title == "" {
+		return false, nil
+	}
+
+	var exists bool
+	err := s.DB.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM market_news WHERE (source_id = $1 AND source_guid = $2) OR source_url = $3)
+	`, source.ID, truncateRunes(guid, maxSourceURLLength), link).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("error verificando duplicados: %v", err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	content := ingestedContent(source, item, link)
+	contentHTML := renderNewsContent(content)
+
+	var imageURL *string
+	if item.ImageURL != "" && len(item.ImageURL) <= maxSourceURLLength && isSafeURL(item.ImageURL) &&
+		strings.HasPrefix(item.ImageURL, "http") {
+		imageURL = &item.ImageURL
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// El índice único por (source_id, source_guid) cubre la carrera entre dos consultas simultáneas
+	var newsID uuid.UUID
+	err = tx.QueryRow(`
+		INSERT INTO market_news (title, content, content_html, summary, summary_generated, image_url, category, priority,
+		                         source_id, source_guid, source_url)
+		VALUES ($1, $2, $3, $4, true, $5, $6, 1, $7, $8, $9)
+		ON CONFLICT (source_id, source_guid) WHERE source_guid IS NOT NULL DO NOTHING
+		RETURNING id
+	`, truncateRunes(item.Title, 255), content, contentHTML, newsSummary(contentHTML), imageURL,
+		mapSourceCategory(source, item.Categories), source.ID, truncateRunes(guid, maxSourceURLLength), link).Scan(&newsID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error importando noticia: %v", err)
+	}
+
+	if err := recordRevision(tx, newsID, nil); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error importando noticia: %v", err)
+	}
+
+	return true, nil
+}
+
+// mapSourceCategory usa la primera categoría del artículo presente en el mapeo de la fuente
+func mapSourceCategory(source *models.NewsSource, categories []string) string {
+	for _, category := range categories {
+		if mapped, ok := source.CategoryMap[strings.ToLower(strings.TrimSpace(category))]; ok {
+			return mapped
+		}
+	}
+	return source.Category
+}
+
+// ingestedContent arma el Markdown del borrador: el texto del artículo original (sin su HTML)
+// y el enlace a la fuente para que el editor lo reescriba o lo cite
+func ingestedContent(source *models.NewsSource, item feedItem, link *string) string {
+	text := htmlToText(sanitizeHTML(item.Content))
+	if text == "" {
+		text = item.Title
+	}
+
+	attribution := "Fuente: " + source.Name
+	if link != nil {
+		// Los paréntesis cerrarían el enlace Markdown antes de tiempo
+		escaped := strings.NewReplacer("(", "%28", ")", "%29", " ", "%20").Replace(*link)
+		attribution = fmt.Sprintf("Fuente: [%s](%s)", strings.NewReplacer("[", "(", "]", ")").Replace(source.Name), escaped)
+	}
+	return text + "\n\n" + attribution
+}
+
+// truncateRunes corta el texto a maxRunes caracteres sin partir caracteres multibyte
+func truncateRunes(text string, maxRunes int) string {
+	if utf8.RuneCountInString(text) <= maxRunes {
+		return text
+	}
+	return string([]rune(text)[:maxRunes])
+}
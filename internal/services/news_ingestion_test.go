@@ -0,0 +1,363 @@
+package services
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+// openTestDB conecta a la base de datos de TEST_DATABASE_URL y aplica las migraciones;
+// sin ella el test se omite
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL no configurada")
+	}
+
+	db, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		t.Fatalf("driver de migraciones: %v", err)
+	}
+	m, err := migrate.NewWithDatabaseInstance("file://../../migrations", "postgres", driver)
+	if err != nil {
+		t.Fatalf("migraciones: %v", err)
+	}
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		t.Fatalf("migraciones: %v", err)
+	}
+	return db
+}
+
+// fakeFeedServer sirve un feed con ETag y responde 304 al GET condicional
+type fakeFeedServer struct {
+	*httptest.Server
+	mu          sync.Mutex
+	contentType string
+	body        string
+	etag        string
+	conditional []string // If-None-Match recibidos
+}
+
+func newFakeFeedServer(t *testing.T, contentType, body, etag string) *fakeFeedServer {
+	f := &fakeFeedServer{contentType: contentType, body: body, etag: etag}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		match := r.Header.Get("If-None-Match")
+		f.conditional = append(f.conditional, match)
+		if match != "" && match == f.etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", f.etag)
+		w.Header().Set("Content-Type", f.contentType)
+		w.Write([]byte(f.body))
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeFeedServer) update(body, etag string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.body, f.etag = body, etag
+}
+
+func (f *fakeFeedServer) lastConditional() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.conditional[len(f.conditional)-1]
+}
+
+const testRSSFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/" xmlns:media="http://search.yahoo.com/mrss/">
+<channel>
+  <title>Fuente de prueba</title>
+  <item>
+    <guid>rss-1</guid>
+    <title>Bitcoin  supera
+      los 100k</title>
+    <link>https://example.com/articulos/1</link>
+    <description>Resumen corto</description>
+    <content:encoded><![CDATA[<p>El <strong>precio</strong> subió.</p><script>alert(1)</script>]]></content:encoded>
+    <category>Crypto</category>
+    <enclosure url="https://example.com/img/1.jpg" type="image/jpeg" length="1"/>
+  </item>
+  <item>
+    <title>Sin GUID</title>
+    <link>https://example.com/articulos/2</link>
+    <description>Precio del caf&eacute; sin declarar la entidad</description>
+    <category>Otros</category>
+    <media:content url="https://example.com/img/2.png" medium="image"/>
+  </item>
+  <item>
+    <guid>rss-3</guid>
+    <title>Enlace demasiado largo</title>
+    <link>https://example.com/` + "LONG" + `</link>
+    <description>Texto</description>
+    <enclosure url="https://example.com/` + "LONG" + `.jpg" type="image/jpeg" length="1"/>
+  </item>
+  <item>
+    <guid>rss-4</guid>
+    <description>Sin título</description>
+  </item>
+  <item>
+    <guid>rss-5</guid>
+    <title>Categoría inexistente</title>
+    <link>https://example.com/articulos/5</link>
+    <category>Roto</category>
+  </item>
+</channel>
+</rss>`
+
+const testAtomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Atom de prueba</title>
+  <entry>
+    <id>urn:uuid:atom-1</id>
+    <title>Misma noticia, otra fuente</title>
+    <link rel="alternate" href="https://example.com/articulos/1"/>
+    <summary>Ya importada por URL</summary>
+  </entry>
+  <entry>
+    <id>urn:uuid:atom-2</id>
+    <title type="html">Análisis semanal</title>
+    <link rel="self" href="https://example.com/atom/2.xml"/>
+    <link rel="alternate" type="text/html" href="https://example.com/analisis/2"/>
+    <link rel="enclosure" type="image/png" href="https://example.com/img/a2.png"/>
+    <summary>Resumen</summary>
+    <content type="html">&lt;p&gt;Contenido completo&lt;/p&gt;</content>
+    <category term="Analysis"/>
+  </entry>
+</feed>`
+
+func testRSS() string {
+	return strings.ReplaceAll(testRSSFeed, "LONG", strings.Repeat("a", maxSourceURLLength))
+}
+
+func TestParseFeedRSS(t *testing.T) {
+	items, err := parseFeed(strings.NewReader(testRSS()))
+	if err != nil {
+		t.Fatalf("parseFeed: %v", err)
+	}
+	if len(items) != 5 {
+		t.Fatalf("len(items) = %d, se esperaban 5", len(items))
+	}
+
+	first := items[0]
+	if first.GUID != "rss-1" || first.Title != "Bitcoin supera los 100k" || first.Link != "https://example.com/articulos/1" {
+		t.Errorf("primer artículo = %+v", first)
+	}
+	if !strings.Contains(first.Content, "<strong>precio</strong>") {
+		t.Errorf("se esperaba content:encoded en lugar de la descripción: %q", first.Content)
+	}
+	if first.ImageURL != "https://example.com/img/1.jpg" || len(first.Categories) != 1 || first.Categories[0] != "Crypto" {
+		t.Errorf("imagen o categorías = %q %v", first.ImageURL, first.Categories)
+	}
+
+	second := items[1]
+	if second.GUID != "" || second.ImageURL != "https://example.com/img/2.png" {
+		t.Errorf("segundo artículo = %+v", second)
+	}
+	if !strings.Contains(second.Content, "café") {
+		t.Errorf("las entidades HTML no se decodificaron: %q", second.Content)
+	}
+}
+
+func TestParseFeedAtom(t *testing.T) {
+	items, err := parseFeed(strings.NewReader(testAtomFeed))
+	if err != nil {
+		t.Fatalf("parseFeed: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("len(items) = %d, se esperaban 2", len(items))
+	}
+
+	if items[0].Content != "Ya importada por URL" {
+		t.Errorf("sin content se esperaba el summary: %q", items[0].Content)
+	}
+	entry := items[1]
+	if entry.GUID != "urn:uuid:atom-2" || entry.Link != "https://example.com/analisis/2" {
+		t.Errorf("GUID o enlace = %q %q", entry.GUID, entry.Link)
+	}
+	if entry.ImageURL != "https://example.com/img/a2.png" || entry.Content != "<p>Contenido completo</p>" {
+		t.Errorf("imagen o contenido = %q %q", entry.ImageURL, entry.Content)
+	}
+	if len(entry.Categories) != 1 || entry.Categories[0] != "Analysis" {
+		t.Errorf("categorías = %v", entry.Categories)
+	}
+}
+
+func TestParseFeedInvalid(t *testing.T) {
+	for _, body := range []string{"", "no es xml", `<html><body></body></html>`} {
+		if _, err := parseFeed(strings.NewReader(body)); err == nil {
+			t.Errorf("parseFeed(%q): se esperaba error", body)
+		}
+	}
+}
+
+// ingestedNews noticia importada, tal como quedó en la base de datos
+type ingestedNews struct {
+	category  string
+	status    models.NewsStatus
+	sourceURL *string
+	imageURL  *string
+	createdBy *uuid.UUID
+	revisions int
+}
+
+func loadIngestedNews(t *testing.T, db *sql.DB, sourceID uuid.UUID) map[string]ingestedNews {
+	t.Helper()
+	rows, err := db.Query(`
+		SELECT n.source_guid, n.category, n.status, n.source_url, n.image_url, n.created_by,
+		       (SELECT COUNT(*) FROM market_news_revisions r WHERE r.news_id = n.id)
+		FROM market_news n WHERE n.source_id = $1
+	`, sourceID)
+	if err != nil {
+		t.Fatalf("consultando noticias importadas: %v", err)
+	}
+	defer rows.Close()
+
+	news := map[string]ingestedNews{}
+	for rows.Next() {
+		var guid string
+		var n ingestedNews
+		if err := rows.Scan(&guid, &n.category, &n.status, &n.sourceURL, &n.imageURL, &n.createdBy, &n.revisions); err != nil {
+			t.Fatalf("escaneando noticia importada: %v", err)
+		}
+		news[guid] = n
+	}
+	return news
+}
+
+func createTestSource(t *testing.T, db *sql.DB, feedURL, category, categoryMap string) *models.NewsSource {
+	t.Helper()
+	var id uuid.UUID
+	err := db.QueryRow(`
+		INSERT INTO news_sources (name, feed_url, category, category_map) VALUES ($1, $2, $3, $4) RETURNING id
+	`, "Prueba "+feedURL, feedURL, category, categoryMap).Scan(&id)
+	if err != nil {
+		t.Fatalf("creando fuente: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM market_news WHERE source_id = $1", id)
+		db.Exec("DELETE FROM news_sources WHERE id = $1", id)
+	})
+
+	service := &NewsIngestionService{DB: db}
+	source, err := service.GetSourceByID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return source
+}
+
+func TestNewsIngestionPoll(t *testing.T) {
+	db := openTestDB(t)
+	service := NewNewsIngestionService(db, 5*time.Second)
+
+	rssServer := newFakeFeedServer(t, "application/rss+xml", testRSS(), `"v1"`)
+	// "roto" mapea a una categoría que no cabe en la columna, así ese artículo falla al insertarse
+	rssSource := createTestSource(t, db, rssServer.URL+"/rss", "general",
+		`{"crypto": "crypto", "roto": "`+strings.Repeat("x", 101)+`"}`)
+
+	// Primera consulta: se importan los artículos válidos; el que falla no detiene al resto
+	result, err := service.PollSource(rssSource.ID)
+	if err != nil {
+		t.Fatalf("PollSource: %v", err)
+	}
+	if result.Fetched != 5 || result.Imported != 3 || result.Duplicates != 1 || result.Failed != 1 {
+		t.Errorf("primera consulta = %+v, se esperaban 5 leídos, 3 importados, 1 omitido y 1 fallido", result)
+	}
+
+	news := loadIngestedNews(t, db, rssSource.ID)
+	if len(news) != 3 {
+		t.Fatalf("noticias importadas = %d, se esperaban 3", len(news))
+	}
+	for guid, n := range news {
+		if n.status != models.NewsStatusDraft || n.createdBy != nil || n.revisions != 1 {
+			t.Errorf("%s: estado %s, autor %v, %d versiones; se esperaba borrador sin autor con 1 versión",
+				guid, n.status, n.createdBy, n.revisions)
+		}
+	}
+	if n := news["rss-1"]; n.category != "crypto" || n.imageURL == nil {
+		t.Errorf("rss-1: categoría %s, imagen %v; se esperaba crypto por category_map", n.category, n.imageURL)
+	}
+	// Sin GUID se usa el enlace
+	if n, ok := news["https://example.com/articulos/2"]; !ok || n.category != "general" {
+		t.Errorf("artículo sin GUID = %+v, %v; se esperaba la categoría por defecto", n, ok)
+	}
+	if n := news["rss-3"]; n.sourceURL != nil || n.imageURL != nil {
+		t.Errorf("rss-3: los enlaces de más de %d caracteres deberían descartarse", maxSourceURLLength)
+	}
+
+	// La caché HTTP se guarda aunque un artículo haya fallado
+	source, err := service.GetSourceByID(rssSource.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source.ETag == nil || *source.ETag != `"v1"` || source.LastError == nil {
+		t.Errorf("fuente tras la consulta: etag %v, último error %v", source.ETag, source.LastError)
+	}
+
+	// Sin cambios el servidor responde 304
+	result, err = service.PollSource(rssSource.ID)
+	if err != nil {
+		t.Fatalf("PollSource: %v", err)
+	}
+	if !result.NotModified || result.Imported != 0 {
+		t.Errorf("segunda consulta = %+v, se esperaba 304", result)
+	}
+	if got := rssServer.lastConditional(); got != `"v1"` {
+		t.Errorf("If-None-Match = %q, se esperaba el ETag guardado", got)
+	}
+
+	// Con un artículo nuevo solo se importa ese; los demás son duplicados por GUID
+	rssServer.update(strings.Replace(testRSS(), "<channel>", `<channel>
+  <item><guid>rss-6</guid><title>Nuevo</title><link>https://example.com/articulos/6</link></item>`, 1), `"v2"`)
+	result, err = service.PollSource(rssSource.ID)
+	if err != nil {
+		t.Fatalf("PollSource: %v", err)
+	}
+	if result.Imported != 1 || result.Duplicates != 4 {
+		t.Errorf("tercera consulta = %+v, se esperaba 1 importado y 4 duplicados", result)
+	}
+	if source, _ := service.GetSourceByID(rssSource.ID); source.ETag == nil || *source.ETag != `"v2"` {
+		t.Errorf("etag = %v, se esperaba \"v2\"", source.ETag)
+	}
+
+	// Otra fuente que enlaza el mismo artículo no lo vuelve a importar
+	atomServer := newFakeFeedServer(t, "application/atom+xml", testAtomFeed, `"a1"`)
+	atomSource := createTestSource(t, db, atomServer.URL+"/atom", "markets", `{"analysis": "analysis"}`)
+	result, err = service.PollSource(atomSource.ID)
+	if err != nil {
+		t.Fatalf("PollSource: %v", err)
+	}
+	if result.Imported != 1 || result.Duplicates != 1 {
+		t.Errorf("consulta Atom = %+v, se esperaba 1 importado y 1 duplicado por URL", result)
+	}
+	atomNews := loadIngestedNews(t, db, atomSource.ID)
+	if n, ok := atomNews["urn:uuid:atom-2"]; !ok || n.category != "analysis" || n.status != models.NewsStatusDraft {
+		t.Errorf("entrada Atom = %+v, %v", n, ok)
+	}
+}
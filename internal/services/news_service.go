@@ -2,65 +2,259 @@ package services
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"html"
+	"strings"
 	"time"
 
 	"tradeoptix-back/internal/models"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type NewsService struct {
-	DB *sql.DB
+	DB            *sql.DB
+	commentFilter *commentFilter
 }
 
 func NewNewsService(db *sql.DB) *NewsService {
-	return &NewsService{DB: db}
+	return &NewsService{DB: db, commentFilter: newCommentFilter(nil)}
 }
 
-// CreateNews crea una nueva noticia
+// SetBlockedWords agrega palabras a la lista por defecto del filtro de comentarios
+func (s *NewsService) SetBlockedWords(words []string) {
+	s.commentFilter = newCommentFilter(words)
+}
+
+// newsColumns columnas de market_news en el orden que espera scanNews
+const newsColumns = `id, title, content, content_html, summary, summary_generated, image_url, image_media_id,
+		       (SELECT renditions FROM media WHERE media.id = market_news.image_media_id),
+		       category,
+		       ARRAY(SELECT t.name FROM market_news_tags mt JOIN news_tags t ON t.id = mt.tag_id
+		             WHERE mt.news_id = market_news.id ORDER BY t.name),
+		       priority, is_active,
+		       status, reviewed_by, reviewed_at, review_note,
+		       published_at, expire_at, source_id, source_url, created_by, created_at, updated_at`
+
+// publishedNewsCondition noticias publicadas y activas dentro de su ventana de publicación
+const publishedNewsCondition = "status = 'published' AND is_active = true AND published_at <= NOW() AND (expire_at IS NULL OR expire_at > NOW())"
+
+// newsTagCondition noticias con la etiqueta cuyo slug es el parámetro indicado
+func newsTagCondition(argIndex int) string {
+	return fmt.Sprintf(`EXISTS (SELECT 1 FROM market_news_tags mt JOIN news_tags t ON t.id = mt.tag_id
+		WHERE mt.news_id = market_news.id AND t.slug = $%d)`, argIndex)
+}
+
+// newsSearchConfig configuración de texto completo en español sin acentos (ver migración 000014)
+const newsSearchConfig = "es_unaccent"
+
+// maxSearchQueryLength largo máximo de una búsqueda
+const maxSearchQueryLength = 200
+
+// Marcadores que ts_headline inserta alrededor de los términos encontrados. El resultado se escapa
+// como HTML en Go y recién entonces los marcadores se reemplazan por <mark>, para que el texto de la
+// noticia nunca llegue sin escapar al cliente. Son caracteres de uso privado que se quitan del texto.
+const (
+	searchHighlightStart = "\uE000"
+	searchHighlightStop  = "\uE001"
+)
+
+var searchHighlightReplacer = strings.NewReplacer(searchHighlightStart, "<mark>", searchHighlightStop, "</mark>")
+
+// searchHighlightHTML escapa un texto resaltado por ts_headline y convierte sus marcadores en <mark>
+func searchHighlightHTML(text string) string {
+	return searchHighlightReplacer.Replace(html.EscapeString(text))
+}
+
+// rowScanner permite escanear tanto *sql.Row como *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanNews escanea las columnas de newsColumns seguidas de las columnas adicionales en extra.
+// Las noticias anteriores al soporte de Markdown se renderizan al vuelo.
+func scanNews(row rowScanner, news *models.MarketNews, extra ...interface{}) error {
+	var contentHTML sql.NullString
+	var imageRenditions []byte
+	dest := []interface{}{
+		&news.ID, &news.Title, &news.Content, &contentHTML, &news.Summary, &news.SummaryGenerated,
+		&news.ImageURL, &news.ImageMediaID, &imageRenditions,
+		&news.Category, pq.Array(&news.Tags), &news.Priority, &news.IsActive,
+		&news.Status, &news.ReviewedBy, &news.ReviewedAt, &news.ReviewNote,
+		&news.PublishedAt, &news.ExpireAt, &news.SourceID, &news.SourceURL, &news.CreatedBy, &news.CreatedAt, &news.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	news.ContentHTML = contentHTML.String
+	if !contentHTML.Valid {
+		news.ContentHTML = renderNewsContent(news.Content)
+	}
+	if imageRenditions != nil {
+		if err := json.Unmarshal(imageRenditions, &news.ImageRenditions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveNewsImage obtiene la URL de la imagen de la biblioteca que usará la noticia
+func (s *NewsService) resolveNewsImage(mediaID uuid.UUID) (string, error) {
+	var renditions []byte
+	err := s.DB.QueryRow("SELECT renditions FROM media WHERE id = $1", mediaID).Scan(&renditions)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("imagen no encontrada")
+		}
+		return "", fmt.Errorf("error obteniendo imagen: %v", err)
+	}
+
+	var parsed map[string]models.MediaRendition
+	if err := json.Unmarshal(renditions, &parsed); err != nil {
+		return "", fmt.Errorf("error obteniendo imagen: %v", err)
+	}
+	return mediaImageURL(parsed), nil
+}
+
+// newsSummaryLength largo del resumen generado cuando no se indica uno
+const newsSummaryLength = 280
+
+// renderNewsContent convierte el Markdown de una noticia a HTML seguro
+func renderNewsContent(source string) string {
+	return sanitizeHTML(renderMarkdown(source))
+}
+
+// newsSummary genera un resumen en texto plano a partir del HTML de la noticia
+func newsSummary(contentHTML string) *string {
+	summary := summarize(htmlToText(contentHTML), newsSummaryLength)
+	if summary == "" {
+		return nil
+	}
+	return &summary
+}
+
+// validatePublicationWindow verifica que la expiración sea posterior a la publicación
+func validatePublicationWindow(publishAt time.Time, expireAt *time.Time) error {
+	if expireAt != nil && !expireAt.After(publishAt) {
+		return fmt.Errorf("la fecha de expiración debe ser posterior a la de publicación")
+	}
+	return nil
+}
+
+// CreateNews crea una nueva noticia como borrador y guarda su primera versión.
+// Con publish_at futuro queda programada hasta esa fecha una vez aprobada.
 func (s *NewsService) CreateNews(req models.CreateNewsRequest, adminID uuid.UUID) (*models.MarketNews, error) {
+	publishAt := time.Now()
+	if req.PublishAt != nil {
+		publishAt = *req.PublishAt
+	}
+	if err := validatePublicationWindow(publishAt, req.ExpireAt); err != nil {
+		return nil, err
+	}
+	if err := validateNewsCategory(s.DB, req.Category); err != nil {
+		return nil, err
+	}
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	imageURL := req.ImageURL
+	if req.ImageMediaID != nil {
+		url, err := s.resolveNewsImage(*req.ImageMediaID)
+		if err != nil {
+			return nil, err
+		}
+		imageURL = &url
+	}
+
+	contentHTML := renderNewsContent(req.Content)
+	summary := req.Summary
+	summaryGenerated := summary == nil || strings.TrimSpace(*summary) == ""
+	if summaryGenerated {
+		summary = newsSummary(contentHTML)
+	}
+
 	query := `
-		INSERT INTO market_news (title, content, summary, image_url, category, priority, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, title, content, summary, image_url, category, priority, is_active, 
-		         published_at, created_by, created_at, updated_at
-	`
+		INSERT INTO market_news (title, content, content_html, summary, summary_generated, image_url, image_media_id, category, priority, published_at, expire_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING ` + newsColumns
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
 	var news models.MarketNews
-	err := s.DB.QueryRow(
-		query, req.Title, req.Content, req.Summary, req.ImageURL,
-		req.Category, req.Priority, adminID,
-	).Scan(
-		&news.ID, &news.Title, &news.Content, &news.Summary, &news.ImageURL,
-		&news.Category, &news.Priority, &news.IsActive, &news.PublishedAt,
-		&news.CreatedBy, &news.CreatedAt, &news.UpdatedAt,
-	)
+	err = scanNews(tx.QueryRow(
+		query, req.Title, req.Content, contentHTML, summary, summaryGenerated, imageURL, req.ImageMediaID,
+		req.Category, req.Priority, publishAt, req.ExpireAt, adminID,
+	), &news)
 
 	if err != nil {
 		return nil, fmt.Errorf("error creando noticia: %v", err)
 	}
 
+	if news.Tags, err = setNewsTags(tx, news.ID, tags); err != nil {
+		return nil, err
+	}
+
+	if err := recordRevision(tx, news.ID, &adminID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error creando noticia: %v", err)
+	}
+
 	return &news, nil
 }
 
-// GetNews obtiene todas las noticias con paginación
-func (s *NewsService) GetNews(page, limit int, category string, activeOnly bool) ([]models.MarketNews, int, error) {
+// GetNews obtiene todas las noticias con paginación. Con filter.Search filtra por texto completo
+// y ordena por relevancia.
+func (s *NewsService) GetNews(page, limit int, filter models.NewsFilter) ([]models.MarketNews, int, error) {
 	offset := (page - 1) * limit
 
 	whereClause := "WHERE 1=1"
 	args := []interface{}{}
 	argIndex := 1
+	orderBy := "priority DESC, published_at DESC"
 
-	if category != "" {
+	if filter.Category != "" {
 		whereClause += fmt.Sprintf(" AND category = $%d", argIndex)
-		args = append(args, category)
+		args = append(args, filter.Category)
+		argIndex++
+	}
+
+	if filter.Tag != "" {
+		whereClause += " AND " + newsTagCondition(argIndex)
+		args = append(args, filter.Tag)
 		argIndex++
 	}
 
-	if activeOnly {
-		whereClause += fmt.Sprintf(" AND is_active = $%d", argIndex)
-		args = append(args, true)
+	if filter.Status != "" {
+		whereClause += fmt.Sprintf(" AND status = $%d", argIndex)
+		args = append(args, filter.Status)
+		argIndex++
+	}
+
+	// Solo las noticias visibles para los usuarios: publicadas, activas y sin expirar
+	if filter.ActiveOnly {
+		whereClause += " AND " + publishedNewsCondition
+	}
+
+	if filter.Search != "" {
+		if err := validateSearchQuery(filter.Search); err != nil {
+			return nil, 0, err
+		}
+		query := fmt.Sprintf("websearch_to_tsquery('%s', $%d)", newsSearchConfig, argIndex)
+		whereClause += fmt.Sprintf(" AND search_vector @@ %s", query)
+		orderBy = fmt.Sprintf("ts_rank_cd(search_vector, %s) DESC, published_at DESC", query)
+		args = append(args, filter.Search)
 		argIndex++
 	}
 
@@ -74,13 +268,12 @@ func (s *NewsService) GetNews(page, limit int, category string, activeOnly bool)
 
 	// Obtener noticias
 	query := fmt.Sprintf(`
-		SELECT id, title, content, summary, image_url, category, priority, is_active,
-		       published_at, created_by, created_at, updated_at
+		SELECT %s
 		FROM market_news 
 		%s 
-		ORDER BY priority DESC, published_at DESC 
+		ORDER BY %s 
 		LIMIT $%d OFFSET $%d
-	`, whereClause, argIndex, argIndex+1)
+	`, newsColumns, whereClause, orderBy, argIndex, argIndex+1)
 
 	args = append(args, limit, offset)
 
@@ -93,11 +286,7 @@ func (s *NewsService) GetNews(page, limit int, category string, activeOnly bool)
 	var newsList []models.MarketNews
 	for rows.Next() {
 		var news models.MarketNews
-		err := rows.Scan(
-			&news.ID, &news.Title, &news.Content, &news.Summary, &news.ImageURL,
-			&news.Category, &news.Priority, &news.IsActive, &news.PublishedAt,
-			&news.CreatedBy, &news.CreatedAt, &news.UpdatedAt,
-		)
+		err := scanNews(rows, &news)
 		if err != nil {
 			return nil, 0, fmt.Errorf("error escaneando noticia: %v", err)
 		}
@@ -107,21 +296,94 @@ func (s *NewsService) GetNews(page, limit int, category string, activeOnly bool)
 	return newsList, total, nil
 }
 
+// SearchNews busca noticias publicadas por texto completo en título, resumen y contenido.
+// Acepta la sintaxis de búsqueda web ("frase exacta", OR, -excluir) y devuelve los
+// resultados ordenados por relevancia con los términos resaltados.
+func (s *NewsService) SearchNews(search, category string, page, limit int) ([]models.NewsSearchResult, int, error) {
+	if err := validateSearchQuery(search); err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * limit
+
+	whereClause := "WHERE " + publishedNewsCondition + " AND search_vector @@ q.query"
+	args := []interface{}{search}
+	argIndex := 2
+
+	if category != "" {
+		whereClause += fmt.Sprintf(" AND category = $%d", argIndex)
+		args = append(args, category)
+		argIndex++
+	}
+
+	from := fmt.Sprintf("FROM market_news, websearch_to_tsquery('%s', $1) AS q(query)", newsSearchConfig)
+
+	var total int
+	err := s.DB.QueryRow(fmt.Sprintf("SELECT COUNT(*) %s %s", from, whereClause), args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error contando resultados: %v", err)
+	}
+
+	// El fragmento sale del texto plano del HTML renderizado (no de la fuente Markdown); las
+	// noticias antiguas sin content_html usan la fuente
+	markers := searchHighlightStart + searchHighlightStop
+	selectors := fmt.Sprintf("StartSel=%s, StopSel=%s", searchHighlightStart, searchHighlightStop)
+	query := fmt.Sprintf(`
+		SELECT %s,
+		       ts_rank_cd(search_vector, q.query) AS rank,
+		       ts_headline('%s', translate(title, '%s', ''), q.query, '%s, HighlightAll=true'),
+		       ts_headline('%s', translate(regexp_replace(coalesce(content_html, content), '<[^>]*>', ' ', 'g'), '%s', ''), q.query,
+		                   '%s, MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=" … "')
+		%s
+		%s
+		ORDER BY rank DESC, published_at DESC
+		LIMIT $%d OFFSET $%d
+	`, newsColumns, newsSearchConfig, markers, selectors, newsSearchConfig, markers, selectors,
+		from, whereClause, argIndex, argIndex+1)
+	args = append(args, limit, offset)
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error buscando noticias: %v", err)
+	}
+	defer rows.Close()
+
+	var results []models.NewsSearchResult
+	for rows.Next() {
+		var result models.NewsSearchResult
+		err := scanNews(rows, &result.MarketNews, &result.Rank, &result.TitleHighlight, &result.Snippet)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error escaneando resultado: %v", err)
+		}
+		result.TitleHighlight = searchHighlightHTML(result.TitleHighlight)
+		// El fragmento viene de HTML: se decodifican sus entidades antes de escaparlo
+		result.Snippet = searchHighlightHTML(html.UnescapeString(result.Snippet))
+		results = append(results, result)
+	}
+
+	return results, total, nil
+}
+
+// validateSearchQuery valida el texto de una búsqueda
+func validateSearchQuery(search string) error {
+	if strings.TrimSpace(search) == "" {
+		return fmt.Errorf("la búsqueda no puede estar vacía")
+	}
+	if len(search) > maxSearchQueryLength {
+		return fmt.Errorf("la búsqueda no puede superar %d caracteres", maxSearchQueryLength)
+	}
+	return nil
+}
+
 // GetNewsByID obtiene una noticia por ID
 func (s *NewsService) GetNewsByID(id uuid.UUID) (*models.MarketNews, error) {
 	query := `
-		SELECT id, title, content, summary, image_url, category, priority, is_active,
-		       published_at, created_by, created_at, updated_at
+		SELECT ` + newsColumns + `
 		FROM market_news 
 		WHERE id = $1
 	`
 
 	var news models.MarketNews
-	err := s.DB.QueryRow(query, id).Scan(
-		&news.ID, &news.Title, &news.Content, &news.Summary, &news.ImageURL,
-		&news.Category, &news.Priority, &news.IsActive, &news.PublishedAt,
-		&news.CreatedBy, &news.CreatedAt, &news.UpdatedAt,
-	)
+	err := scanNews(s.DB.QueryRow(query, id), &news)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -133,8 +395,18 @@ func (s *NewsService) GetNewsByID(id uuid.UUID) (*models.MarketNews, error) {
 	return &news, nil
 }
 
-// UpdateNews actualiza una noticia
-func (s *NewsService) UpdateNews(id uuid.UUID, req models.UpdateNewsRequest) error {
+// UpdateNews actualiza una noticia y, si cambia su contenido, guarda una nueva versión.
+// Editar una noticia en revisión la devuelve a borrador; editar una publicada la retira y la
+// envía a revisión, para que nadie cambie contenido publicado sin una segunda aprobación.
+func (s *NewsService) UpdateNews(id uuid.UUID, req models.UpdateNewsRequest, editorID uuid.UUID) error {
+	current, err := s.GetNewsByID(id)
+	if err != nil {
+		return err
+	}
+	if current.Status == models.NewsStatusArchived {
+		return fmt.Errorf("no se puede editar una noticia archivada")
+	}
+
 	setParts := []string{}
 	args := []interface{}{}
 	argIndex := 1
@@ -146,24 +418,48 @@ func (s *NewsService) UpdateNews(id uuid.UUID, req models.UpdateNewsRequest) err
 	}
 
 	if req.Content != nil {
-		setParts = append(setParts, fmt.Sprintf("content = $%d", argIndex))
-		args = append(args, *req.Content)
-		argIndex++
+		contentHTML := renderNewsContent(*req.Content)
+		setParts = append(setParts, fmt.Sprintf("content = $%d, content_html = $%d", argIndex, argIndex+1))
+		args = append(args, *req.Content, contentHTML)
+		argIndex += 2
+
+		// Sin resumen propio, el resumen se regenera con el contenido
+		if req.Summary == nil && (current.SummaryGenerated || current.Summary == nil || *current.Summary == "") {
+			setParts = append(setParts, fmt.Sprintf("summary = $%d, summary_generated = true", argIndex))
+			args = append(args, newsSummary(contentHTML))
+			argIndex++
+		}
 	}
 
 	if req.Summary != nil {
-		setParts = append(setParts, fmt.Sprintf("summary = $%d", argIndex))
+		setParts = append(setParts, fmt.Sprintf("summary = $%d, summary_generated = false", argIndex))
 		args = append(args, *req.Summary)
 		argIndex++
 	}
 
-	if req.ImageURL != nil {
-		setParts = append(setParts, fmt.Sprintf("image_url = $%d", argIndex))
+	// Una imagen de la biblioteca reemplaza a la URL externa y viceversa
+	if req.ClearImage {
+		setParts = append(setParts, "image_url = NULL, image_media_id = NULL")
+	} else if req.ImageMediaID != nil {
+		imageURL, err := s.resolveNewsImage(*req.ImageMediaID)
+		if err != nil {
+			return err
+		}
+		setParts = append(setParts, fmt.Sprintf("image_url = $%d, image_media_id = $%d", argIndex, argIndex+1))
+		args = append(args, imageURL, *req.ImageMediaID)
+		argIndex += 2
+	} else if req.ImageURL != nil {
+		setParts = append(setParts, fmt.Sprintf("image_url = $%d, image_media_id = NULL", argIndex))
 		args = append(args, *req.ImageURL)
 		argIndex++
 	}
 
 	if req.Category != nil {
+		if *req.Category != current.Category {
+			if err := validateNewsCategory(s.DB, *req.Category); err != nil {
+				return err
+			}
+		}
 		setParts = append(setParts, fmt.Sprintf("category = $%d", argIndex))
 		args = append(args, *req.Category)
 		argIndex++
@@ -175,13 +471,50 @@ func (s *NewsService) UpdateNews(id uuid.UUID, req models.UpdateNewsRequest) err
 		argIndex++
 	}
 
+	contentChanged := len(setParts) > 0
+	if next := statusAfterEdit(current.Status); contentChanged && next != current.Status {
+		setParts = append(setParts, fmt.Sprintf("status = '%s'", next))
+	}
+
 	if req.IsActive != nil {
 		setParts = append(setParts, fmt.Sprintf("is_active = $%d", argIndex))
 		args = append(args, *req.IsActive)
 		argIndex++
 	}
 
-	if len(setParts) == 0 {
+	if req.PublishAt != nil || req.ExpireAt != nil || req.ClearExpireAt {
+		publishAt := current.PublishedAt
+		if req.PublishAt != nil {
+			publishAt = *req.PublishAt
+			setParts = append(setParts, fmt.Sprintf("published_at = $%d", argIndex))
+			args = append(args, publishAt)
+			argIndex++
+		}
+
+		expireAt := current.ExpireAt
+		if req.ClearExpireAt {
+			expireAt = nil
+			setParts = append(setParts, "expire_at = NULL")
+		} else if req.ExpireAt != nil {
+			expireAt = req.ExpireAt
+			setParts = append(setParts, fmt.Sprintf("expire_at = $%d", argIndex))
+			args = append(args, *expireAt)
+			argIndex++
+		}
+
+		if err := validatePublicationWindow(publishAt, expireAt); err != nil {
+			return err
+		}
+	}
+
+	var tags []newsTag
+	if req.Tags != nil {
+		if tags, err = normalizeTags(req.Tags); err != nil {
+			return err
+		}
+	}
+
+	if len(setParts) == 0 && req.Tags == nil {
 		return fmt.Errorf("no hay campos para actualizar")
 	}
 
@@ -195,9 +528,15 @@ func (s *NewsService) UpdateNews(id uuid.UUID, req models.UpdateNewsRequest) err
 		UPDATE market_news 
 		SET %s 
 		WHERE id = $%d
-	`, fmt.Sprintf("%v", setParts)[1:len(fmt.Sprintf("%v", setParts))-1], argIndex)
+	`, strings.Join(setParts, ", "), argIndex)
 
-	result, err := s.DB.Exec(query, args...)
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(query, args...)
 	if err != nil {
 		return fmt.Errorf("error actualizando noticia: %v", err)
 	}
@@ -207,6 +546,22 @@ func (s *NewsService) UpdateNews(id uuid.UUID, req models.UpdateNewsRequest) err
 		return fmt.Errorf("noticia no encontrada")
 	}
 
+	if req.Tags != nil {
+		if _, err := setNewsTags(tx, id, tags); err != nil {
+			return err
+		}
+	}
+
+	if contentChanged {
+		if err := recordRevision(tx, id, &editorID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error actualizando noticia: %v", err)
+	}
+
 	return nil
 }
 
@@ -226,18 +581,29 @@ func (s *NewsService) DeleteNews(id uuid.UUID) error {
 	return nil
 }
 
-// GetLatestNews obtiene las últimas noticias activas
-func (s *NewsService) GetLatestNews(limit int) ([]models.MarketNews, error) {
+// GetLatestNews obtiene las últimas noticias publicadas y vigentes, opcionalmente filtradas
+// por categoría y etiqueta (filter.Category y filter.Tag)
+func (s *NewsService) GetLatestNews(limit int, filter models.NewsFilter) ([]models.MarketNews, error) {
+	whereClause := "WHERE " + publishedNewsCondition
+	args := []interface{}{limit}
+	if filter.Category != "" {
+		args = append(args, filter.Category)
+		whereClause += fmt.Sprintf(" AND category = $%d", len(args))
+	}
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		whereClause += fmt.Sprintf(" AND %s", newsTagCondition(len(args)))
+	}
+
 	query := `
-		SELECT id, title, content, summary, image_url, category, priority, is_active,
-		       published_at, created_by, created_at, updated_at
+		SELECT ` + newsColumns + `
 		FROM market_news 
-		WHERE is_active = true 
+		` + whereClause + `
 		ORDER BY priority DESC, published_at DESC 
 		LIMIT $1
 	`
 
-	rows, err := s.DB.Query(query, limit)
+	rows, err := s.DB.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo últimas noticias: %v", err)
 	}
@@ -246,11 +612,7 @@ func (s *NewsService) GetLatestNews(limit int) ([]models.MarketNews, error) {
 	var newsList []models.MarketNews
 	for rows.Next() {
 		var news models.MarketNews
-		err := rows.Scan(
-			&news.ID, &news.Title, &news.Content, &news.Summary, &news.ImageURL,
-			&news.Category, &news.Priority, &news.IsActive, &news.PublishedAt,
-			&news.CreatedBy, &news.CreatedAt, &news.UpdatedAt,
-		)
+		err := scanNews(rows, &news)
 		if err != nil {
 			return nil, fmt.Errorf("error escaneando noticia: %v", err)
 		}
@@ -260,6 +622,40 @@ func (s *NewsService) GetLatestNews(limit int) ([]models.MarketNews, error) {
 	return newsList, nil
 }
 
+// GetScheduledNews obtiene las noticias aprobadas con publicación futura en orden de publicación
+func (s *NewsService) GetScheduledNews(page, limit int) ([]models.MarketNews, int, error) {
+	offset := (page - 1) * limit
+
+	var total int
+	err := s.DB.QueryRow("SELECT COUNT(*) FROM market_news WHERE status = 'published' AND published_at > NOW()").Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error contando noticias programadas: %v", err)
+	}
+
+	rows, err := s.DB.Query(`
+		SELECT `+newsColumns+`
+		FROM market_news
+		WHERE status = 'published' AND published_at > NOW()
+		ORDER BY published_at ASC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error obteniendo noticias programadas: %v", err)
+	}
+	defer rows.Close()
+
+	var newsList []models.MarketNews
+	for rows.Next() {
+		var news models.MarketNews
+		if err := scanNews(rows, &news); err != nil {
+			return nil, 0, fmt.Errorf("error escaneando noticia: %v", err)
+		}
+		newsList = append(newsList, news)
+	}
+
+	return newsList, total, nil
+}
+
 // GetNewsStats obtiene estadísticas de noticias
 func (s *NewsService) GetNewsStats() (*models.NewsStats, error) {
 	stats := &models.NewsStats{
@@ -278,6 +674,12 @@ func (s *NewsService) GetNewsStats() (*models.NewsStats, error) {
 		return nil, fmt.Errorf("error obteniendo noticias activas: %v", err)
 	}
 
+	// Noticias programadas
+	err = s.DB.QueryRow("SELECT COUNT(*) FROM market_news WHERE status = 'published' AND is_active = true AND published_at > NOW()").Scan(&stats.ScheduledNews)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo noticias programadas: %v", err)
+	}
+
 	// Noticias de hoy
 	err = s.DB.QueryRow(`
 		SELECT COUNT(*) FROM market_news 
@@ -287,12 +689,12 @@ func (s *NewsService) GetNewsStats() (*models.NewsStats, error) {
 		return nil, fmt.Errorf("error obteniendo noticias de hoy: %v", err)
 	}
 
-	// Noticias por categoría
+	// Noticias por categoría, incluidas las categorías sin noticias
 	rows, err := s.DB.Query(`
-		SELECT category, COUNT(*) 
-		FROM market_news 
-		WHERE is_active = true 
-		GROUP BY category
+		SELECT c.slug, COUNT(n.id)
+		FROM news_categories c
+		LEFT JOIN market_news n ON n.category = c.slug AND n.is_active = true
+		GROUP BY c.slug
 	`)
 	if err != nil {
 		return nil, fmt.Errorf("error obteniendo noticias por categoría: %v", err)
@@ -308,5 +710,9 @@ func (s *NewsService) GetNewsStats() (*models.NewsStats, error) {
 		stats.NewsByCategory[category] = count
 	}
 
+	if err := s.addAudienceStats(stats); err != nil {
+		return nil, err
+	}
+
 	return stats, nil
 }
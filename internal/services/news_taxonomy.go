@@ -0,0 +1,281 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxTagsPerNews cantidad máxima de etiquetas por noticia
+const maxTagsPerNews = 10
+
+// maxTagLength largo máximo del nombre de una etiqueta
+const maxTagLength = 50
+
+// categorySlugPattern slugs de categoría: minúsculas, dígitos y guiones
+var categorySlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// GetCategories obtiene las categorías en su orden de presentación con la cantidad de noticias de cada una
+func (s *NewsService) GetCategories(activeOnly bool) ([]models.NewsCategory, error) {
+	query := `
+		SELECT c.slug, c.name, c.description, c.sort_order, c.is_active,
+		       (SELECT COUNT(*) FROM market_news n WHERE n.category = c.slug),
+		       c.created_at, c.updated_at
+		FROM news_categories c`
+	if activeOnly {
+		query += " WHERE c.is_active = true"
+	}
+	query += " ORDER BY c.sort_order ASC, c.name ASC"
+
+	rows, err := s.DB.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo categorías: %v", err)
+	}
+	defer rows.Close()
+
+	var categories []models.NewsCategory
+	for rows.Next() {
+		var c models.NewsCategory
+		err := rows.Scan(&c.Slug, &c.Name, &c.Description, &c.SortOrder, &c.IsActive, &c.NewsCount, &c.CreatedAt, &c.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando categoría: %v", err)
+		}
+		categories = append(categories, c)
+	}
+
+	return categories, nil
+}
+
+// GetCategory obtiene una categoría por su slug
+func (s *NewsService) GetCategory(slug string) (*models.NewsCategory, error) {
+	var c models.NewsCategory
+	err := s.DB.QueryRow(`
+		SELECT slug, name, description, sort_order, is_active,
+		       (SELECT COUNT(*) FROM market_news WHERE category = $1),
+		       created_at, updated_at
+		FROM news_categories WHERE slug = $1
+	`, slug).Scan(&c.Slug, &c.Name, &c.Description, &c.SortOrder, &c.IsActive, &c.NewsCount, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("categoría no encontrada")
+		}
+		return nil, fmt.Errorf("error obteniendo categoría: %v", err)
+	}
+	return &c, nil
+}
+
+// CreateCategory crea una categoría
+func (s *NewsService) CreateCategory(req models.CreateNewsCategoryRequest) (*models.NewsCategory, error) {
+	slug := strings.TrimSpace(req.Slug)
+	if len(slug) > 100 || !categorySlugPattern.MatchString(slug) {
+		return nil, fmt.Errorf("slug de categoría inválido: usar minúsculas, números y guiones")
+	}
+
+	var exists bool
+	if err := s.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM news_categories WHERE slug = $1)", slug).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error verificando categoría: %v", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("la categoría ya existe")
+	}
+
+	_, err := s.DB.Exec(`
+		INSERT INTO news_categories (slug, name, description, sort_order)
+		VALUES ($1, $2, $3, $4)
+	`, slug, strings.TrimSpace(req.Name), req.Description, req.SortOrder)
+	if err != nil {
+		return nil, fmt.Errorf("error creando categoría: %v", err)
+	}
+
+	return s.GetCategory(slug)
+}
+
+// UpdateCategory edita el nombre, la descripción, el orden o la activación de una categoría
+func (s *NewsService) UpdateCategory(slug string, req models.UpdateNewsCategoryRequest) (*models.NewsCategory, error) {
+	result, err := s.DB.Exec(`
+		UPDATE news_categories SET
+		    name = COALESCE($1, name),
+		    description = COALESCE($2, description),
+		    sort_order = COALESCE($3, sort_order),
+		    is_active = COALESCE($4, is_active)
+		WHERE slug = $5
+	`, req.Name, req.Description, req.SortOrder, req.IsActive, slug)
+	if err != nil {
+		return nil, fmt.Errorf("error actualizando categoría: %v", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("categoría no encontrada")
+	}
+
+	return s.GetCategory(slug)
+}
+
+// DeleteCategory elimina una categoría sin noticias ni fuentes asociadas; si está en uso
+// corresponde desactivarla
+func (s *NewsService) DeleteCategory(slug string) error {
+	var inUse int
+	err := s.DB.QueryRow(`
+		SELECT (SELECT COUNT(*) FROM market_news WHERE category = $1) +
+		       (SELECT COUNT(*) FROM news_sources
+		        WHERE category = $1 OR EXISTS (SELECT 1 FROM jsonb_each_text(category_map) m WHERE m.value = $1))
+	`, slug).Scan(&inUse)
+	if err != nil {
+		return fmt.Errorf("error verificando uso de la categoría: %v", err)
+	}
+	if inUse > 0 {
+		return fmt.Errorf("categoría en uso por %d noticia(s) o fuente(s)", inUse)
+	}
+
+	result, err := s.DB.Exec("DELETE FROM news_categories WHERE slug = $1", slug)
+	if err != nil {
+		return fmt.Errorf("error eliminando categoría: %v", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("categoría no encontrada")
+	}
+	return nil
+}
+
+// validateNewsCategory verifica que la categoría exista y esté activa
+func validateNewsCategory(db *sql.DB, slug string) error {
+	var active bool
+	err := db.QueryRow("SELECT is_active FROM news_categories WHERE slug = $1", slug).Scan(&active)
+	if err == sql.ErrNoRows || (err == nil && !active) {
+		return fmt.Errorf("categoría inválida: %s", slug)
+	}
+	if err != nil {
+		return fmt.Errorf("error verificando categoría: %v", err)
+	}
+	return nil
+}
+
+// GetTags obtiene las etiquetas con la cantidad de noticias publicadas que las usan,
+// de la más usada a la menos usada
+func (s *NewsService) GetTags() ([]models.NewsTag, error) {
+	rows, err := s.DB.Query(`
+		SELECT t.id, t.name, t.slug, COUNT(market_news.id), t.created_at
+		FROM news_tags t
+		LEFT JOIN market_news_tags mt ON mt.tag_id = t.id
+		LEFT JOIN market_news ON market_news.id = mt.news_id AND ` + publishedNewsCondition + `
+		GROUP BY t.id
+		ORDER BY COUNT(market_news.id) DESC, t.name ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo etiquetas: %v", err)
+	}
+	defer rows.Close()
+
+	var tags []models.NewsTag
+	for rows.Next() {
+		var t models.NewsTag
+		if err := rows.Scan(&t.ID, &t.Name, &t.Slug, &t.NewsCount, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error escaneando etiqueta: %v", err)
+		}
+		tags = append(tags, t)
+	}
+
+	return tags, nil
+}
+
+// DeleteTag elimina una etiqueta y la quita de todas las noticias
+func (s *NewsService) DeleteTag(slug string) error {
+	result, err := s.DB.Exec("DELETE FROM news_tags WHERE slug = $1", slug)
+	if err != nil {
+		return fmt.Errorf("error eliminando etiqueta: %v", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("etiqueta no encontrada")
+	}
+	return nil
+}
+
+// newsTag etiqueta normalizada antes de guardarse
+type newsTag struct {
+	Name string
+	Slug string
+}
+
+// normalizeTags limpia los espacios de cada etiqueta y descarta las repetidas (mismo slug)
+func normalizeTags(names []string) ([]newsTag, error) {
+	var tags []newsTag
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.Join(strings.Fields(name), " ")
+		slug := tagSlug(name)
+		if slug == "" || utf8.RuneCountInString(name) > maxTagLength {
+			return nil, fmt.Errorf("etiqueta inválida: %q", name)
+		}
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		tags = append(tags, newsTag{Name: name, Slug: slug})
+	}
+	if len(tags) > maxTagsPerNews {
+		return nil, fmt.Errorf("máximo %d etiquetas por noticia", maxTagsPerNews)
+	}
+	return tags, nil
+}
+
+// tagSlug genera el slug de una etiqueta: minúsculas, sin acentos y con guiones ("Política Monetaria" → "politica-monetaria")
+func tagSlug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range norm.NFD.String(strings.ToLower(name)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Marcas diacríticas separadas por la normalización
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+		default:
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// setNewsTags reemplaza las etiquetas de una noticia, creando las que no existen. Devuelve los
+// nombres guardados ordenados: una etiqueta existente conserva su nombre original.
+func setNewsTags(tx *sql.Tx, newsID uuid.UUID, tags []newsTag) ([]string, error) {
+	if _, err := tx.Exec("DELETE FROM market_news_tags WHERE news_id = $1", newsID); err != nil {
+		return nil, fmt.Errorf("error actualizando etiquetas: %v", err)
+	}
+
+	names := []string{}
+	for _, tag := range tags {
+		var tagID uuid.UUID
+		var name string
+		err := tx.QueryRow(`
+			INSERT INTO news_tags (name, slug) VALUES ($1, $2)
+			ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug
+			RETURNING id, name
+		`, tag.Name, tag.Slug).Scan(&tagID, &name)
+		if err != nil {
+			return nil, fmt.Errorf("error guardando etiqueta: %v", err)
+		}
+
+		if _, err := tx.Exec("INSERT INTO market_news_tags (news_id, tag_id) VALUES ($1, $2)", newsID, tagID); err != nil {
+			return nil, fmt.Errorf("error asignando etiqueta: %v", err)
+		}
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
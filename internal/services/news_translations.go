@@ -0,0 +1,338 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// languageCodePattern códigos de idioma ISO 639-1/639-2 en minúsculas ("en", "pt")
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// validateTranslationLanguage verifica el idioma de una traducción; el idioma por defecto es
+// el del contenido original de la noticia
+func validateTranslationLanguage(language string) error {
+	if !languageCodePattern.MatchString(language) {
+		return fmt.Errorf("idioma inválido: %s", language)
+	}
+	if language == models.DefaultLanguage {
+		return fmt.Errorf("idioma inválido: %s es el idioma original de las noticias", language)
+	}
+	return nil
+}
+
+// newsTranslationColumns columnas de market_news_translations en el orden que espera scanNewsTranslation
+const newsTranslationColumns = `news_id, language, title, summary, summary_generated, content, content_html,
+		       updated_by, created_at, updated_at`
+
+func scanNewsTranslation(row rowScanner, t *models.NewsTranslation) error {
+	return row.Scan(&t.NewsID, &t.Language, &t.Title, &t.Summary, &t.SummaryGenerated, &t.Content, &t.ContentHTML,
+		&t.UpdatedBy, &t.CreatedAt, &t.UpdatedAt)
+}
+
+// GetTranslations obtiene las traducciones de una noticia
+func (s *NewsService) GetTranslations(newsID uuid.UUID) ([]models.NewsTranslation, error) {
+	var exists bool
+	if err := s.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM market_news WHERE id = $1)", newsID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error verificando noticia: %v", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("noticia no encontrada")
+	}
+
+	rows, err := s.DB.Query(`
+		SELECT `+newsTranslationColumns+`
+		FROM market_news_translations WHERE news_id = $1
+		ORDER BY language ASC
+	`, newsID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo traducciones: %v", err)
+	}
+	defer rows.Close()
+
+	translations := []models.NewsTranslation{}
+	for rows.Next() {
+		var t models.NewsTranslation
+		if err := scanNewsTranslation(rows, &t); err != nil {
+			return nil, fmt.Errorf("error escaneando traducción: %v", err)
+		}
+		translations = append(translations, t)
+	}
+
+	return translations, nil
+}
+
+// UpsertTranslation crea o reemplaza la traducción de una noticia a un idioma. Como al editar la
+// noticia, cada cambio guarda una versión y devuelve la noticia a revisión: una traducción de una
+// noticia publicada no se muestra hasta que otro admin la apruebe.
+func (s *NewsService) UpsertTranslation(newsID uuid.UUID, language string, req models.UpsertNewsTranslationRequest, editorID uuid.UUID) (*models.NewsTranslation, error) {
+	if err := validateTranslationLanguage(language); err != nil {
+		return nil, err
+	}
+
+	contentHTML := renderNewsContent(req.Content)
+	summary := req.Summary
+	summaryGenerated := summary == nil || strings.TrimSpace(*summary) == ""
+	if summaryGenerated {
+		summary = newsSummary(contentHTML)
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error guardando traducción: %v", err)
+	}
+	defer tx.Rollback()
+
+	status, err := lockNewsForTranslation(tx, newsID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Guardar la misma traducción no crea una versión ni devuelve la noticia a revisión
+	var t models.NewsTranslation
+	err = scanNewsTranslation(tx.QueryRow(`
+		INSERT INTO market_news_translations (news_id, language, title, summary, summary_generated, content, content_html, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (news_id, language) DO UPDATE SET
+		    title = EXCLUDED.title,
+		    summary = EXCLUDED.summary,
+		    summary_generated = EXCLUDED.summary_generated,
+		    content = EXCLUDED.content,
+		    content_html = EXCLUDED.content_html,
+		    updated_by = EXCLUDED.updated_by
+		WHERE (market_news_translations.title, market_news_translations.summary, market_news_translations.content)
+		      IS DISTINCT FROM (EXCLUDED.title, EXCLUDED.summary, EXCLUDED.content)
+		RETURNING `+newsTranslationColumns,
+		newsID, language, strings.TrimSpace(req.Title), summary, summaryGenerated, req.Content, contentHTML, editorID,
+	), &t)
+	if err == sql.ErrNoRows {
+		err = scanNewsTranslation(tx.QueryRow(`
+			SELECT `+newsTranslationColumns+` FROM market_news_translations WHERE news_id = $1 AND language = $2
+		`, newsID, language), &t)
+		if err != nil {
+			return nil, fmt.Errorf("error obteniendo traducción: %v", err)
+		}
+		return &t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error guardando traducción: %v", err)
+	}
+
+	if err := recordTranslationRevision(tx, newsID, language, editorID, false); err != nil {
+		return nil, err
+	}
+
+	if err := returnNewsToReview(tx, newsID, status); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error guardando traducción: %v", err)
+	}
+
+	return &t, nil
+}
+
+// lockNewsForTranslation bloquea la noticia y devuelve su estado; las noticias archivadas no se editan
+func lockNewsForTranslation(tx *sql.Tx, newsID uuid.UUID) (models.NewsStatus, error) {
+	var status models.NewsStatus
+	err := tx.QueryRow("SELECT status FROM market_news WHERE id = $1 FOR UPDATE", newsID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("noticia no encontrada")
+	}
+	if err != nil {
+		return "", fmt.Errorf("error obteniendo noticia: %v", err)
+	}
+	if status == models.NewsStatusArchived {
+		return "", fmt.Errorf("no se puede editar una noticia archivada")
+	}
+	return status, nil
+}
+
+// returnNewsToReview aplica statusAfterEdit a la noticia tras cambiar una de sus traducciones
+func returnNewsToReview(tx *sql.Tx, newsID uuid.UUID, status models.NewsStatus) error {
+	if next := statusAfterEdit(status); next != status {
+		_, err := tx.Exec("UPDATE market_news SET status = $1, updated_at = NOW() WHERE id = $2", next, newsID)
+		if err != nil {
+			return fmt.Errorf("error devolviendo la noticia a revisión: %v", err)
+		}
+	}
+	return nil
+}
+
+// recordTranslationRevision guarda la traducción actual como nueva versión; deleted marca la
+// versión que registra su eliminación
+func recordTranslationRevision(tx *sql.Tx, newsID uuid.UUID, language string, editorID uuid.UUID, deleted bool) error {
+	_, err := tx.Exec(`
+		INSERT INTO market_news_translation_revisions (news_id, language, revision, title, summary, content, deleted, edited_by)
+		SELECT news_id, language,
+		       COALESCE((SELECT MAX(revision) FROM market_news_translation_revisions WHERE news_id = $1 AND language = $2), 0) + 1,
+		       title, summary, content, $4, $3
+		FROM market_news_translations WHERE news_id = $1 AND language = $2
+	`, newsID, language, editorID, deleted)
+	if err != nil {
+		return fmt.Errorf("error guardando versión de la traducción: %v", err)
+	}
+	return nil
+}
+
+// GetTranslationRevisions obtiene las versiones de la traducción de una noticia a un idioma,
+// de la más reciente a la más antigua
+func (s *NewsService) GetTranslationRevisions(newsID uuid.UUID, language string) ([]models.NewsTranslationRevision, error) {
+	rows, err := s.DB.Query(`
+		SELECT id, news_id, language, revision, title, summary, content, deleted, edited_by, created_at
+		FROM market_news_translation_revisions WHERE news_id = $1 AND language = $2
+		ORDER BY revision DESC
+	`, newsID, language)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo versiones de la traducción: %v", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.NewsTranslationRevision{}
+	for rows.Next() {
+		var r models.NewsTranslationRevision
+		err := rows.Scan(&r.ID, &r.NewsID, &r.Language, &r.Revision, &r.Title, &r.Summary, &r.Content, &r.Deleted, &r.EditedBy, &r.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando versión de la traducción: %v", err)
+		}
+		revisions = append(revisions, r)
+	}
+
+	return revisions, nil
+}
+
+// DeleteTranslation elimina la traducción de una noticia a un idioma. Como una edición, guarda
+// una versión (con el contenido eliminado) y devuelve la noticia a revisión.
+func (s *NewsService) DeleteTranslation(newsID uuid.UUID, language string, editorID uuid.UUID) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("error eliminando traducción: %v", err)
+	}
+	defer tx.Rollback()
+
+	status, err := lockNewsForTranslation(tx, newsID)
+	if err != nil {
+		return err
+	}
+
+	if err := recordTranslationRevision(tx, newsID, language, editorID, true); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec("DELETE FROM market_news_translations WHERE news_id = $1 AND language = $2", newsID, language)
+	if err != nil {
+		return fmt.Errorf("error eliminando traducción: %v", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("traducción no encontrada")
+	}
+
+	if err := returnNewsToReview(tx, newsID, status); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error eliminando traducción: %v", err)
+	}
+	return nil
+}
+
+// ApplyTranslations reemplaza título, resumen y contenido de cada noticia por la traducción al
+// primer idioma de la lista que tenga una; si ninguno la tiene se deja el original. Completa
+// Language con el idioma devuelto y Languages con los disponibles.
+func (s *NewsService) ApplyTranslations(newsList []models.MarketNews, languages []string) error {
+	if len(newsList) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(newsList))
+	for i, news := range newsList {
+		ids[i] = news.ID.String()
+	}
+
+	rows, err := s.DB.Query(`
+		SELECT news_id, language, title, summary, content, content_html
+		FROM market_news_translations WHERE news_id = ANY($1::uuid[])
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("error obteniendo traducciones: %v", err)
+	}
+	defer rows.Close()
+
+	translations := make(map[uuid.UUID]map[string]models.NewsTranslation)
+	for rows.Next() {
+		var t models.NewsTranslation
+		if err := rows.Scan(&t.NewsID, &t.Language, &t.Title, &t.Summary, &t.Content, &t.ContentHTML); err != nil {
+			return fmt.Errorf("error escaneando traducción: %v", err)
+		}
+		if translations[t.NewsID] == nil {
+			translations[t.NewsID] = make(map[string]models.NewsTranslation)
+		}
+		translations[t.NewsID][t.Language] = t
+	}
+
+	for i := range newsList {
+		news := &newsList[i]
+		available := translations[news.ID]
+
+		news.Language = models.DefaultLanguage
+		news.Languages = []string{models.DefaultLanguage}
+		for language := range available {
+			news.Languages = append(news.Languages, language)
+		}
+		sort.Strings(news.Languages[1:])
+
+		for _, language := range languages {
+			if language == models.DefaultLanguage {
+				break
+			}
+			if t, ok := available[language]; ok {
+				news.Title = t.Title
+				news.Summary = t.Summary
+				news.Content = t.Content
+				news.ContentHTML = t.ContentHTML
+				news.Language = language
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LocalizeSearchResults aplica las traducciones a los resultados de una búsqueda. La búsqueda se
+// hace sobre el original en español, así que en los resultados traducidos el título y el fragmento
+// resaltados se reemplazan por el título y el resumen traducidos, sin resaltar.
+func (s *NewsService) LocalizeSearchResults(results []models.NewsSearchResult, languages []string) error {
+	news := make([]models.MarketNews, len(results))
+	for i := range results {
+		news[i] = results[i].MarketNews
+	}
+	if err := s.ApplyTranslations(news, languages); err != nil {
+		return err
+	}
+
+	for i := range results {
+		results[i].MarketNews = news[i]
+		if news[i].Language == models.DefaultLanguage {
+			continue
+		}
+		results[i].TitleHighlight = html.EscapeString(news[i].Title)
+		results[i].Snippet = ""
+		if news[i].Summary != nil {
+			results[i].Snippet = html.EscapeString(*news[i].Summary)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"math/bits"
+	"os"
+)
+
+// dHashSize ancho de la cuadrícula usada por el hash de diferencias (9x8 -> 64 bits)
+const dHashSize = 8
+
+// computeFileDHash calcula el hash perceptual de diferencias (dHash) de una imagen en disco.
+// Imágenes visualmente similares (recomprimidas, reescaladas) producen hashes con poca distancia de Hamming.
+func computeFileDHash(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("error abriendo imagen: %v", err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return 0, fmt.Errorf("error decodificando imagen: %v", err)
+	}
+
+	return dHash(img), nil
+}
+
+// dHash reduce la imagen a una cuadrícula de 9x8 en escala de grises y compara píxeles adyacentes
+func dHash(img image.Image) int64 {
+	grid := grayscaleGrid(img, dHashSize+1, dHashSize)
+
+	var hash uint64
+	for y := 0; y < dHashSize; y++ {
+		for x := 0; x < dHashSize; x++ {
+			hash <<= 1
+			if grid[y][x] < grid[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+
+	return int64(hash)
+}
+
+// grayscaleGrid promedia la luminancia de la imagen en una cuadrícula de width x height celdas
+func grayscaleGrid(img image.Image, width, height int) [][]float64 {
+	bounds := img.Bounds()
+	grid := make([][]float64, height)
+
+	for gy := 0; gy < height; gy++ {
+		grid[gy] = make([]float64, width)
+		y0 := bounds.Min.Y + gy*bounds.Dy()/height
+		y1 := bounds.Min.Y + (gy+1)*bounds.Dy()/height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+
+		for gx := 0; gx < width; gx++ {
+			x0 := bounds.Min.X + gx*bounds.Dx()/width
+			x1 := bounds.Min.X + (gx+1)*bounds.Dx()/width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var sum float64
+			var count int
+			for y := y0; y < y1 && y < bounds.Max.Y; y++ {
+				for x := x0; x < x1 && x < bounds.Max.X; x++ {
+					r, g, b, _ := img.At(x, y).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+					count++
+				}
+			}
+			if count > 0 {
+				grid[gy][gx] = sum / float64(count)
+			}
+		}
+	}
+
+	return grid
+}
+
+// hashBands divide el hash en count bandas contiguas de bits y devuelve una clave por banda
+// (índice de banda y valor), para buscar candidatos con un índice en lugar de comparar todos los
+// hashes. Si dos hashes están a distancia de Hamming menor que count, por el principio del
+// palomar coinciden en al menos una banda.
+func hashBands(hash int64, count int) []int64 {
+	value := uint64(hash)
+	bands := make([]int64, count)
+	offset := 0
+	for i := 0; i < count; i++ {
+		width := 64 / count
+		if i < 64%count {
+			width++
+		}
+		band := (value >> offset) & (1<<width - 1)
+		bands[i] = int64(i)<<16 | int64(band)
+		offset += width
+	}
+	return bands
+}
+
+// hammingDistance cuenta los bits distintos entre dos hashes
+func hammingDistance(a, b int64) int {
+	return bits.OnesCount64(uint64(a ^ b))
+}
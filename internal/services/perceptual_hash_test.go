@@ -0,0 +1,162 @@
+package services
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// gradientImage imagen en escala de grises que se aclara de izquierda a derecha (o al revés)
+func gradientImage(width, height int, ascending bool) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := uint8(x * 255 / (width - 1))
+			if !ascending {
+				v = 255 - v
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return img
+}
+
+func TestDHashGradients(t *testing.T) {
+	if got := dHash(gradientImage(90, 80, true)); uint64(got) != ^uint64(0) {
+		t.Errorf("gradiente ascendente: hash = %016x, se esperaban todos los bits en 1", uint64(got))
+	}
+	if got := dHash(gradientImage(90, 80, false)); got != 0 {
+		t.Errorf("gradiente descendente: hash = %016x, se esperaba 0", uint64(got))
+	}
+}
+
+func TestDHashSimilarImages(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	original := image.NewGray(image.Rect(0, 0, 180, 160))
+	for i := range original.Pix {
+		original.Pix[i] = uint8(rng.Intn(256))
+	}
+
+	// La misma imagen con un poco de ruido debe quedar dentro del umbral
+	noisy := image.NewGray(original.Rect)
+	copy(noisy.Pix, original.Pix)
+	for i := range noisy.Pix {
+		if rng.Intn(50) == 0 {
+			noisy.Pix[i] ^= 0x01
+		}
+	}
+	if d := hammingDistance(dHash(original), dHash(noisy)); d > duplicateHashThreshold {
+		t.Errorf("imagen con ruido: distancia = %d, se esperaba <= %d", d, duplicateHashThreshold)
+	}
+
+	// Una imagen distinta debe quedar lejos
+	if d := hammingDistance(dHash(original), dHash(gradientImage(180, 160, true))); d <= duplicateHashThreshold {
+		t.Errorf("imagen distinta: distancia = %d, se esperaba > %d", d, duplicateHashThreshold)
+	}
+}
+
+func TestComputeFileDHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := gradientImage(90, 80, true)
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	got, err := computeFileDHash(path)
+	if err != nil {
+		t.Fatalf("computeFileDHash: %v", err)
+	}
+	if got != dHash(img) {
+		t.Errorf("hash del archivo = %016x, se esperaba %016x", uint64(got), uint64(dHash(img)))
+	}
+
+	if err := os.WriteFile(path, []byte("no es una imagen"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := computeFileDHash(path); err == nil {
+		t.Error("se esperaba error con un archivo que no es imagen")
+	}
+}
+
+func TestHammingDistance(t *testing.T) {
+	tests := []struct {
+		a, b int64
+		want int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{0, -1, 64},
+		{0x0F0F, 0x00FF, 8},
+	}
+	for _, tt := range tests {
+		if got := hammingDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("hammingDistance(%x, %x) = %d, se esperaba %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestHashBandsShareBandWithinThreshold(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 1000; i++ {
+		a := int64(rng.Uint64())
+		// Invertir hasta duplicateHashThreshold bits distintos
+		b := a
+		for _, bit := range rng.Perm(64)[:rng.Intn(duplicateHashThreshold+1)] {
+			b ^= 1 << bit
+		}
+
+		bandsA := hashBands(a, duplicateHashBands)
+		bandsB := hashBands(b, duplicateHashBands)
+		shared := false
+		for j := range bandsA {
+			if bandsA[j] == bandsB[j] {
+				shared = true
+				break
+			}
+		}
+		if !shared {
+			t.Fatalf("hashes %016x y %016x a distancia %d no comparten banda", uint64(a), uint64(b), hammingDistance(a, b))
+		}
+	}
+}
+
+func TestHashBandsKeys(t *testing.T) {
+	bands := hashBands(-1, duplicateHashBands)
+	if len(bands) != duplicateHashBands {
+		t.Fatalf("len(bands) = %d, se esperaba %d", len(bands), duplicateHashBands)
+	}
+
+	// Con todos los bits en 1, cada banda vale 2^ancho - 1 y los anchos suman 64
+	total := 0
+	for i, key := range bands {
+		if index := int(key >> 16); index != i {
+			t.Errorf("banda %d: índice codificado = %d", i, index)
+		}
+		value := key & 0xFFFF
+		width := 0
+		for ; value > 0; value >>= 1 {
+			width++
+		}
+		total += width
+	}
+	if total != 64 {
+		t.Errorf("los anchos de banda suman %d, se esperaba 64", total)
+	}
+
+	// La misma banda con el mismo valor en posiciones distintas no debe confundirse
+	zero := hashBands(0, duplicateHashBands)
+	for i := 1; i < len(zero); i++ {
+		if zero[i] == zero[0] {
+			t.Errorf("las bandas 0 y %d producen la misma clave", i)
+		}
+	}
+}
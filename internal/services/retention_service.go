@@ -0,0 +1,305 @@
+package services
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// RetentionService elimina los archivos KYC que superan el plazo de retención de su categoría
+type RetentionService struct {
+	DB *sql.DB
+	// Días que se conservan los archivos por categoría (0 = no se purgan)
+	RetainDays map[models.RetentionPolicy]int
+}
+
+func NewRetentionService(db *sql.DB, retainDays map[models.RetentionPolicy]int) *RetentionService {
+	return &RetentionService{
+		DB:         db,
+		RetainDays: retainDays,
+	}
+}
+
+// Report lista los archivos que se purgarían hoy, sin modificar nada
+func (s *RetentionService) Report() (*models.RetentionReport, error) {
+	candidates, err := s.candidates()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildReport(true, candidates), nil
+}
+
+// Purge elimina de forma segura los archivos vencidos, redacta sus registros y deja una lápida.
+// Un documento que falla se reporta en Failures y no impide purgar el resto.
+func (s *RetentionService) Purge() (*models.RetentionReport, error) {
+	candidates, err := s.candidates()
+	if err != nil {
+		return nil, err
+	}
+
+	var purged []models.RetentionCandidate
+	var failures []models.RetentionFailure
+	for _, candidate := range candidates {
+		if err := s.purgeDocument(candidate); err != nil {
+			failures = append(failures, models.RetentionFailure{
+				DocumentID: candidate.DocumentID,
+				UserID:     candidate.UserID,
+				Error:      err.Error(),
+			})
+			continue
+		}
+		purged = append(purged, candidate)
+	}
+
+	report := s.buildReport(false, purged)
+	report.Failures = failures
+	return report, nil
+}
+
+// RunPurge ejecuta la purga como tarea programada
+func (s *RetentionService) RunPurge() error {
+	report, err := s.Purge()
+	if err != nil {
+		return err
+	}
+	if report.TotalFiles > 0 {
+		fmt.Printf("Retención KYC: %d archivos purgados (%d bytes)\n", report.TotalFiles, report.TotalBytes)
+	}
+	for _, failure := range report.Failures {
+		fmt.Printf("Retención KYC: error purgando documento %v: %s\n", failure.DocumentID, failure.Error)
+	}
+	if len(report.Failures) > 0 {
+		return fmt.Errorf("%d documentos no se pudieron purgar", len(report.Failures))
+	}
+	return nil
+}
+
+// GetTombstones obtiene las constancias de purga, opcionalmente de un usuario
+func (s *RetentionService) GetTombstones(userID *uuid.UUID) ([]models.PurgeTombstone, error) {
+	query := `
+		SELECT id, document_id, user_id, document_type, document_status, policy,
+		       file_size, file_sha256, uploaded_at, last_activity_at, purged_at
+		FROM kyc_purge_tombstones
+	`
+	args := []interface{}{}
+	if userID != nil {
+		query += " WHERE user_id = $1"
+		args = append(args, *userID)
+	}
+	query += " ORDER BY purged_at DESC"
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo constancias de purga: %v", err)
+	}
+	defer rows.Close()
+
+	var tombstones []models.PurgeTombstone
+	for rows.Next() {
+		var t models.PurgeTombstone
+		err := rows.Scan(&t.ID, &t.DocumentID, &t.UserID, &t.DocumentType, &t.DocumentStatus, &t.Policy,
+			&t.FileSize, &t.FileSHA256, &t.UploadedAt, &t.LastActivityAt, &t.PurgedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando constancia de purga: %v", err)
+		}
+		tombstones = append(tombstones, t)
+	}
+
+	return tombstones, nil
+}
+
+// SetLegalHold activa o levanta la retención legal; con ella activa los archivos del usuario no se purgan
+func (s *RetentionService) SetLegalHold(userID uuid.UUID, req models.SetLegalHoldRequest, adminID uuid.UUID) error {
+	var result sql.Result
+	var err error
+	if *req.LegalHold {
+		result, err = s.DB.Exec(`
+			UPDATE users SET legal_hold = true, legal_hold_reason = $1, legal_hold_by = $2, legal_hold_at = $3
+			WHERE id = $4
+		`, req.Reason, adminID, time.Now(), userID)
+	} else {
+		result, err = s.DB.Exec(`
+			UPDATE users SET legal_hold = false, legal_hold_reason = NULL, legal_hold_by = NULL, legal_hold_at = NULL
+			WHERE id = $1
+		`, userID)
+	}
+	if err != nil {
+		return fmt.Errorf("error actualizando retención legal: %v", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("usuario no encontrado")
+	}
+
+	return nil
+}
+
+// candidates obtiene los documentos cuyo plazo de retención venció.
+// La categoría se decide por el usuario: cuenta cerrada, KYC rechazado o KYC pendiente (abandonado);
+// el plazo se cuenta desde el cierre de la cuenta o desde la última actividad en sus documentos.
+func (s *RetentionService) candidates() ([]models.RetentionCandidate, error) {
+	rows, err := s.DB.Query(`
+		WITH activity AS (
+			SELECT user_id, MAX(updated_at) AS last_activity
+			FROM kyc_documents GROUP BY user_id
+		)
+		SELECT d.id, d.user_id, d.document_type, d.status, d.file_size, d.file_path, d.created_at,
+		       CASE WHEN u.closed_at IS NOT NULL THEN 'closed'
+		            WHEN u.kyc_status = 'rejected' THEN 'rejected'
+		            ELSE 'abandoned'
+		       END AS policy,
+		       COALESCE(u.closed_at, a.last_activity) AS last_activity
+		FROM kyc_documents d
+		JOIN users u ON u.id = d.user_id
+		JOIN activity a ON a.user_id = d.user_id
+		WHERE d.purged_at IS NULL AND u.legal_hold = false AND u.role = 'user'
+		  AND (
+		        (u.closed_at IS NOT NULL AND $3::int > 0
+		         AND u.closed_at <= NOW() - make_interval(days => $3::int))
+		     OR (u.closed_at IS NULL AND u.kyc_status = 'rejected' AND $1::int > 0
+		         AND a.last_activity <= NOW() - make_interval(days => $1::int))
+		     OR (u.closed_at IS NULL AND u.kyc_status = 'pending' AND $2::int > 0
+		         AND a.last_activity <= NOW() - make_interval(days => $2::int))
+		  )
+		ORDER BY last_activity ASC
+	`, s.RetainDays[models.RetentionPolicyRejected], s.RetainDays[models.RetentionPolicyAbandoned],
+		s.RetainDays[models.RetentionPolicyClosed])
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo documentos a purgar: %v", err)
+	}
+	defer rows.Close()
+
+	var candidates []models.RetentionCandidate
+	for rows.Next() {
+		var c models.RetentionCandidate
+		err := rows.Scan(&c.DocumentID, &c.UserID, &c.DocumentType, &c.DocumentStatus, &c.FileSize,
+			&c.FilePath, &c.UploadedAt, &c.Policy, &c.LastActivityAt)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando documento a purgar: %v", err)
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates, nil
+}
+
+// purgeDocument borra el archivo y luego redacta el registro. Si falla la base de datos,
+// la siguiente ejecución vuelve a tomar el documento (el archivo ya no existe).
+func (s *RetentionService) purgeDocument(c models.RetentionCandidate) error {
+	checksum, err := secureDelete(c.FilePath)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO kyc_purge_tombstones (
+			document_id, user_id, document_type, document_status, policy,
+			file_size, file_sha256, uploaded_at, last_activity_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, c.DocumentID, c.UserID, c.DocumentType, c.DocumentStatus, c.Policy,
+		c.FileSize, checksum, c.UploadedAt, c.LastActivityAt)
+	if err != nil {
+		return fmt.Errorf("error registrando constancia de purga: %v", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE kyc_documents SET
+			file_path = '', original_name = '', perceptual_hash = NULL, perceptual_hash_bands = NULL,
+			rejection_note = NULL, purged_at = $1
+		WHERE id = $2
+	`, time.Now(), c.DocumentID)
+	if err != nil {
+		return fmt.Errorf("error redactando documento: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// Quitar el directorio del usuario si quedó vacío
+	if c.FilePath != "" {
+		os.Remove(filepath.Dir(c.FilePath))
+	}
+
+	return nil
+}
+
+func (s *RetentionService) buildReport(dryRun bool, documents []models.RetentionCandidate) *models.RetentionReport {
+	report := &models.RetentionReport{
+		DryRun:      dryRun,
+		GeneratedAt: time.Now(),
+		RetainDays:  s.RetainDays,
+		ByPolicy:    map[models.RetentionPolicy]int{},
+		Documents:   []models.RetentionCandidate{},
+	}
+	for _, doc := range documents {
+		report.ByPolicy[doc.Policy]++
+		report.TotalFiles++
+		report.TotalBytes += doc.FileSize
+		report.Documents = append(report.Documents, doc)
+	}
+	return report
+}
+
+// secureDelete sobrescribe el archivo con ceros antes de eliminarlo y devuelve su SHA-256 original.
+// Un archivo inexistente no es un error (devuelve nil).
+func secureDelete(path string) (*string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo archivo: %v", err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo archivo: %v", err)
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("error sobrescribiendo archivo: %v", err)
+	}
+	zeros := make([]byte, 32*1024)
+	for remaining := size; remaining > 0; {
+		n := min(remaining, int64(len(zeros)))
+		if _, err := f.Write(zeros[:n]); err != nil {
+			return nil, fmt.Errorf("error sobrescribiendo archivo: %v", err)
+		}
+		remaining -= n
+	}
+	if err := f.Sync(); err != nil {
+		return nil, fmt.Errorf("error sobrescribiendo archivo: %v", err)
+	}
+	f.Close()
+
+	if err := os.Remove(path); err != nil {
+		return nil, fmt.Errorf("error eliminando archivo: %v", err)
+	}
+
+	return &checksum, nil
+}
@@ -0,0 +1,319 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// maxFailedLoginsScored límite de intentos fallidos que suman al puntaje
+const maxFailedLoginsScored = 10
+
+// disposableEmailDomains dominios de email temporales conocidos
+var disposableEmailDomains = []string{
+	"mailinator.com", "guerrillamail.com", "10minutemail.com", "tempmail.com", "temp-mail.org",
+	"yopmail.com", "trashmail.com", "sharklasers.com", "getnada.com", "dispostable.com",
+}
+
+// RiskService calcula el puntaje de riesgo de los usuarios a partir de reglas con pesos configurables
+type RiskService struct {
+	DB                *sql.DB
+	HighRiskCountries []string
+	MediumThreshold   int
+	HighThreshold     int
+}
+
+func NewRiskService(db *sql.DB, highRiskCountries []string, mediumThreshold, highThreshold int) *RiskService {
+	countries := make([]string, 0, len(highRiskCountries))
+	for _, country := range highRiskCountries {
+		if normalized := normalizeName(country); normalized != "" {
+			countries = append(countries, normalized)
+		}
+	}
+
+	return &RiskService{
+		DB:                db,
+		HighRiskCountries: countries,
+		MediumThreshold:   mediumThreshold,
+		HighThreshold:     highThreshold,
+	}
+}
+
+// HandleUserEvent recalcula el riesgo al registrarse y ante intentos fallidos de inicio de sesión
+func (s *RiskService) HandleUserEvent(event models.UserEvent) error {
+	switch event.Type {
+	case models.UserEventRegistered, models.UserEventLoginFailed:
+		_, err := s.Recalculate(event.UserID, string(event.Type))
+		return err
+	}
+	return nil
+}
+
+// HandleKYCEvent recalcula el riesgo cuando se sube o rechaza un documento
+func (s *RiskService) HandleKYCEvent(event models.KYCEvent) error {
+	switch event.Type {
+	case models.KYCEventDocumentUploaded, models.KYCEventDocumentRejected:
+		_, err := s.Recalculate(event.UserID, string(event.Type))
+		return err
+	}
+	return nil
+}
+
+// HandleMatchResolved recalcula el riesgo de los usuarios de un duplicado o screening resuelto
+func (s *RiskService) HandleMatchResolved(trigger string, userIDs ...uuid.UUID) error {
+	for _, userID := range userIDs {
+		if _, err := s.Recalculate(userID, trigger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Recalculate evalúa las reglas de riesgo para un usuario, guarda el resultado y lo registra en el historial
+func (s *RiskService) Recalculate(userID uuid.UUID, trigger string) (*models.RiskAssessment, error) {
+	weights, err := s.weightMap()
+	if err != nil {
+		return nil, err
+	}
+
+	var documentType, email, address string
+	var failedLogins, duplicates, screeningHits, rejections int
+	err = s.DB.QueryRow(`
+		SELECT u.document_type, u.email, u.address, u.failed_login_attempts,
+		       (SELECT COUNT(*) FROM identity_duplicate_matches m
+		        WHERE (m.user_id = u.id OR m.matched_user_id = u.id) AND m.status IN ('open', 'confirmed')),
+		       (SELECT COUNT(*) FROM screening_hits h
+		        WHERE h.user_id = u.id AND h.status IN ('pending', 'confirmed')),
+		       (SELECT COUNT(*) FROM kyc_document_rejections r
+		        JOIN kyc_documents d ON d.id = r.document_id WHERE d.user_id = u.id)
+		FROM users u WHERE u.id = $1
+	`, userID).Scan(&documentType, &email, &address, &failedLogins, &duplicates, &screeningHits, &rejections)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo datos de riesgo: %v", err)
+	}
+
+	occurrences := map[string]int{
+		"duplicate_match": duplicates,
+		"screening_hit":   screeningHits,
+		"failed_login":    min(failedLogins, maxFailedLoginsScored),
+		"kyc_rejection":   rejections,
+	}
+	if documentType == string(models.DocumentTypePasaporte) {
+		occurrences["passport_document"] = 1
+	}
+	if s.isHighRiskCountry(address) {
+		occurrences["high_risk_country"] = 1
+	}
+	if isDisposableEmail(email) {
+		occurrences["disposable_email"] = 1
+	}
+
+	assessment := &models.RiskAssessment{
+		UserID:  userID,
+		Trigger: trigger,
+		Factors: []models.RiskFactor{},
+	}
+	for factor, count := range occurrences {
+		if count == 0 {
+			continue
+		}
+		points := weights[factor] * count
+		assessment.Factors = append(assessment.Factors, models.RiskFactor{
+			Factor:      factor,
+			Occurrences: count,
+			Points:      points,
+		})
+		assessment.Score += points
+	}
+	sort.Slice(assessment.Factors, func(i, j int) bool {
+		return assessment.Factors[i].Factor < assessment.Factors[j].Factor
+	})
+	assessment.Score = min(assessment.Score, 100)
+	assessment.Level = s.levelFor(assessment.Score)
+
+	factorsJSON, err := json.Marshal(assessment.Factors)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO user_risk_scores (user_id, score, level, factors, trigger)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, userID, assessment.Score, assessment.Level, string(factorsJSON), trigger).Scan(&assessment.ID, &assessment.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error guardando historial de riesgo: %v", err)
+	}
+
+	_, err = tx.Exec("UPDATE users SET risk_score = $1, risk_level = $2 WHERE id = $3",
+		assessment.Score, assessment.Level, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error actualizando riesgo del usuario: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return assessment, nil
+}
+
+// GetRiskHistory obtiene los cálculos de riesgo de un usuario, del más reciente al más antiguo
+func (s *RiskService) GetRiskHistory(userID uuid.UUID) ([]models.RiskAssessment, error) {
+	rows, err := s.DB.Query(`
+		SELECT id, user_id, score, level, factors, trigger, created_at
+		FROM user_risk_scores WHERE user_id = $1
+		ORDER BY created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo historial de riesgo: %v", err)
+	}
+	defer rows.Close()
+
+	var history []models.RiskAssessment
+	for rows.Next() {
+		var a models.RiskAssessment
+		var factorsJSON []byte
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Score, &a.Level, &factorsJSON, &a.Trigger, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error escaneando historial de riesgo: %v", err)
+		}
+		if err := json.Unmarshal(factorsJSON, &a.Factors); err != nil {
+			return nil, fmt.Errorf("error leyendo factores de riesgo: %v", err)
+		}
+		history = append(history, a)
+	}
+
+	return history, nil
+}
+
+// GetWeights obtiene los pesos configurados de cada factor
+func (s *RiskService) GetWeights() ([]models.RiskFactorWeight, error) {
+	rows, err := s.DB.Query("SELECT factor, weight, description, updated_at FROM risk_factor_weights ORDER BY factor")
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo pesos de riesgo: %v", err)
+	}
+	defer rows.Close()
+
+	var weights []models.RiskFactorWeight
+	for rows.Next() {
+		var w models.RiskFactorWeight
+		if err := rows.Scan(&w.Factor, &w.Weight, &w.Description, &w.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error escaneando peso de riesgo: %v", err)
+		}
+		weights = append(weights, w)
+	}
+
+	return weights, nil
+}
+
+// UpdateWeights cambia el peso de uno o más factores existentes
+func (s *RiskService) UpdateWeights(req models.UpdateRiskWeightsRequest) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for factor, weight := range req.Weights {
+		if weight < 0 {
+			return fmt.Errorf("peso inválido para %s", factor)
+		}
+		result, err := tx.Exec("UPDATE risk_factor_weights SET weight = $1 WHERE factor = $2", weight, factor)
+		if err != nil {
+			return fmt.Errorf("error actualizando peso de riesgo: %v", err)
+		}
+		if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+			return fmt.Errorf("factor de riesgo desconocido: %s", factor)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// RecalculateAll recalcula el riesgo de todos los usuarios (por ejemplo tras cambiar los pesos)
+func (s *RiskService) RecalculateAll(trigger string) (int, error) {
+	rows, err := s.DB.Query("SELECT id FROM users WHERE role = 'user'")
+	if err != nil {
+		return 0, fmt.Errorf("error obteniendo usuarios: %v", err)
+	}
+	defer rows.Close()
+
+	var userIDs []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return 0, fmt.Errorf("error escaneando usuario: %v", err)
+		}
+		userIDs = append(userIDs, id)
+	}
+
+	for i, id := range userIDs {
+		if _, err := s.Recalculate(id, trigger); err != nil {
+			return i, err
+		}
+	}
+
+	return len(userIDs), nil
+}
+
+func (s *RiskService) weightMap() (map[string]int, error) {
+	weights, err := s.GetWeights()
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]int, len(weights))
+	for _, w := range weights {
+		m[w.Factor] = w.Weight
+	}
+	return m, nil
+}
+
+func (s *RiskService) levelFor(score int) models.RiskLevel {
+	switch {
+	case score >= s.HighThreshold:
+		return models.RiskLevelHigh
+	case score >= s.MediumThreshold:
+		return models.RiskLevelMedium
+	default:
+		return models.RiskLevelLow
+	}
+}
+
+// isHighRiskCountry toma el país como el último segmento de la dirección ("Caracas, Venezuela")
+func (s *RiskService) isHighRiskCountry(address string) bool {
+	parts := strings.Split(address, ",")
+	country := normalizeName(parts[len(parts)-1])
+	for _, c := range s.HighRiskCountries {
+		if country == c {
+			return true
+		}
+	}
+	return false
+}
+
+func isDisposableEmail(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, d := range disposableEmailDomains {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// allowedHTMLTags etiquetas permitidas en el contenido de noticias y sus atributos permitidos
+var allowedHTMLTags = map[string][]string{
+	"p": nil, "br": nil, "hr": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"strong": nil, "em": nil, "del": nil, "mark": nil,
+	"code": {"class"}, "pre": nil, "blockquote": nil,
+	"ul": nil, "ol": {"start"}, "li": nil,
+	"a":   {"href", "title"},
+	"img": {"src", "alt", "title"},
+}
+
+// droppedHTMLTags etiquetas que se eliminan junto con su contenido
+var droppedHTMLTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "template": true, "svg": true, "math": true, "textarea": true, "select": true,
+}
+
+// allowedURLSchemes esquemas aceptados en href/src; las URLs relativas también se aceptan
+var allowedURLSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+// sanitizeHTML deja solo las etiquetas y atributos permitidos: elimina scripts, manejadores
+// de eventos (on*), estilos y URLs con esquemas peligrosos (javascript:, data:, ...)
+func sanitizeHTML(input string) string {
+	var b strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+	dropping := ""
+	dropDepth := 0
+
+	for {
+		tt := tokenizer.Next()
+		if tt == html.ErrorToken {
+			return b.String()
+		}
+		token := tokenizer.Token()
+
+		// Dentro de una etiqueta descartada solo se sigue su anidamiento
+		if dropping != "" {
+			if token.Data == dropping {
+				switch tt {
+				case html.StartTagToken:
+					dropDepth++
+				case html.EndTagToken:
+					dropDepth--
+					if dropDepth == 0 {
+						dropping = ""
+					}
+				}
+			}
+			continue
+		}
+
+		switch tt {
+		case html.TextToken:
+			b.WriteString(html.EscapeString(token.Data))
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if droppedHTMLTags[token.Data] {
+				if tt == html.StartTagToken {
+					dropping = token.Data
+					dropDepth = 1
+				}
+				continue
+			}
+			allowedAttrs, ok := allowedHTMLTags[token.Data]
+			if !ok {
+				continue
+			}
+			token.Attr = sanitizeAttributes(token.Data, token.Attr, allowedAttrs)
+			if token.Data == "img" && !hasAttr(token.Attr, "src") {
+				continue
+			}
+			if token.Data == "a" && hasAttr(token.Attr, "href") {
+				token.Attr = append(token.Attr, html.Attribute{Key: "rel", Val: "nofollow noopener noreferrer"})
+			}
+			token.Type = html.StartTagToken
+			b.WriteString(token.String())
+
+		case html.EndTagToken:
+			if _, ok := allowedHTMLTags[token.Data]; ok {
+				b.WriteString(token.String())
+			}
+		}
+		// Comentarios y doctype se descartan
+	}
+}
+
+func sanitizeAttributes(tag string, attrs []html.Attribute, allowed []string) []html.Attribute {
+	var clean []html.Attribute
+	for _, attr := range attrs {
+		if attr.Namespace != "" || !containsString(allowed, attr.Key) {
+			continue
+		}
+		switch attr.Key {
+		case "href", "src":
+			if !isSafeURL(attr.Val) {
+				continue
+			}
+		case "class":
+			// Solo la clase de lenguaje de los bloques de código
+			if !strings.HasPrefix(attr.Val, "language-") || strings.ContainsAny(attr.Val, " \"'<>") {
+				continue
+			}
+		case "start":
+			if strings.Trim(attr.Val, "0123456789") != "" {
+				continue
+			}
+		}
+		clean = append(clean, attr)
+	}
+	return clean
+}
+
+// isSafeURL acepta URLs relativas o con esquema http, https o mailto
+func isSafeURL(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	// Los navegadores ignoran caracteres de control y espacios dentro del esquema ("java\tscript:")
+	cleaned := strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == ' ' {
+			return -1
+		}
+		return r
+	}, raw)
+	if !utf8.ValidString(cleaned) {
+		return false
+	}
+	u, err := url.Parse(cleaned)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" {
+		// Sin esquema, los dos puntos antes de la ruta, la consulta o el fragmento se interpretarían como esquema
+		before := cleaned
+		if i := strings.IndexAny(cleaned, "/?#"); i >= 0 {
+			before = cleaned[:i]
+		}
+		return !strings.Contains(before, ":")
+	}
+	return allowedURLSchemes[strings.ToLower(u.Scheme)]
+}
+
+func hasAttr(attrs []html.Attribute, key string) bool {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// htmlToText extrae el texto de un fragmento HTML, separando los bloques con espacios
+func htmlToText(input string) string {
+	var b strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+	for {
+		tt := tokenizer.Next()
+		switch tt {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(b.String()), " ")
+		case html.TextToken:
+			b.Write(tokenizer.Text())
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "a", "strong", "em", "del", "code", "mark":
+			default:
+				b.WriteString(" ")
+			}
+		}
+	}
+}
+
+// summarize genera un resumen en texto plano de hasta maxRunes caracteres, cortando en un espacio
+func summarize(text string, maxRunes int) string {
+	if utf8.RuneCountInString(text) <= maxRunes {
+		return text
+	}
+	runes := []rune(text)
+	cut := maxRunes
+	for i := maxRunes; i > maxRunes/2; i-- {
+		if runes[i] == ' ' {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRight(string(runes[:cut]), " ,.;:") + "…"
+}
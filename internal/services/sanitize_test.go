@@ -0,0 +1,147 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+	}{
+		{"etiquetas permitidas", "<p><strong>a</strong> <em>b</em></p>", "<p><strong>a</strong> <em>b</em></p>"},
+		{"etiqueta desconocida conserva el texto", "<div><span>texto</span></div>", "texto"},
+		{"script", "a<script>alert(1)</script>b", "ab"},
+		{"script anidado en texto", "<p>a<script>document.write('<script>x</script>')</script>b</p>", "<p>a&#39;)b</p>"},
+		{"script autocerrado", "a<script/>b", "ab"},
+		{"script autocerrado con src", `a<script src="https://evil.example/x.js"/>b`, "ab"},
+		{"svg", `a<svg onload="alert(1)"><circle r="1"/></svg>b`, "ab"},
+		{"svg anidado", "a<svg><svg><script>x</script></svg>c</svg>b", "ab"},
+		{"style", "<style>p{color:red}</style><p>x</p>", "<p>x</p>"},
+		{"iframe", `<iframe src="https://evil.example"></iframe>x`, "x"},
+		{"comentario", "a<!-- <script>x</script> -->b", "ab"},
+		{"manejador onclick", `<p onclick="alert(1)">x</p>`, "<p>x</p>"},
+		{"manejador onerror", `<img src="/a.png" onerror="alert(1)">`, `<img src="/a.png">`},
+		{"manejador en mayúsculas", `<a href="/x" OnMouseOver="alert(1)">x</a>`, `<a href="/x" rel="nofollow noopener noreferrer">x</a>`},
+		{"atributo style", `<p style="background:url(javascript:x)">x</p>`, "<p>x</p>"},
+		{"href javascript", `<a href="javascript:alert(1)">x</a>`, "<a>x</a>"},
+		{"href javascript con mayúsculas y espacios", `<a href="  JaVaScRiPt:alert(1)">x</a>`, "<a>x</a>"},
+		{"href java tab script", "<a href=\"java\tscript:alert(1)\">x</a>", "<a>x</a>"},
+		{"href java salto script", "<a href=\"java\nscript:alert(1)\">x</a>", "<a>x</a>"},
+		{"href con entidad", `<a href="java&#x09;script:alert(1)">x</a>`, "<a>x</a>"},
+		{"href data", `<a href="data:text/html;base64,PHNjcmlwdD4=">x</a>`, "<a>x</a>"},
+		{"href vbscript", `<a href="vbscript:msgbox">x</a>`, "<a>x</a>"},
+		{"src javascript", `<img src="javascript:alert(1)">`, ""},
+		{"src data", `<img src="data:image/svg+xml;base64,PHN2Zz4=" alt="x">`, ""},
+		{"href relativo con dos puntos", `<a href="evil:x/y">x</a>`, "<a>x</a>"},
+		{"href https", `<a href="https://example.com/a?b=1&amp;c=2" title="t">x</a>`, `<a href="https://example.com/a?b=1&amp;c=2" title="t" rel="nofollow noopener noreferrer">x</a>`},
+		{"href mailto", `<a href="mailto:a@example.com">x</a>`, `<a href="mailto:a@example.com" rel="nofollow noopener noreferrer">x</a>`},
+		{"clase de código", `<code class="language-go">x</code><code class="evil">y</code>`, `<code class="language-go">x</code><code>y</code>`},
+		{"start numérico", `<ol start="3"></ol><ol start="3;x"></ol>`, `<ol start="3"></ol><ol></ol>`},
+		{"texto escapado", "a &lt;b&gt; & c", "a &lt;b&gt; &amp; c"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeHTML(tt.input); got != tt.want {
+			t.Errorf("%s: sanitizeHTML(%q) = %q, se esperaba %q", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestIsSafeURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		safe bool
+	}{
+		{"https://example.com", true},
+		{"http://example.com/a:b", true},
+		{"mailto:a@example.com", true},
+		{"/noticias/1", true},
+		{"noticia.html#a:b", true},
+		{"?q=a:b", true},
+		{"javascript:alert(1)", false},
+		{"JAVASCRIPT:alert(1)", false},
+		{"java\tscript:alert(1)", false},
+		{"java\x00script:alert(1)", false},
+		{" javascript:alert(1)", false},
+		{"data:text/html,x", false},
+		{"vbscript:x", false},
+		{"ftp://example.com", false},
+		{"evil:x", false},
+	}
+	for _, tt := range tests {
+		if got := isSafeURL(tt.url); got != tt.safe {
+			t.Errorf("isSafeURL(%q) = %v, se esperaba %v", tt.url, got, tt.safe)
+		}
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	got := htmlToText("<h1>Título</h1><p>Un <strong>texto</strong> con <a href=\"/x\">enlace</a>.</p><ul><li>uno</li><li>dos</li></ul>")
+	if want := "Título Un texto con enlace. uno dos"; got != want {
+		t.Errorf("htmlToText = %q, se esperaba %q", got, want)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxRunes int
+		want     string
+	}{
+		{"texto corto", "hola", 10, "hola"},
+		{"justo en el límite", "ñandú", 5, "ñandú"},
+		{"corta en un espacio", "uno dos tres cuatro", 10, "uno dos…"},
+		{"quita puntuación final", "uno, dos, tres", 9, "uno, dos…"},
+		{"sin espacios corta en el límite", "aaaaaaaaaaaaaaaaaaaa", 10, "aaaaaaaaaa…"},
+		{"multibyte", "áéíóú áéíóú áéíóú", 8, "áéíóú…"},
+		{"multibyte sin espacios", "ñññññññññññññññ", 7, "ñññññññ…"},
+		{"emoji", "📈📈📈📈📈📈📈📈", 3, "📈📈📈…"},
+	}
+	for _, tt := range tests {
+		got := summarize(tt.text, tt.maxRunes)
+		if got != tt.want {
+			t.Errorf("%s: summarize(%q, %d) = %q, se esperaba %q", tt.name, tt.text, tt.maxRunes, got, tt.want)
+		}
+		if !strings.HasSuffix(got, "…") && got != tt.text {
+			t.Errorf("%s: el resumen recortado debe terminar en …", tt.name)
+		}
+	}
+}
+
+func TestNewsSummaryFromMarkdown(t *testing.T) {
+	content := "# Mercados\n\nEl **índice** subió " + strings.Repeat("ñ", newsSummaryLength) + " <script>x</script>"
+	summary := newsSummary(renderNewsContent(content))
+	if summary == nil {
+		t.Fatal("se esperaba un resumen")
+	}
+	if n := len([]rune(*summary)); n > newsSummaryLength+1 {
+		t.Errorf("el resumen tiene %d caracteres, máximo %d", n, newsSummaryLength+1)
+	}
+	if strings.ContainsAny(*summary, "<>*#") {
+		t.Errorf("el resumen contiene marcas: %q", *summary)
+	}
+	if !strings.HasPrefix(*summary, "Mercados El índice subió ") {
+		t.Errorf("resumen = %q", *summary)
+	}
+
+	if newsSummary("") != nil {
+		t.Error("se esperaba nil para contenido vacío")
+	}
+}
+
+func TestSearchHighlightHTML(t *testing.T) {
+	highlighted := searchHighlightStart + "bolsa" + searchHighlightStop
+	tests := []struct {
+		text, want string
+	}{
+		{"la " + highlighted + " sube", "la <mark>bolsa</mark> sube"},
+		{"<img src=x onerror=alert(1)> " + highlighted, "&lt;img src=x onerror=alert(1)&gt; <mark>bolsa</mark>"},
+		{`"` + highlighted + `" & más`, "&#34;<mark>bolsa</mark>&#34; &amp; más"},
+	}
+	for _, tt := range tests {
+		if got := searchHighlightHTML(tt.text); got != tt.want {
+			t.Errorf("searchHighlightHTML(%q) = %q, se esperaba %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// ScanResult resultado del análisis antivirus de un archivo
+type ScanResult struct {
+	Infected  bool
+	Signature string // Nombre de la firma detectada, vacío si está limpio
+}
+
+// Scanner analiza un archivo subido antes de que quede disponible para revisión
+type Scanner interface {
+	Scan(r io.Reader) (ScanResult, error)
+}
+
+// NoopScanner no analiza nada; es el escáner por defecto cuando no hay antivirus configurado
+type NoopScanner struct{}
+
+func (NoopScanner) Scan(r io.Reader) (ScanResult, error) {
+	return ScanResult{}, nil
+}
+
+// clamdChunkSize tamaño de cada bloque enviado con INSTREAM
+const clamdChunkSize = 32 * 1024
+
+// ClamdScanner analiza archivos con un daemon clamd usando el comando INSTREAM
+type ClamdScanner struct {
+	Network string // "tcp" o "unix"
+	Address string // "127.0.0.1:3310" o "/var/run/clamav/clamd.ctl"
+	Timeout time.Duration
+}
+
+// NewClamdScanner crea un escáner a partir de una dirección "host:puerto", "tcp://host:puerto" o "unix:///ruta.sock"
+func NewClamdScanner(address string, timeout time.Duration) *ClamdScanner {
+	network := "tcp"
+	switch {
+	case strings.HasPrefix(address, "unix://"):
+		network, address = "unix", strings.TrimPrefix(address, "unix://")
+	case strings.HasPrefix(address, "tcp://"):
+		address = strings.TrimPrefix(address, "tcp://")
+	}
+
+	return &ClamdScanner{
+		Network: network,
+		Address: address,
+		Timeout: timeout,
+	}
+}
+
+// Scan envía el contenido a clamd en bloques y espera la respuesta ("stream: OK" o "stream: <firma> FOUND")
+func (s *ClamdScanner) Scan(r io.Reader) (ScanResult, error) {
+	conn, err := net.DialTimeout(s.Network, s.Address, s.Timeout)
+	if err != nil {
+		return ScanResult{}, fmt.Errorf("error conectando con clamd: %v", err)
+	}
+	defer conn.Close()
+
+	if s.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(s.Timeout))
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return ScanResult{}, fmt.Errorf("error enviando comando a clamd: %v", err)
+	}
+
+	buf := make([]byte, clamdChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return ScanResult{}, fmt.Errorf("error enviando archivo a clamd: %v", err)
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return ScanResult{}, fmt.Errorf("error enviando archivo a clamd: %v", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return ScanResult{}, fmt.Errorf("error leyendo archivo: %v", readErr)
+		}
+	}
+
+	// Un bloque de longitud cero indica el fin del stream
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return ScanResult{}, fmt.Errorf("error enviando archivo a clamd: %v", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return ScanResult{}, fmt.Errorf("error leyendo respuesta de clamd: %v", err)
+	}
+
+	return parseClamdReply(reply)
+}
+
+// parseClamdReply interpreta la respuesta de clamd a INSTREAM
+func parseClamdReply(reply string) (ScanResult, error) {
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	reply = strings.TrimPrefix(reply, "stream: ")
+
+	switch {
+	case reply == "OK":
+		return ScanResult{}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return ScanResult{Infected: true, Signature: strings.TrimSuffix(reply, " FOUND")}, nil
+	default:
+		return ScanResult{}, fmt.Errorf("respuesta inesperada de clamd: %q", reply)
+	}
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClamd atiende conexiones INSTREAM en un puerto local. reply recibe el contenido recibido
+// y devuelve la respuesta; si maxSize > 0 responde el error de límite apenas se supera.
+type fakeClamd struct {
+	listener net.Listener
+	maxSize  int
+	reply    func(data []byte) string
+	received chan []byte
+}
+
+func newFakeClamd(t *testing.T, maxSize int, reply func(data []byte) string) *fakeClamd {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+	f := &fakeClamd{listener: listener, maxSize: maxSize, reply: reply, received: make(chan []byte, 1)}
+	t.Cleanup(func() { listener.Close() })
+	go f.serve()
+	return f
+}
+
+func (f *fakeClamd) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeClamd) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	command, err := r.ReadString(0)
+	if err != nil || command != "zINSTREAM\x00" {
+		conn.Write([]byte("UNKNOWN COMMAND\x00"))
+		return
+	}
+
+	var data []byte
+	size := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(r, size); err != nil {
+			return
+		}
+		n := binary.BigEndian.Uint32(size)
+		if n == 0 {
+			break
+		}
+		chunk := make([]byte, n)
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return
+		}
+		data = append(data, chunk...)
+		if f.maxSize > 0 && len(data) > f.maxSize {
+			conn.Write([]byte("INSTREAM size limit exceeded. ERROR\x00"))
+			return
+		}
+	}
+
+	f.received <- data
+	conn.Write([]byte(f.reply(data) + "\x00"))
+}
+
+func (f *fakeClamd) scanner() *ClamdScanner {
+	return NewClamdScanner("tcp://"+f.listener.Addr().String(), 2*time.Second)
+}
+
+func TestClamdScannerClean(t *testing.T) {
+	clamd := newFakeClamd(t, 0, func([]byte) string { return "stream: OK" })
+
+	// Más de un bloque, para verificar el framing de INSTREAM
+	content := bytes.Repeat([]byte("documento"), clamdChunkSize/4)
+	result, err := clamd.scanner().Scan(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if result.Infected {
+		t.Errorf("resultado = %+v, se esperaba limpio", result)
+	}
+	if received := <-clamd.received; !bytes.Equal(received, content) {
+		t.Errorf("clamd recibió %d bytes, se enviaron %d", len(received), len(content))
+	}
+}
+
+func TestClamdScannerInfected(t *testing.T) {
+	clamd := newFakeClamd(t, 0, func(data []byte) string {
+		if bytes.Contains(data, []byte("EICAR")) {
+			return "stream: Eicar-Test-Signature FOUND"
+		}
+		return "stream: OK"
+	})
+
+	result, err := clamd.scanner().Scan(strings.NewReader(`X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`))
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if !result.Infected || result.Signature != "Eicar-Test-Signature" {
+		t.Errorf("resultado = %+v, se esperaba infectado con Eicar-Test-Signature", result)
+	}
+}
+
+func TestClamdScannerErrorReply(t *testing.T) {
+	clamd := newFakeClamd(t, 0, func([]byte) string { return "stream: Can't allocate memory ERROR" })
+
+	if _, err := clamd.scanner().Scan(strings.NewReader("documento")); err == nil {
+		t.Error("se esperaba error con una respuesta ERROR de clamd")
+	}
+}
+
+func TestClamdScannerSizeLimit(t *testing.T) {
+	clamd := newFakeClamd(t, 1024, func([]byte) string { return "stream: OK" })
+
+	if _, err := clamd.scanner().Scan(bytes.NewReader(make([]byte, 4*clamdChunkSize))); err == nil {
+		t.Error("se esperaba error al superar el límite de tamaño de clamd")
+	}
+}
+
+func TestClamdScannerUnavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	if _, err := NewClamdScanner(address, time.Second).Scan(strings.NewReader("documento")); err == nil {
+		t.Error("se esperaba error sin clamd disponible")
+	}
+}
+
+func TestNewClamdScannerAddress(t *testing.T) {
+	tests := []struct {
+		address, network, want string
+	}{
+		{"127.0.0.1:3310", "tcp", "127.0.0.1:3310"},
+		{"tcp://clamav:3310", "tcp", "clamav:3310"},
+		{"unix:///var/run/clamav/clamd.ctl", "unix", "/var/run/clamav/clamd.ctl"},
+	}
+	for _, tt := range tests {
+		s := NewClamdScanner(tt.address, time.Second)
+		if s.Network != tt.network || s.Address != tt.want {
+			t.Errorf("NewClamdScanner(%q) = %s %s, se esperaba %s %s", tt.address, s.Network, s.Address, tt.network, tt.want)
+		}
+	}
+}
+
+func TestParseClamdReply(t *testing.T) {
+	tests := []struct {
+		reply     string
+		infected  bool
+		signature string
+		wantErr   bool
+	}{
+		{"stream: OK\x00", false, "", false},
+		{"OK", false, "", false},
+		{"stream: Win.Test.EICAR_HDB-1 FOUND\x00", true, "Win.Test.EICAR_HDB-1", false},
+		{"INSTREAM size limit exceeded. ERROR\x00", false, "", true},
+		{"stream: lstat() failed ERROR", false, "", true},
+		{"", false, "", true},
+	}
+	for _, tt := range tests {
+		result, err := parseClamdReply(tt.reply)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseClamdReply(%q): error = %v, se esperaba error: %v", tt.reply, err, tt.wantErr)
+			continue
+		}
+		if result.Infected != tt.infected || result.Signature != tt.signature {
+			t.Errorf("parseClamdReply(%q) = %+v", tt.reply, result)
+		}
+	}
+}
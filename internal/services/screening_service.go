@@ -0,0 +1,437 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"time"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ScreeningService importa listas de sanciones/PEP y compara a los usuarios contra ellas
+type ScreeningService struct {
+	DB        *sql.DB
+	Threshold float64 // similitud mínima de nombre para registrar una coincidencia
+	listeners []MatchResolutionListener
+}
+
+func NewScreeningService(db *sql.DB, threshold float64) *ScreeningService {
+	return &ScreeningService{
+		DB:        db,
+		Threshold: threshold,
+	}
+}
+
+// screeningCandidate nombre o alias de una entrada preparado para comparar
+type screeningCandidate struct {
+	watchlistID uuid.UUID
+	externalID  string
+	name        string
+	field       string
+	tokens      []string
+}
+
+// screeningIndex entradas de las listas agrupadas por trigramas de palabra para reducir comparaciones.
+// Un error de tipeo solo deja fuera los trigramas que toca, así que el candidato sigue comparándose.
+type screeningIndex struct {
+	byTrigram      map[string][]*screeningCandidate
+	byDocument     map[string][]*screeningCandidate
+	candidateCount int
+}
+
+type screeningUser struct {
+	id             uuid.UUID
+	fullName       string
+	documentNumber string
+}
+
+// AddResolutionListener registra un listener de coincidencias resueltas
+func (s *ScreeningService) AddResolutionListener(listener MatchResolutionListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// HandleUserEvent compara a cada usuario nuevo contra las listas cargadas
+func (s *ScreeningService) HandleUserEvent(event models.UserEvent) error {
+	if event.Type != models.UserEventRegistered {
+		return nil
+	}
+	_, err := s.ScreenUser(event.UserID)
+	return err
+}
+
+// CheckKYCApproval impide aprobar el KYC de usuarios con coincidencias sin resolver o confirmadas
+func (s *ScreeningService) CheckKYCApproval(userID uuid.UUID) error {
+	var pending, confirmed int
+	err := s.DB.QueryRow(`
+		SELECT COUNT(CASE WHEN status = 'pending' THEN 1 END),
+		       COUNT(CASE WHEN status = 'confirmed' THEN 1 END)
+		FROM screening_hits WHERE user_id = $1
+	`, userID).Scan(&pending, &confirmed)
+	if err != nil {
+		return fmt.Errorf("error verificando screening: %v", err)
+	}
+
+	if confirmed > 0 {
+		return fmt.Errorf("el usuario tiene coincidencias confirmadas en listas de sanciones/PEP")
+	}
+	if pending > 0 {
+		return fmt.Errorf("el usuario tiene %d coincidencias de screening sin resolver", pending)
+	}
+
+	return nil
+}
+
+// ImportWatchlist carga (o reemplaza) una lista y vuelve a evaluar a todos los usuarios contra ella
+func (s *ScreeningService) ImportWatchlist(name, format, listType string, r io.Reader, adminID uuid.UUID) (*models.Watchlist, int, error) {
+	entries, err := parseWatchlist(format, r)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(entries) == 0 {
+		return nil, 0, fmt.Errorf("la lista no contiene entradas")
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+
+	var list models.Watchlist
+	err = tx.QueryRow(`
+		INSERT INTO watchlists (name, source_format, list_type, entry_count, imported_by, imported_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (name) DO UPDATE SET
+			source_format = EXCLUDED.source_format,
+			list_type = EXCLUDED.list_type,
+			entry_count = EXCLUDED.entry_count,
+			imported_by = EXCLUDED.imported_by,
+			imported_at = EXCLUDED.imported_at
+		RETURNING id, name, source_format, list_type, entry_count, imported_by, imported_at, created_at
+	`, name, format, listType, len(entries), adminID, time.Now()).Scan(
+		&list.ID, &list.Name, &list.SourceFormat, &list.ListType, &list.EntryCount,
+		&list.ImportedBy, &list.ImportedAt, &list.CreatedAt,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error guardando lista: %v", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM watchlist_entries WHERE watchlist_id = $1", list.ID); err != nil {
+		return nil, 0, fmt.Errorf("error reemplazando entradas: %v", err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO watchlist_entries (
+			watchlist_id, external_id, entry_type, name, aliases, document_numbers, country, program
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		_, err := stmt.Exec(list.ID, entry.ExternalID, entry.EntryType, entry.Name,
+			pq.Array(entry.Aliases), pq.Array(entry.DocumentNumbers), entry.Country, entry.Program)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error guardando entrada '%s': %v", entry.Name, err)
+		}
+	}
+
+	// Las coincidencias pendientes contra entradas retiradas de la lista dejan de bloquear la aprobación
+	_, err = tx.Exec(`
+		UPDATE screening_hits h
+		SET status = $2, reviewed_at = $3
+		WHERE h.watchlist_id = $1 AND h.status = $4
+		  AND NOT EXISTS (
+			SELECT 1 FROM watchlist_entries e
+			WHERE e.watchlist_id = $1 AND (e.name = h.matched_name OR h.matched_name = ANY(e.aliases))
+		  )
+	`, list.ID, models.ScreeningHitSuperseded, time.Now(), models.ScreeningHitPending)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error cerrando coincidencias de entradas retiradas: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, err
+	}
+
+	hits, err := s.screenUsers(&list.ID, nil)
+	if err != nil {
+		return &list, 0, err
+	}
+
+	return &list, hits, nil
+}
+
+// GetWatchlists obtiene las listas cargadas
+func (s *ScreeningService) GetWatchlists() ([]models.Watchlist, error) {
+	rows, err := s.DB.Query(`
+		SELECT id, name, source_format, list_type, entry_count, imported_by, imported_at, created_at
+		FROM watchlists ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo listas: %v", err)
+	}
+	defer rows.Close()
+
+	var lists []models.Watchlist
+	for rows.Next() {
+		var list models.Watchlist
+		err := rows.Scan(&list.ID, &list.Name, &list.SourceFormat, &list.ListType, &list.EntryCount,
+			&list.ImportedBy, &list.ImportedAt, &list.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando lista: %v", err)
+		}
+		lists = append(lists, list)
+	}
+
+	return lists, nil
+}
+
+// ScreenUser compara un usuario contra todas las listas y devuelve el número de coincidencias
+func (s *ScreeningService) ScreenUser(userID uuid.UUID) (int, error) {
+	return s.screenUsers(nil, &userID)
+}
+
+// screenUsers compara usuarios (uno o todos) contra una lista (o todas)
+func (s *ScreeningService) screenUsers(watchlistID, userID *uuid.UUID) (int, error) {
+	index, err := s.loadIndex(watchlistID)
+	if err != nil {
+		return 0, err
+	}
+	if index.candidateCount == 0 {
+		return 0, nil
+	}
+
+	query := "SELECT id, first_name || ' ' || last_name, COALESCE(document_number_normalized, '') FROM users WHERE role = 'user'"
+	args := []interface{}{}
+	if userID != nil {
+		query += " AND id = $1"
+		args = append(args, *userID)
+	}
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("error obteniendo usuarios: %v", err)
+	}
+	defer rows.Close()
+
+	var users []screeningUser
+	for rows.Next() {
+		var u screeningUser
+		if err := rows.Scan(&u.id, &u.fullName, &u.documentNumber); err != nil {
+			return 0, fmt.Errorf("error escaneando usuario: %v", err)
+		}
+		users = append(users, u)
+	}
+
+	hits := 0
+	for _, u := range users {
+		for _, match := range s.matchUser(index, u) {
+			if err := s.recordHit(u.id, match.candidate, match.score, match.field); err != nil {
+				return hits, err
+			}
+			hits++
+		}
+	}
+
+	return hits, nil
+}
+
+type screeningMatch struct {
+	candidate *screeningCandidate
+	score     float64
+	field     string
+}
+
+// matchUser busca coincidencias por documento exacto y por similitud de nombre
+func (s *ScreeningService) matchUser(index *screeningIndex, u screeningUser) []screeningMatch {
+	var matches []screeningMatch
+	seen := make(map[*screeningCandidate]bool)
+
+	if u.documentNumber != "" {
+		for _, c := range index.byDocument[u.documentNumber] {
+			seen[c] = true
+			matches = append(matches, screeningMatch{candidate: c, score: 1, field: "document"})
+		}
+	}
+
+	tokens := nameTokens(u.fullName)
+	for _, token := range tokens {
+		for _, trigram := range tokenTrigrams(token) {
+			for _, c := range index.byTrigram[trigram] {
+				if seen[c] {
+					continue
+				}
+				seen[c] = true
+				if score := nameSimilarity(tokens, c.tokens); score >= s.Threshold {
+					matches = append(matches, screeningMatch{candidate: c, score: score, field: c.field})
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+// loadIndex carga las entradas de las listas y las indexa por trigramas de palabra y documento
+func (s *ScreeningService) loadIndex(watchlistID *uuid.UUID) (*screeningIndex, error) {
+	query := "SELECT watchlist_id, COALESCE(external_id, ''), name, aliases, document_numbers FROM watchlist_entries"
+	args := []interface{}{}
+	if watchlistID != nil {
+		query += " WHERE watchlist_id = $1"
+		args = append(args, *watchlistID)
+	}
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo entradas de listas: %v", err)
+	}
+	defer rows.Close()
+
+	index := newScreeningIndex()
+
+	for rows.Next() {
+		var listID uuid.UUID
+		var externalID, name string
+		var aliases, documents []string
+		if err := rows.Scan(&listID, &externalID, &name, pq.Array(&aliases), pq.Array(&documents)); err != nil {
+			return nil, fmt.Errorf("error escaneando entrada de lista: %v", err)
+		}
+
+		primary := &screeningCandidate{watchlistID: listID, externalID: externalID, name: name, field: "name", tokens: nameTokens(name)}
+		index.add(primary)
+		for _, number := range documents {
+			index.byDocument[number] = append(index.byDocument[number], primary)
+		}
+		for _, alias := range aliases {
+			index.add(&screeningCandidate{watchlistID: listID, externalID: externalID, name: alias, field: "alias", tokens: nameTokens(alias)})
+		}
+	}
+
+	return index, nil
+}
+
+func newScreeningIndex() *screeningIndex {
+	return &screeningIndex{
+		byTrigram:  make(map[string][]*screeningCandidate),
+		byDocument: make(map[string][]*screeningCandidate),
+	}
+}
+
+func (idx *screeningIndex) add(c *screeningCandidate) {
+	idx.candidateCount++
+	added := make(map[string]bool)
+	for _, token := range c.tokens {
+		for _, trigram := range tokenTrigrams(token) {
+			if added[trigram] {
+				continue
+			}
+			added[trigram] = true
+			idx.byTrigram[trigram] = append(idx.byTrigram[trigram], c)
+		}
+	}
+}
+
+// tokenTrigrams secuencias de tres letras de una palabra, usadas para agrupar candidatos
+func tokenTrigrams(token string) []string {
+	runes := []rune(token)
+	if len(runes) <= 3 {
+		return []string{token}
+	}
+	trigrams := make([]string, 0, len(runes)-2)
+	for i := 0; i+3 <= len(runes); i++ {
+		trigrams = append(trigrams, string(runes[i:i+3]))
+	}
+	return trigrams
+}
+
+// recordHit guarda una coincidencia; las ya adjudicadas conservan su estado y las
+// cerradas porque la entrada se retiró vuelven a quedar pendientes si reaparece
+func (s *ScreeningService) recordHit(userID uuid.UUID, c *screeningCandidate, score float64, field string) error {
+	var externalID *string
+	if c.externalID != "" {
+		externalID = &c.externalID
+	}
+
+	_, err := s.DB.Exec(`
+		INSERT INTO screening_hits (user_id, watchlist_id, entry_external_id, matched_name, match_field, score)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, watchlist_id, matched_name) DO UPDATE SET
+			score = EXCLUDED.score,
+			match_field = EXCLUDED.match_field,
+			status = CASE WHEN screening_hits.status = $7 THEN $8 ELSE screening_hits.status END,
+			reviewed_at = CASE WHEN screening_hits.status = $7 THEN NULL ELSE screening_hits.reviewed_at END
+	`, userID, c.watchlistID, externalID, c.name, field, score, models.ScreeningHitSuperseded, models.ScreeningHitPending)
+	if err != nil {
+		return fmt.Errorf("error registrando coincidencia de screening: %v", err)
+	}
+	return nil
+}
+
+// GetHits obtiene las coincidencias filtradas por estado
+func (s *ScreeningService) GetHits(status string) ([]models.ScreeningHit, error) {
+	query := `
+		SELECT h.id, h.user_id, u.first_name || ' ' || u.last_name, u.email,
+		       h.watchlist_id, w.name, w.list_type, h.entry_external_id, h.matched_name,
+		       h.match_field, h.score, h.status, h.note, h.reviewed_by, h.reviewed_at, h.created_at
+		FROM screening_hits h
+		JOIN users u ON u.id = h.user_id
+		JOIN watchlists w ON w.id = h.watchlist_id
+	`
+
+	args := []interface{}{}
+	if status != "" {
+		query += " WHERE h.status = $1"
+		args = append(args, status)
+	}
+
+	query += " ORDER BY h.score DESC, h.created_at DESC"
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo coincidencias: %v", err)
+	}
+	defer rows.Close()
+
+	var hits []models.ScreeningHit
+	for rows.Next() {
+		var hit models.ScreeningHit
+		err := rows.Scan(
+			&hit.ID, &hit.UserID, &hit.UserName, &hit.UserEmail,
+			&hit.WatchlistID, &hit.WatchlistName, &hit.ListType, &hit.EntryExternalID, &hit.MatchedName,
+			&hit.MatchField, &hit.Score, &hit.Status, &hit.Note, &hit.ReviewedBy, &hit.ReviewedAt, &hit.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando coincidencia: %v", err)
+		}
+		hits = append(hits, hit)
+	}
+
+	return hits, nil
+}
+
+// ResolveHit registra la adjudicación de una coincidencia por parte de un administrador
+func (s *ScreeningService) ResolveHit(id uuid.UUID, req models.ResolveScreeningHitRequest, adminID uuid.UUID) error {
+	var userID uuid.UUID
+	err := s.DB.QueryRow(`
+		UPDATE screening_hits
+		SET status = $1, note = $2, reviewed_by = $3, reviewed_at = $4
+		WHERE id = $5
+		RETURNING user_id
+	`, req.Status, req.Note, adminID, time.Now(), id).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("coincidencia no encontrada")
+	}
+	if err != nil {
+		return fmt.Errorf("error actualizando coincidencia: %v", err)
+	}
+
+	notifyMatchResolved(s.listeners, "screening_resolved", userID)
+	return nil
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenTrigrams(t *testing.T) {
+	tests := []struct {
+		token string
+		want  []string
+	}{
+		{"perez", []string{"per", "ere", "rez"}},
+		{"ana", []string{"ana"}},
+		{"nuñez", []string{"nuñ", "uñe", "ñez"}},
+	}
+	for _, tt := range tests {
+		if got := tokenTrigrams(tt.token); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tokenTrigrams(%q) = %v, se esperaba %v", tt.token, got, tt.want)
+		}
+	}
+}
+
+// Un error de tipeo en las primeras letras no debe impedir la comparación
+func TestMatchUserTypoInLeadingLetters(t *testing.T) {
+	index := newScreeningIndex()
+	entry := &screeningCandidate{name: "Gustavo Hernandez", field: "name", tokens: nameTokens("Gustavo Hernandez")}
+	index.add(entry)
+
+	s := &ScreeningService{Threshold: 0.85}
+	for _, name := range []string{"Gustavo Hernandez", "Justavo Ernandez", "Hernandes Gustabo"} {
+		matches := s.matchUser(index, screeningUser{fullName: name})
+		if len(matches) != 1 || matches[0].candidate != entry {
+			t.Errorf("matchUser(%q) = %v, se esperaba una coincidencia con %q", name, matches, entry.name)
+		}
+	}
+
+	if matches := s.matchUser(index, screeningUser{fullName: "Maria Lopez"}); len(matches) != 0 {
+		t.Errorf("no se esperaban coincidencias para un nombre distinto, se obtuvo %v", matches)
+	}
+}
@@ -0,0 +1,82 @@
+package services
+
+import "tradeoptix-back/internal/models"
+
+// maxDiffCells límite de la tabla LCS; por encima el diff se reporta como reemplazo completo
+const maxDiffCells = 4_000_000
+
+// diffLines calcula un diff por líneas (subsecuencia común más larga)
+func diffLines(a, b []string) []models.DiffLine {
+	// Las líneas comunes al inicio y al final no necesitan la tabla
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]models.DiffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		lines = append(lines, models.DiffLine{Op: "equal", Text: line})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if (len(midA)+1)*(len(midB)+1) > maxDiffCells {
+		for _, line := range midA {
+			lines = append(lines, models.DiffLine{Op: "delete", Text: line})
+		}
+		for _, line := range midB {
+			lines = append(lines, models.DiffLine{Op: "insert", Text: line})
+		}
+	} else {
+		lines = append(lines, lcsDiff(midA, midB)...)
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, models.DiffLine{Op: "equal", Text: line})
+	}
+	return lines
+}
+
+func lcsDiff(a, b []string) []models.DiffLine {
+	// lcs[i][j] = largo de la subsecuencia común más larga de a[i:] y b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []models.DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, models.DiffLine{Op: "equal", Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, models.DiffLine{Op: "delete", Text: a[i]})
+			i++
+		default:
+			lines = append(lines, models.DiffLine{Op: "insert", Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, models.DiffLine{Op: "delete", Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, models.DiffLine{Op: "insert", Text: b[j]})
+	}
+	return lines
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"fmt"
+
+	"tradeoptix-back/internal/models"
+)
+
+// UserEventListener recibe los eventos emitidos por UserService
+type UserEventListener interface {
+	HandleUserEvent(event models.UserEvent) error
+}
+
+// AddEventListener registra un listener de eventos de usuario
+func (s *UserService) AddEventListener(listener UserEventListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// emit notifica un evento a todos los listeners; los errores se registran pero no interrumpen el flujo
+func (s *UserService) emit(event models.UserEvent) {
+	for _, listener := range s.listeners {
+		if err := listener.HandleUserEvent(event); err != nil {
+			fmt.Printf("Error procesando evento %s para usuario %v: %v\n", event.Type, event.UserID, err)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package services
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 	"tradeoptix-back/internal/models"
 
@@ -14,6 +16,7 @@ import (
 type UserService struct {
 	DB        *sql.DB
 	JWTSecret string
+	listeners []UserEventListener
 }
 
 func NewUserService(db *sql.DB, jwtSecret string) *UserService {
@@ -34,8 +37,9 @@ func (s *UserService) RegisterUser(req models.UserRegistrationRequest) (*models.
 		return nil, errors.New("el email ya está registrado")
 	}
 
-	// Verificar si el documento ya existe
-	err = s.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE document_number = $1)", req.DocumentNumber).Scan(&exists)
+	// Verificar si el documento ya existe (ignorando guiones, espacios y mayúsculas)
+	normalizedDocument := NormalizeDocumentNumber(req.DocumentNumber)
+	err = s.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE document_number_normalized = $1)", normalizedDocument).Scan(&exists)
 	if err != nil {
 		return nil, err
 	}
@@ -77,31 +81,44 @@ func (s *UserService) RegisterUser(req models.UserRegistrationRequest) (*models.
 			id, first_name, last_name, document_type, document_number,
 			email, phone_number, address, facebook_profile, instagram_profile,
 			twitter_profile, linkedin_profile, password_hash, role, kyc_status,
-			email_verified, created_at, updated_at
+			email_verified, created_at, updated_at, document_number_normalized
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
 		)`
 
 	_, err = s.DB.Exec(query,
 		user.ID, user.FirstName, user.LastName, user.DocumentType, user.DocumentNumber,
 		user.Email, user.PhoneNumber, user.Address, user.FacebookProfile, user.InstagramProfile,
 		user.TwitterProfile, user.LinkedinProfile, user.PasswordHash, user.Role, user.KYCStatus,
-		user.EmailVerified, user.CreatedAt, user.UpdatedAt,
+		user.EmailVerified, user.CreatedAt, user.UpdatedAt, normalizedDocument,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	s.emit(models.UserEvent{Type: models.UserEventRegistered, UserID: user.ID})
+
 	return user, nil
 }
 
+// NormalizeDocumentNumber elimina separadores y unifica mayúsculas ("v-12.345.678" -> "V12345678")
+func NormalizeDocumentNumber(documentNumber string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(documentNumber) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
 func (s *UserService) LoginUser(req models.UserLoginRequest) (*models.LoginResponse, error) {
 	var user models.User
 	query := `
 		SELECT id, first_name, last_name, document_type, document_number,
 		       email, phone_number, address, facebook_profile, instagram_profile,
 		       twitter_profile, linkedin_profile, password_hash, role, kyc_status,
-		       email_verified, created_at, updated_at
+		       kyc_level, email_verified, closed_at, locale, created_at, updated_at
 		FROM users WHERE email = $1
 	`
 
@@ -109,7 +126,7 @@ func (s *UserService) LoginUser(req models.UserLoginRequest) (*models.LoginRespo
 		&user.ID, &user.FirstName, &user.LastName, &user.DocumentType, &user.DocumentNumber,
 		&user.Email, &user.PhoneNumber, &user.Address, &user.FacebookProfile, &user.InstagramProfile,
 		&user.TwitterProfile, &user.LinkedinProfile, &user.PasswordHash, &user.Role, &user.KYCStatus,
-		&user.EmailVerified, &user.CreatedAt, &user.UpdatedAt,
+		&user.KYCLevel, &user.EmailVerified, &user.ClosedAt, &user.Locale, &user.CreatedAt, &user.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -121,11 +138,16 @@ func (s *UserService) LoginUser(req models.UserLoginRequest) (*models.LoginRespo
 	// Verificar contraseña
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password))
 	if err != nil {
+		s.recordFailedLogin(user.ID)
 		return nil, errors.New("credenciales inválidas")
 	}
 
+	if user.ClosedAt != nil {
+		return nil, errors.New("cuenta cerrada")
+	}
+
 	// Generar JWT token
-	token, expiresAt, err := s.GenerateJWT(user.ID, user.Email, user.Role)
+	token, expiresAt, err := s.GenerateJWT(user.ID, user.Email, user.Role, user.KYCLevel)
 	if err != nil {
 		return nil, err
 	}
@@ -137,14 +159,26 @@ func (s *UserService) LoginUser(req models.UserLoginRequest) (*models.LoginRespo
 	}, nil
 }
 
-func (s *UserService) GenerateJWT(userID uuid.UUID, email string, role models.UserRole) (string, time.Time, error) {
+// recordFailedLogin contabiliza un intento fallido de inicio de sesión
+func (s *UserService) recordFailedLogin(userID uuid.UUID) {
+	_, err := s.DB.Exec("UPDATE users SET failed_login_attempts = failed_login_attempts + 1 WHERE id = $1", userID)
+	if err != nil {
+		fmt.Printf("Error registrando intento fallido para usuario %v: %v\n", userID, err)
+		return
+	}
+
+	s.emit(models.UserEvent{Type: models.UserEventLoginFailed, UserID: userID})
+}
+
+func (s *UserService) GenerateJWT(userID uuid.UUID, email string, role models.UserRole, kycLevel int) (string, time.Time, error) {
 	expiresAt := time.Now().Add(24 * time.Hour)
 
 	claims := jwt.MapClaims{
-		"user_id": userID.String(),
-		"email":   email,
-		"role":    string(role),
-		"exp":     expiresAt.Unix(),
+		"user_id":   userID.String(),
+		"email":     email,
+		"role":      string(role),
+		"kyc_level": kycLevel,
+		"exp":       expiresAt.Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -156,21 +190,47 @@ func (s *UserService) GenerateJWT(userID uuid.UUID, email string, role models.Us
 	return tokenString, expiresAt, nil
 }
 
+// RefreshToken emite un nuevo token con el rol y el nivel KYC actuales del usuario
+func (s *UserService) RefreshToken(userID uuid.UUID) (*models.LoginResponse, error) {
+	user, err := s.GetUserByID(userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("usuario no encontrado")
+		}
+		return nil, err
+	}
+
+	if user.ClosedAt != nil {
+		return nil, errors.New("cuenta cerrada")
+	}
+
+	token, expiresAt, err := s.GenerateJWT(user.ID, user.Email, user.Role, user.KYCLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginResponse{
+		Token:     token,
+		User:      *user,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
 func (s *UserService) GetUserByID(userID uuid.UUID) (*models.User, error) {
 	var user models.User
 	query := `
 		SELECT id, first_name, last_name, document_type, document_number,
 		       email, phone_number, address, facebook_profile, instagram_profile,
-		       twitter_profile, linkedin_profile, role, kyc_status,
-		       email_verified, created_at, updated_at
+		       twitter_profile, linkedin_profile, role, kyc_status, kyc_level,
+		       email_verified, closed_at, locale, created_at, updated_at
 		FROM users WHERE id = $1
 	`
 
 	err := s.DB.QueryRow(query, userID).Scan(
 		&user.ID, &user.FirstName, &user.LastName, &user.DocumentType, &user.DocumentNumber,
 		&user.Email, &user.PhoneNumber, &user.Address, &user.FacebookProfile, &user.InstagramProfile,
-		&user.TwitterProfile, &user.LinkedinProfile, &user.Role, &user.KYCStatus,
-		&user.EmailVerified, &user.CreatedAt, &user.UpdatedAt,
+		&user.TwitterProfile, &user.LinkedinProfile, &user.Role, &user.KYCStatus, &user.KYCLevel,
+		&user.EmailVerified, &user.ClosedAt, &user.Locale, &user.CreatedAt, &user.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -179,13 +239,56 @@ func (s *UserService) GetUserByID(userID uuid.UUID) (*models.User, error) {
 	return &user, nil
 }
 
+// SetLocale guarda el idioma preferido del usuario (código ISO 639-1, ej: "en"); nil lo elimina
+func (s *UserService) SetLocale(userID uuid.UUID, locale *string) error {
+	if locale != nil && !languageCodePattern.MatchString(*locale) {
+		return errors.New("idioma inválido")
+	}
+
+	result, err := s.DB.Exec("UPDATE users SET locale = $1 WHERE id = $2", locale, userID)
+	if err != nil {
+		return fmt.Errorf("error guardando idioma: %v", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("usuario no encontrado")
+	}
+	return nil
+}
+
+// GetLocale obtiene el idioma preferido del usuario (nil si no eligió uno)
+func (s *UserService) GetLocale(userID uuid.UUID) (*string, error) {
+	var locale *string
+	if err := s.DB.QueryRow("SELECT locale FROM users WHERE id = $1", userID).Scan(&locale); err != nil {
+		return nil, fmt.Errorf("error obteniendo idioma: %v", err)
+	}
+	return locale, nil
+}
+
+// CloseAccount cierra la cuenta de un usuario: ya no puede iniciar sesión y sus archivos KYC
+// pasan a la política de retención de cuentas cerradas
+func (s *UserService) CloseAccount(userID uuid.UUID) error {
+	result, err := s.DB.Exec("UPDATE users SET closed_at = $1 WHERE id = $2 AND role = 'user' AND closed_at IS NULL",
+		time.Now(), userID)
+	if err != nil {
+		return fmt.Errorf("error cerrando cuenta: %v", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("usuario no encontrado")
+	}
+
+	return nil
+}
+
 func (s *UserService) GetAllUsers() ([]models.User, error) {
 	var users []models.User
 	query := `
 		SELECT id, first_name, last_name, document_type, document_number,
 		       email, phone_number, address, facebook_profile, instagram_profile,
-		       twitter_profile, linkedin_profile, role, kyc_status,
-		       email_verified, created_at, updated_at
+		       twitter_profile, linkedin_profile, role, kyc_status, kyc_level,
+		       email_verified, risk_score, risk_level, legal_hold, closed_at, locale, created_at, updated_at
 		FROM users 
 		ORDER BY created_at DESC
 	`
@@ -201,8 +304,9 @@ func (s *UserService) GetAllUsers() ([]models.User, error) {
 		err := rows.Scan(
 			&user.ID, &user.FirstName, &user.LastName, &user.DocumentType, &user.DocumentNumber,
 			&user.Email, &user.PhoneNumber, &user.Address, &user.FacebookProfile, &user.InstagramProfile,
-			&user.TwitterProfile, &user.LinkedinProfile, &user.Role, &user.KYCStatus,
-			&user.EmailVerified, &user.CreatedAt, &user.UpdatedAt,
+			&user.TwitterProfile, &user.LinkedinProfile, &user.Role, &user.KYCStatus, &user.KYCLevel,
+			&user.EmailVerified, &user.RiskScore, &user.RiskLevel, &user.LegalHold, &user.ClosedAt, &user.Locale,
+			&user.CreatedAt, &user.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -268,16 +372,39 @@ func (s *UserService) GetDashboardStats() (map[string]interface{}, error) {
 	}
 	stats["new_users_this_month"] = newUsersMonth
 
+	// Rechazos KYC por motivo
+	rows, err := s.DB.Query(`
+		SELECT reason_code, COUNT(*)
+		FROM kyc_document_rejections
+		GROUP BY reason_code
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rejectionsByReason := make(map[string]int)
+	for rows.Next() {
+		var code string
+		var count int
+		if err := rows.Scan(&code, &count); err != nil {
+			return nil, err
+		}
+		rejectionsByReason[code] = count
+	}
+	stats["rejections_by_reason"] = rejectionsByReason
+
 	return stats, nil
 }
 
-func (s *UserService) GetUsersByKYCStatus(status string) ([]models.User, error) {
+// GetUsersByKYCStatus obtiene usuarios filtrados por estado KYC; sortBy "risk" ordena por mayor riesgo primero
+func (s *UserService) GetUsersByKYCStatus(status, sortBy string) ([]models.User, error) {
 	var users []models.User
 	query := `
 		SELECT id, first_name, last_name, document_type, document_number,
 		       email, phone_number, address, facebook_profile, instagram_profile,
-		       twitter_profile, linkedin_profile, role, kyc_status,
-		       email_verified, created_at, updated_at
+		       twitter_profile, linkedin_profile, role, kyc_status, kyc_level,
+		       email_verified, risk_score, risk_level, legal_hold, closed_at, locale, created_at, updated_at
 		FROM users 
 	`
 
@@ -287,7 +414,11 @@ func (s *UserService) GetUsersByKYCStatus(status string) ([]models.User, error)
 		args = append(args, status)
 	}
 
-	query += " ORDER BY created_at DESC"
+	if sortBy == "risk" {
+		query += " ORDER BY risk_score DESC NULLS LAST, created_at ASC"
+	} else {
+		query += " ORDER BY created_at DESC"
+	}
 
 	rows, err := s.DB.Query(query, args...)
 	if err != nil {
@@ -300,8 +431,9 @@ func (s *UserService) GetUsersByKYCStatus(status string) ([]models.User, error)
 		err := rows.Scan(
 			&user.ID, &user.FirstName, &user.LastName, &user.DocumentType, &user.DocumentNumber,
 			&user.Email, &user.PhoneNumber, &user.Address, &user.FacebookProfile, &user.InstagramProfile,
-			&user.TwitterProfile, &user.LinkedinProfile, &user.Role, &user.KYCStatus,
-			&user.EmailVerified, &user.CreatedAt, &user.UpdatedAt,
+			&user.TwitterProfile, &user.LinkedinProfile, &user.Role, &user.KYCStatus, &user.KYCLevel,
+			&user.EmailVerified, &user.RiskScore, &user.RiskLevel, &user.LegalHold, &user.ClosedAt, &user.Locale,
+			&user.CreatedAt, &user.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
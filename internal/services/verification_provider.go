@@ -0,0 +1,188 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"tradeoptix-back/internal/models"
+)
+
+// VerificationProvider integra un proveedor externo de verificación de identidad (IDV)
+type VerificationProvider interface {
+	// Name identifica al proveedor en la URL del webhook (/webhooks/idv/:provider)
+	Name() string
+	// Submit envía los documentos del usuario y devuelve la referencia asignada por el proveedor
+	Submit(submission models.IDVSubmission) (string, error)
+	// ParseWebhook valida la firma del callback y extrae el veredicto
+	ParseWebhook(header http.Header, body []byte) (*models.IDVVerdict, error)
+}
+
+// Encabezados del formato de webhook firmado (HMAC-SHA256 sobre "timestamp.cuerpo")
+const (
+	WebhookSignatureHeader = "X-IDV-Signature"
+	WebhookTimestampHeader = "X-IDV-Timestamp"
+)
+
+// webhookTolerance antigüedad máxima aceptada de un webhook firmado (evita reenvíos)
+const webhookTolerance = 5 * time.Minute
+
+// signWebhookPayload calcula la firma "sha256=<hex>" de un webhook
+func signWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyWebhookSignature comprueba la firma y la vigencia de un webhook
+func verifyWebhookSignature(secret string, header http.Header, body []byte) error {
+	if secret == "" {
+		return fmt.Errorf("secreto de webhook no configurado")
+	}
+
+	timestamp, err := strconv.ParseInt(header.Get(WebhookTimestampHeader), 10, 64)
+	if err != nil {
+		return fmt.Errorf("firma inválida: timestamp ausente")
+	}
+	age := time.Since(time.Unix(timestamp, 0))
+	if age > webhookTolerance || age < -webhookTolerance {
+		return fmt.Errorf("firma inválida: webhook vencido")
+	}
+
+	expected := signWebhookPayload(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(header.Get(WebhookSignatureHeader))) {
+		return fmt.Errorf("firma inválida")
+	}
+
+	return nil
+}
+
+// webhookVerdictPayload cuerpo del webhook en el formato firmado genérico
+type webhookVerdictPayload struct {
+	Reference   string             `json:"reference"`
+	Decision    models.IDVDecision `json:"decision"`
+	Confidence  float64            `json:"confidence"`
+	ReasonCodes []string           `json:"reason_codes"`
+}
+
+// parseSignedVerdict valida la firma y decodifica un webhook en el formato genérico
+func parseSignedVerdict(secret string, header http.Header, body []byte) (*models.IDVVerdict, error) {
+	if err := verifyWebhookSignature(secret, header, body); err != nil {
+		return nil, err
+	}
+
+	var payload webhookVerdictPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("payload inválido: %v", err)
+	}
+
+	switch payload.Decision {
+	case models.IDVDecisionApproved, models.IDVDecisionRejected, models.IDVDecisionReview:
+	default:
+		return nil, fmt.Errorf("payload inválido: decisión desconocida %q", payload.Decision)
+	}
+	if payload.Reference == "" || payload.Confidence < 0 || payload.Confidence > 1 {
+		return nil, fmt.Errorf("payload inválido: referencia o confianza fuera de rango")
+	}
+
+	return &models.IDVVerdict{
+		ExternalID:  payload.Reference,
+		Decision:    payload.Decision,
+		Confidence:  payload.Confidence,
+		ReasonCodes: payload.ReasonCodes,
+		Payload:     body,
+	}, nil
+}
+
+// FakeVerificationProvider simula un proveedor IDV para desarrollo local: al recibir un envío
+// responde con un webhook firmado al CallbackURL con el veredicto configurado
+type FakeVerificationProvider struct {
+	Secret      string
+	CallbackURL string
+	Decision    models.IDVDecision
+	Confidence  float64
+	ReasonCodes []string
+	Delay       time.Duration
+
+	client *http.Client
+}
+
+func NewFakeVerificationProvider(secret, callbackURL string, decision models.IDVDecision, confidence float64) *FakeVerificationProvider {
+	p := &FakeVerificationProvider{
+		Secret:      secret,
+		CallbackURL: callbackURL,
+		Decision:    decision,
+		Confidence:  confidence,
+		Delay:       2 * time.Second,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+	if decision == models.IDVDecisionRejected {
+		p.ReasonCodes = []string{"mismatch"}
+	}
+	return p
+}
+
+func (p *FakeVerificationProvider) Name() string {
+	return "fake"
+}
+
+// Submit acepta cualquier envío y agenda el webhook con el veredicto configurado
+func (p *FakeVerificationProvider) Submit(submission models.IDVSubmission) (string, error) {
+	reference := "fake-" + submission.VerificationID.String()
+
+	if p.CallbackURL != "" {
+		go func() {
+			time.Sleep(p.Delay)
+			if err := p.deliver(reference); err != nil {
+				fmt.Printf("Proveedor IDV simulado: error enviando webhook %s: %v\n", reference, err)
+			}
+		}()
+	}
+
+	return reference, nil
+}
+
+func (p *FakeVerificationProvider) ParseWebhook(header http.Header, body []byte) (*models.IDVVerdict, error) {
+	return parseSignedVerdict(p.Secret, header, body)
+}
+
+// deliver envía el webhook firmado al CallbackURL
+func (p *FakeVerificationProvider) deliver(reference string) error {
+	body, err := json.Marshal(webhookVerdictPayload{
+		Reference:   reference,
+		Decision:    p.Decision,
+		Confidence:  p.Confidence,
+		ReasonCodes: p.ReasonCodes,
+	})
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequest(http.MethodPost, p.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, signWebhookPayload(p.Secret, timestamp, body))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("respuesta %d del webhook", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"tradeoptix-back/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// capturedWebhook webhook recibido por el servidor de prueba
+type capturedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+// receiveFakeWebhook envía un documento al proveedor simulado y devuelve el webhook que entrega
+func receiveFakeWebhook(t *testing.T, provider *FakeVerificationProvider) (string, capturedWebhook) {
+	t.Helper()
+
+	received := make(chan capturedWebhook, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- capturedWebhook{header: r.Header.Clone(), body: body}
+	}))
+	defer server.Close()
+
+	provider.CallbackURL = server.URL
+	provider.Delay = 0
+
+	reference, err := provider.Submit(models.IDVSubmission{VerificationID: uuid.New(), UserID: uuid.New()})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	select {
+	case webhook := <-received:
+		return reference, webhook
+	case <-time.After(5 * time.Second):
+		t.Fatal("el proveedor simulado no envió el webhook")
+		return "", capturedWebhook{}
+	}
+}
+
+func TestFakeProviderWebhookValid(t *testing.T) {
+	provider := NewFakeVerificationProvider("secreto", "", models.IDVDecisionRejected, 0.97)
+	reference, webhook := receiveFakeWebhook(t, provider)
+
+	verdict, err := provider.ParseWebhook(webhook.header, webhook.body)
+	if err != nil {
+		t.Fatalf("ParseWebhook: %v", err)
+	}
+	if verdict.ExternalID != reference {
+		t.Errorf("ExternalID = %q, se esperaba %q", verdict.ExternalID, reference)
+	}
+	if verdict.Decision != models.IDVDecisionRejected || verdict.Confidence != 0.97 {
+		t.Errorf("veredicto = %s %.2f", verdict.Decision, verdict.Confidence)
+	}
+	if len(verdict.ReasonCodes) != 1 || verdict.ReasonCodes[0] != "mismatch" {
+		t.Errorf("ReasonCodes = %v, se esperaba [mismatch]", verdict.ReasonCodes)
+	}
+	if string(verdict.Payload) != string(webhook.body) {
+		t.Error("el payload guardado no es el cuerpo original")
+	}
+}
+
+func TestFakeProviderWebhookTampered(t *testing.T) {
+	provider := NewFakeVerificationProvider("secreto", "", models.IDVDecisionRejected, 0.97)
+	_, webhook := receiveFakeWebhook(t, provider)
+
+	// Cuerpo modificado: el rechazo se convierte en aprobación
+	tampered := []byte(`{"reference":"x","decision":"approved","confidence":0.99}`)
+	if _, err := provider.ParseWebhook(webhook.header, tampered); err == nil {
+		t.Error("se aceptó un cuerpo modificado")
+	}
+
+	// Firma de otro secreto
+	header := webhook.header.Clone()
+	timestamp, _ := strconv.ParseInt(header.Get(WebhookTimestampHeader), 10, 64)
+	header.Set(WebhookSignatureHeader, signWebhookPayload("otro-secreto", timestamp, webhook.body))
+	if _, err := provider.ParseWebhook(header, webhook.body); err == nil {
+		t.Error("se aceptó una firma con otro secreto")
+	}
+
+	// Timestamp cambiado sin volver a firmar
+	header = webhook.header.Clone()
+	header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp+1, 10))
+	if _, err := provider.ParseWebhook(header, webhook.body); err == nil {
+		t.Error("se aceptó un timestamp modificado")
+	}
+
+	// Sin encabezados de firma
+	if _, err := provider.ParseWebhook(http.Header{}, webhook.body); err == nil {
+		t.Error("se aceptó un webhook sin firma")
+	}
+}
+
+func TestFakeProviderWebhookReplayed(t *testing.T) {
+	provider := NewFakeVerificationProvider("secreto", "", models.IDVDecisionApproved, 0.99)
+	_, webhook := receiveFakeWebhook(t, provider)
+
+	// Un webhook capturado y reenviado fuera de la ventana de tolerancia, aunque esté bien firmado
+	for _, age := range []time.Duration{webhookTolerance + time.Minute, -(webhookTolerance + time.Minute)} {
+		timestamp := time.Now().Add(-age).Unix()
+		header := webhook.header.Clone()
+		header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+		header.Set(WebhookSignatureHeader, signWebhookPayload("secreto", timestamp, webhook.body))
+		if _, err := provider.ParseWebhook(header, webhook.body); err == nil {
+			t.Errorf("se aceptó un webhook con %v de antigüedad", age)
+		}
+	}
+}
+
+func TestParseSignedVerdictPayload(t *testing.T) {
+	sign := func(body string) http.Header {
+		timestamp := time.Now().Unix()
+		header := http.Header{}
+		header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+		header.Set(WebhookSignatureHeader, signWebhookPayload("secreto", timestamp, []byte(body)))
+		return header
+	}
+
+	invalid := []string{
+		`{"reference":"r1","decision":"maybe","confidence":0.5}`,
+		`{"reference":"","decision":"approved","confidence":0.5}`,
+		`{"reference":"r1","decision":"approved","confidence":1.5}`,
+		`no es json`,
+	}
+	for _, body := range invalid {
+		if _, err := parseSignedVerdict("secreto", sign(body), []byte(body)); err == nil {
+			t.Errorf("se aceptó el payload inválido %s", body)
+		}
+	}
+
+	if _, err := parseSignedVerdict("", sign(`{}`), []byte(`{}`)); err == nil {
+		t.Error("se aceptó un webhook sin secreto configurado")
+	}
+}
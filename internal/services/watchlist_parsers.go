@@ -0,0 +1,263 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"tradeoptix-back/internal/models"
+)
+
+// Formatos de lista soportados
+const (
+	WatchlistFormatOFAC = "ofac_sdn"
+	WatchlistFormatUN   = "un_consolidated"
+	WatchlistFormatCSV  = "csv"
+)
+
+var (
+	// ofacAliasPattern extrae los alias de la columna Remarks (a.k.a. 'NOMBRE')
+	ofacAliasPattern = regexp.MustCompile(`(?i)[af]\.k\.a\.\s*'([^']+)'`)
+	// ofacDocumentPattern extrae números de documento de identidad de la columna Remarks
+	ofacDocumentPattern = regexp.MustCompile(`(?i)(?:passport|cedula no\.|national id no\.|identification number|d\.n\.i\.|c\.i\.)\s*#?\s*([A-Z0-9][A-Z0-9.\-]{3,})`)
+)
+
+// parseWatchlist convierte el archivo de una lista en entradas según su formato
+func parseWatchlist(format string, r io.Reader) ([]models.WatchlistEntry, error) {
+	switch format {
+	case WatchlistFormatOFAC:
+		return parseOFACSDN(r)
+	case WatchlistFormatUN:
+		return parseUNConsolidated(r)
+	case WatchlistFormatCSV:
+		return parseGenericCSV(r)
+	default:
+		return nil, fmt.Errorf("formato de lista no soportado: %s", format)
+	}
+}
+
+// parseOFACSDN lee el archivo sdn.csv de OFAC (sin encabezado, "-0-" indica vacío)
+func parseOFACSDN(r io.Reader) ([]models.WatchlistEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var entries []models.WatchlistEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo CSV de OFAC: %v", err)
+		}
+		if len(record) < 4 {
+			continue
+		}
+
+		field := func(i int) string {
+			if i >= len(record) {
+				return ""
+			}
+			value := strings.TrimSpace(record[i])
+			if value == "-0-" {
+				return ""
+			}
+			return value
+		}
+
+		entryType := "entity"
+		switch strings.ToLower(field(2)) {
+		case "individual":
+			entryType = "individual"
+		case "vessel", "aircraft":
+			continue
+		}
+
+		entry := models.WatchlistEntry{
+			ExternalID: field(0),
+			EntryType:  entryType,
+			Name:       ofacDisplayName(field(1)),
+			Program:    field(3),
+		}
+
+		remarks := field(11)
+		for _, match := range ofacAliasPattern.FindAllStringSubmatch(remarks, -1) {
+			entry.Aliases = append(entry.Aliases, ofacDisplayName(match[1]))
+		}
+		for _, match := range ofacDocumentPattern.FindAllStringSubmatch(remarks, -1) {
+			entry.DocumentNumbers = append(entry.DocumentNumbers, NormalizeDocumentNumber(match[1]))
+		}
+
+		if entry.Name != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// ofacDisplayName convierte "APELLIDO, Nombre" en "Nombre APELLIDO"
+func ofacDisplayName(name string) string {
+	parts := strings.SplitN(name, ",", 2)
+	if len(parts) == 2 {
+		return strings.TrimSpace(parts[1]) + " " + strings.TrimSpace(parts[0])
+	}
+	return strings.TrimSpace(name)
+}
+
+// unConsolidatedList estructura del XML de la lista consolidada del Consejo de Seguridad de la ONU
+type unConsolidatedList struct {
+	Individuals []struct {
+		DataID      string `xml:"DATAID"`
+		FirstName   string `xml:"FIRST_NAME"`
+		SecondName  string `xml:"SECOND_NAME"`
+		ThirdName   string `xml:"THIRD_NAME"`
+		FourthName  string `xml:"FOURTH_NAME"`
+		ListType    string `xml:"UN_LIST_TYPE"`
+		Nationality []struct {
+			Value string `xml:"VALUE"`
+		} `xml:"NATIONALITY"`
+		Aliases []struct {
+			Name string `xml:"ALIAS_NAME"`
+		} `xml:"INDIVIDUAL_ALIAS"`
+		Documents []struct {
+			Type   string `xml:"TYPE_OF_DOCUMENT"`
+			Number string `xml:"NUMBER"`
+		} `xml:"INDIVIDUAL_DOCUMENT"`
+	} `xml:"INDIVIDUALS>INDIVIDUAL"`
+	Entities []struct {
+		DataID   string `xml:"DATAID"`
+		Name     string `xml:"FIRST_NAME"`
+		ListType string `xml:"UN_LIST_TYPE"`
+		Aliases  []struct {
+			Name string `xml:"ALIAS_NAME"`
+		} `xml:"ENTITY_ALIAS"`
+	} `xml:"ENTITIES>ENTITY"`
+}
+
+// parseUNConsolidated lee el XML de la lista consolidada de la ONU
+func parseUNConsolidated(r io.Reader) ([]models.WatchlistEntry, error) {
+	var list unConsolidatedList
+	if err := xml.NewDecoder(r).Decode(&list); err != nil {
+		return nil, fmt.Errorf("error leyendo XML de la ONU: %v", err)
+	}
+
+	var entries []models.WatchlistEntry
+	for _, ind := range list.Individuals {
+		entry := models.WatchlistEntry{
+			ExternalID: ind.DataID,
+			EntryType:  "individual",
+			Name:       strings.Join(strings.Fields(strings.Join([]string{ind.FirstName, ind.SecondName, ind.ThirdName, ind.FourthName}, " ")), " "),
+			Program:    ind.ListType,
+		}
+		if len(ind.Nationality) > 0 {
+			entry.Country = strings.TrimSpace(ind.Nationality[0].Value)
+		}
+		for _, alias := range ind.Aliases {
+			if name := strings.TrimSpace(alias.Name); name != "" {
+				entry.Aliases = append(entry.Aliases, name)
+			}
+		}
+		for _, doc := range ind.Documents {
+			if number := NormalizeDocumentNumber(doc.Number); number != "" {
+				entry.DocumentNumbers = append(entry.DocumentNumbers, number)
+			}
+		}
+		if entry.Name != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	for _, ent := range list.Entities {
+		entry := models.WatchlistEntry{
+			ExternalID: ent.DataID,
+			EntryType:  "entity",
+			Name:       strings.TrimSpace(ent.Name),
+			Program:    ent.ListType,
+		}
+		for _, alias := range ent.Aliases {
+			if name := strings.TrimSpace(alias.Name); name != "" {
+				entry.Aliases = append(entry.Aliases, name)
+			}
+		}
+		if entry.Name != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// parseGenericCSV lee un CSV con encabezado: name, aliases, document_numbers, country, entry_type, external_id, program.
+// Los alias y documentos se separan con ";". Pensado para listas PEP internas.
+func parseGenericCSV(r io.Reader) ([]models.WatchlistEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo encabezado del CSV: %v", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("el CSV debe tener una columna 'name'")
+	}
+
+	var entries []models.WatchlistEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo CSV: %v", err)
+		}
+
+		field := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		list := func(column string) []string {
+			var values []string
+			for _, value := range strings.Split(field(column), ";") {
+				if value = strings.TrimSpace(value); value != "" {
+					values = append(values, value)
+				}
+			}
+			return values
+		}
+
+		entry := models.WatchlistEntry{
+			ExternalID: field("external_id"),
+			EntryType:  field("entry_type"),
+			Name:       field("name"),
+			Aliases:    list("aliases"),
+			Country:    field("country"),
+			Program:    field("program"),
+		}
+		if entry.EntryType == "" {
+			entry.EntryType = "individual"
+		}
+		for _, number := range list("document_numbers") {
+			entry.DocumentNumbers = append(entry.DocumentNumbers, NormalizeDocumentNumber(number))
+		}
+
+		if entry.Name != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"tradeoptix-back/internal/models"
+)
+
+func TestNormalizeDocumentNumber(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"1.234.567-8", "12345678"},
+		{" v-12 345 678 ", "V12345678"},
+		{"AB/123#45", "AB12345"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeDocumentNumber(tt.in); got != tt.want {
+			t.Errorf("NormalizeDocumentNumber(%q) = %q, se esperaba %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseOFACSDN(t *testing.T) {
+	data := strings.Join([]string{
+		`36,"AEROCARIBBEAN AIRLINES",-0- ,"CUBA",-0- ,-0- ,-0- ,-0- ,-0- ,-0- ,-0- ,"a.k.a. 'AERO-CARIBBEAN'."`,
+		`2674,"ABDELNUR, Nury de Jesus","individual","SDNT",-0- ,-0- ,-0- ,-0- ,-0- ,-0- ,-0- ,"DOB 10 Jan 1950; Cedula No. 7.552.829 (Colombia); a.k.a. 'NURY ABDELNUR'."`,
+		`15036,"ATLANTIC STAR","vessel","IRAN",-0- ,-0- ,-0- ,-0- ,-0- ,-0- ,-0- ,-0- `,
+		`99,"",individual`,
+	}, "\n")
+
+	entries, err := parseWatchlist(WatchlistFormatOFAC, strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("parseWatchlist: %v", err)
+	}
+
+	want := []models.WatchlistEntry{
+		{ExternalID: "36", EntryType: "entity", Name: "AEROCARIBBEAN AIRLINES", Program: "CUBA", Aliases: []string{"AERO-CARIBBEAN"}},
+		{
+			ExternalID: "2674", EntryType: "individual", Name: "Nury de Jesus ABDELNUR", Program: "SDNT",
+			Aliases: []string{"NURY ABDELNUR"}, DocumentNumbers: []string{"7552829"},
+		},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entradas OFAC:\n got %+v\nwant %+v", entries, want)
+	}
+}
+
+func TestParseUNConsolidated(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<CONSOLIDATED_LIST>
+  <INDIVIDUALS>
+    <INDIVIDUAL>
+      <DATAID>6908555</DATAID>
+      <FIRST_NAME>ABDUL</FIRST_NAME>
+      <SECOND_NAME>GHANI</SECOND_NAME>
+      <THIRD_NAME> </THIRD_NAME>
+      <UN_LIST_TYPE>Al-Qaida</UN_LIST_TYPE>
+      <NATIONALITY><VALUE> Afghanistan </VALUE></NATIONALITY>
+      <INDIVIDUAL_ALIAS><ALIAS_NAME>Mullah Baradar</ALIAS_NAME></INDIVIDUAL_ALIAS>
+      <INDIVIDUAL_ALIAS><ALIAS_NAME></ALIAS_NAME></INDIVIDUAL_ALIAS>
+      <INDIVIDUAL_DOCUMENT><TYPE_OF_DOCUMENT>Passport</TYPE_OF_DOCUMENT><NUMBER>OA-296 623</NUMBER></INDIVIDUAL_DOCUMENT>
+    </INDIVIDUAL>
+  </INDIVIDUALS>
+  <ENTITIES>
+    <ENTITY>
+      <DATAID>110</DATAID>
+      <FIRST_NAME>AL-HARAMAIN FOUNDATION</FIRST_NAME>
+      <UN_LIST_TYPE>Al-Qaida</UN_LIST_TYPE>
+      <ENTITY_ALIAS><ALIAS_NAME>Al Haramain</ALIAS_NAME></ENTITY_ALIAS>
+    </ENTITY>
+  </ENTITIES>
+</CONSOLIDATED_LIST>`
+
+	entries, err := parseWatchlist(WatchlistFormatUN, strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("parseWatchlist: %v", err)
+	}
+
+	want := []models.WatchlistEntry{
+		{
+			ExternalID: "6908555", EntryType: "individual", Name: "ABDUL GHANI", Program: "Al-Qaida", Country: "Afghanistan",
+			Aliases: []string{"Mullah Baradar"}, DocumentNumbers: []string{"OA296623"},
+		},
+		{ExternalID: "110", EntryType: "entity", Name: "AL-HARAMAIN FOUNDATION", Program: "Al-Qaida", Aliases: []string{"Al Haramain"}},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entradas ONU:\n got %+v\nwant %+v", entries, want)
+	}
+
+	if _, err := parseWatchlist(WatchlistFormatUN, strings.NewReader("<CONSOLIDATED_LIST>")); err == nil {
+		t.Error("se esperaba error con un XML incompleto")
+	}
+}
+
+func TestParseGenericCSV(t *testing.T) {
+	data := "Name, Aliases, Document_Numbers, Country, Entry_Type\n" +
+		"Juan Pérez, Juancho; J. Pérez, 1.234.567; V-890, VE,\n" +
+		"Empresa X, , , , entity\n" +
+		", sin nombre, , ,\n"
+
+	entries, err := parseWatchlist(WatchlistFormatCSV, strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("parseWatchlist: %v", err)
+	}
+
+	want := []models.WatchlistEntry{
+		{
+			EntryType: "individual", Name: "Juan Pérez", Country: "VE",
+			Aliases: []string{"Juancho", "J. Pérez"}, DocumentNumbers: []string{"1234567", "V890"},
+		},
+		{EntryType: "entity", Name: "Empresa X"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entradas CSV:\n got %+v\nwant %+v", entries, want)
+	}
+
+	if _, err := parseWatchlist(WatchlistFormatCSV, strings.NewReader("alias,country\nx,VE\n")); err == nil {
+		t.Error("se esperaba error sin columna 'name'")
+	}
+}
+
+func TestParseWatchlistUnknownFormat(t *testing.T) {
+	if _, err := parseWatchlist("xlsx", strings.NewReader("")); err == nil {
+		t.Error("se esperaba error con un formato no soportado")
+	}
+}
@@ -0,0 +1,12 @@
+-- Rollback del catálogo de motivos de rechazo
+DROP TRIGGER IF EXISTS update_kyc_rejection_reasons_updated_at ON kyc_rejection_reasons;
+
+DROP INDEX IF EXISTS idx_kyc_document_rejections_reason;
+DROP INDEX IF EXISTS idx_kyc_document_rejections_document;
+
+ALTER TABLE kyc_documents DROP COLUMN IF EXISTS rejection_note;
+ALTER TABLE kyc_documents DROP COLUMN IF EXISTS rejection_codes;
+
+DROP TABLE IF EXISTS kyc_document_rejections;
+DROP TABLE IF EXISTS kyc_rejection_reason_templates;
+DROP TABLE IF EXISTS kyc_rejection_reasons;
@@ -0,0 +1,60 @@
+-- Catálogo de motivos de rechazo KYC
+CREATE TABLE IF NOT EXISTS kyc_rejection_reasons (
+    code VARCHAR(50) PRIMARY KEY,
+    description TEXT NOT NULL, -- descripción interna para revisores
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Plantillas de mensaje por idioma ({document} se reemplaza por el tipo de documento)
+CREATE TABLE IF NOT EXISTS kyc_rejection_reason_templates (
+    reason_code VARCHAR(50) NOT NULL REFERENCES kyc_rejection_reasons(code) ON DELETE CASCADE,
+    language VARCHAR(10) NOT NULL,
+    message TEXT NOT NULL,
+    PRIMARY KEY (reason_code, language)
+);
+
+-- Motivos aplicados en cada rechazo (un documento puede tener varios)
+CREATE TABLE IF NOT EXISTS kyc_document_rejections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    document_id UUID NOT NULL REFERENCES kyc_documents(id) ON DELETE CASCADE,
+    reason_code VARCHAR(50) NOT NULL REFERENCES kyc_rejection_reasons(code),
+    rejected_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Motivos vigentes y nota interna del revisor (la nota no es visible para el usuario)
+ALTER TABLE kyc_documents ADD COLUMN IF NOT EXISTS rejection_codes TEXT[];
+ALTER TABLE kyc_documents ADD COLUMN IF NOT EXISTS rejection_note TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_kyc_document_rejections_document ON kyc_document_rejections(document_id);
+CREATE INDEX IF NOT EXISTS idx_kyc_document_rejections_reason ON kyc_document_rejections(reason_code);
+
+CREATE TRIGGER update_kyc_rejection_reasons_updated_at BEFORE UPDATE ON kyc_rejection_reasons
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Catálogo inicial
+INSERT INTO kyc_rejection_reasons (code, description) VALUES
+('blurry', 'Imagen borrosa o desenfocada'),
+('expired', 'Documento vencido'),
+('mismatch', 'Los datos no coinciden con el registro'),
+('glare', 'Reflejos o brillo que impiden leer el documento'),
+('cropped', 'Documento recortado o incompleto'),
+('wrong_document', 'Se subió un documento distinto al solicitado')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO kyc_rejection_reason_templates (reason_code, language, message) VALUES
+('blurry', 'es', 'La imagen de {document} está borrosa. Toma una nueva foto con buena iluminación y enfoque.'),
+('blurry', 'en', 'The image of {document} is blurry. Please take a new, well-lit and focused photo.'),
+('expired', 'es', 'El documento que aparece en {document} está vencido. Sube un documento vigente.'),
+('expired', 'en', 'The document shown in {document} has expired. Please upload a valid document.'),
+('mismatch', 'es', 'Los datos de {document} no coinciden con los de tu registro.'),
+('mismatch', 'en', 'The details on {document} do not match your registration.'),
+('glare', 'es', 'La imagen de {document} tiene reflejos que impiden leerla. Evita el flash y la luz directa.'),
+('glare', 'en', 'The image of {document} has glare that makes it unreadable. Avoid flash and direct light.'),
+('cropped', 'es', 'La imagen de {document} está recortada. Asegúrate de que se vea el documento completo.'),
+('cropped', 'en', 'The image of {document} is cropped. Make sure the whole document is visible.'),
+('wrong_document', 'es', 'El archivo subido no corresponde a {document}.'),
+('wrong_document', 'en', 'The uploaded file is not {document}.')
+ON CONFLICT (reason_code, language) DO NOTHING;
@@ -0,0 +1,13 @@
+-- Rollback de detección de identidades duplicadas
+DROP INDEX IF EXISTS idx_identity_duplicate_matches_matched_user;
+DROP INDEX IF EXISTS idx_identity_duplicate_matches_user;
+DROP INDEX IF EXISTS idx_identity_duplicate_matches_status;
+DROP INDEX IF EXISTS idx_identity_duplicate_matches_pair;
+DROP TABLE IF EXISTS identity_duplicate_matches;
+
+DROP INDEX IF EXISTS idx_kyc_documents_hash_bands;
+ALTER TABLE kyc_documents DROP COLUMN IF EXISTS perceptual_hash_bands;
+ALTER TABLE kyc_documents DROP COLUMN IF EXISTS perceptual_hash;
+
+DROP INDEX IF EXISTS idx_users_document_number_normalized;
+ALTER TABLE users DROP COLUMN IF EXISTS document_number_normalized;
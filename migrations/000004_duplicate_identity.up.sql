@@ -0,0 +1,36 @@
+-- Número de documento normalizado (sin guiones, espacios ni puntos) para detectar duplicados
+ALTER TABLE users ADD COLUMN IF NOT EXISTS document_number_normalized VARCHAR(20);
+UPDATE users SET document_number_normalized = UPPER(regexp_replace(document_number, '[^A-Za-z0-9]', '', 'g'))
+WHERE document_number_normalized IS NULL;
+CREATE INDEX IF NOT EXISTS idx_users_document_number_normalized ON users(document_number_normalized);
+
+-- Hash perceptual (dHash de 64 bits) de cada imagen KYC. El hash se divide en bandas
+-- (índice de banda << 16 | valor) para buscar candidatos similares con un índice GIN.
+ALTER TABLE kyc_documents ADD COLUMN IF NOT EXISTS perceptual_hash BIGINT;
+ALTER TABLE kyc_documents ADD COLUMN IF NOT EXISTS perceptual_hash_bands INTEGER[];
+CREATE INDEX IF NOT EXISTS idx_kyc_documents_hash_bands ON kyc_documents USING GIN (perceptual_hash_bands);
+
+-- Posibles cuentas duplicadas para revisión de administradores
+CREATE TABLE IF NOT EXISTS identity_duplicate_matches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    matched_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    match_type VARCHAR(30) NOT NULL, -- document_number, name, cedula_front, cedula_back, face_photo
+    document_id UUID REFERENCES kyc_documents(id) ON DELETE SET NULL,
+    matched_document_id UUID REFERENCES kyc_documents(id) ON DELETE SET NULL,
+    distance INTEGER, -- distancia de Hamming entre hashes (solo para imágenes)
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'dismissed', 'confirmed')),
+    note TEXT,
+    reviewed_by UUID REFERENCES users(id),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Un solo registro por par de usuarios y tipo, sin importar el orden
+CREATE UNIQUE INDEX IF NOT EXISTS idx_identity_duplicate_matches_pair ON identity_duplicate_matches(
+    LEAST(user_id, matched_user_id), GREATEST(user_id, matched_user_id), match_type
+);
+
+CREATE INDEX IF NOT EXISTS idx_identity_duplicate_matches_status ON identity_duplicate_matches(status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_identity_duplicate_matches_user ON identity_duplicate_matches(user_id);
+CREATE INDEX IF NOT EXISTS idx_identity_duplicate_matches_matched_user ON identity_duplicate_matches(matched_user_id);
@@ -0,0 +1,8 @@
+-- Rollback de screening de sanciones y PEP
+DROP INDEX IF EXISTS idx_screening_hits_status;
+DROP INDEX IF EXISTS idx_screening_hits_user_status;
+DROP INDEX IF EXISTS idx_watchlist_entries_watchlist;
+
+DROP TABLE IF EXISTS screening_hits;
+DROP TABLE IF EXISTS watchlist_entries;
+DROP TABLE IF EXISTS watchlists;
@@ -0,0 +1,45 @@
+-- Listas de sanciones y PEP cargadas localmente
+CREATE TABLE IF NOT EXISTS watchlists (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    source_format VARCHAR(30) NOT NULL CHECK (source_format IN ('ofac_sdn', 'un_consolidated', 'csv')),
+    list_type VARCHAR(20) NOT NULL DEFAULT 'sanctions' CHECK (list_type IN ('sanctions', 'pep')),
+    entry_count INTEGER NOT NULL DEFAULT 0,
+    imported_by UUID REFERENCES users(id),
+    imported_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS watchlist_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    watchlist_id UUID NOT NULL REFERENCES watchlists(id) ON DELETE CASCADE,
+    external_id VARCHAR(100),
+    entry_type VARCHAR(20) NOT NULL DEFAULT 'individual', -- individual, entity
+    name TEXT NOT NULL,
+    aliases TEXT[],
+    document_numbers TEXT[], -- normalizados
+    country VARCHAR(100),
+    program VARCHAR(200)
+);
+
+-- Coincidencias de screening pendientes de adjudicación
+CREATE TABLE IF NOT EXISTS screening_hits (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    watchlist_id UUID NOT NULL REFERENCES watchlists(id) ON DELETE CASCADE,
+    entry_external_id VARCHAR(100),
+    matched_name TEXT NOT NULL,
+    match_field VARCHAR(20) NOT NULL, -- name, alias, document
+    score NUMERIC(5, 4) NOT NULL,
+    -- superseded: la entrada ya no figura en la lista reimportada
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'cleared', 'confirmed', 'superseded')),
+    note TEXT,
+    reviewed_by UUID REFERENCES users(id),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE(user_id, watchlist_id, matched_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_entries_watchlist ON watchlist_entries(watchlist_id);
+CREATE INDEX IF NOT EXISTS idx_screening_hits_user_status ON screening_hits(user_id, status);
+CREATE INDEX IF NOT EXISTS idx_screening_hits_status ON screening_hits(status, created_at DESC);
@@ -0,0 +1,11 @@
+-- Rollback del motor de riesgo
+DROP TRIGGER IF EXISTS update_risk_factor_weights_updated_at ON risk_factor_weights;
+
+DROP INDEX IF EXISTS idx_user_risk_scores_user;
+DROP TABLE IF EXISTS user_risk_scores;
+DROP TABLE IF EXISTS risk_factor_weights;
+
+DROP INDEX IF EXISTS idx_users_risk_score;
+ALTER TABLE users DROP COLUMN IF EXISTS failed_login_attempts;
+ALTER TABLE users DROP COLUMN IF EXISTS risk_level;
+ALTER TABLE users DROP COLUMN IF EXISTS risk_score;
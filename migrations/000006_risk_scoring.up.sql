@@ -0,0 +1,40 @@
+-- Puntaje de riesgo vigente del usuario
+ALTER TABLE users ADD COLUMN IF NOT EXISTS risk_score INTEGER;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS risk_level VARCHAR(10) CHECK (risk_level IN ('low', 'medium', 'high'));
+ALTER TABLE users ADD COLUMN IF NOT EXISTS failed_login_attempts INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_users_risk_score ON users(risk_score DESC NULLS LAST);
+
+-- Pesos configurables de cada factor de riesgo
+CREATE TABLE IF NOT EXISTS risk_factor_weights (
+    factor VARCHAR(50) PRIMARY KEY,
+    weight INTEGER NOT NULL CHECK (weight >= 0),
+    description TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Historial de cálculos de riesgo
+CREATE TABLE IF NOT EXISTS user_risk_scores (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    score INTEGER NOT NULL,
+    level VARCHAR(10) NOT NULL,
+    factors JSONB NOT NULL,
+    trigger VARCHAR(50) NOT NULL, -- evento que originó el cálculo
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_risk_scores_user ON user_risk_scores(user_id, created_at DESC);
+
+CREATE TRIGGER update_risk_factor_weights_updated_at BEFORE UPDATE ON risk_factor_weights
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Pesos iniciales (puntos por ocurrencia; el puntaje total se limita a 100)
+INSERT INTO risk_factor_weights (factor, weight, description) VALUES
+('passport_document', 10, 'Registro con pasaporte en lugar de cédula'),
+('high_risk_country', 30, 'País de residencia en la lista de alto riesgo'),
+('disposable_email', 20, 'Email de un dominio temporal o desechable'),
+('duplicate_match', 25, 'Por cada posible cuenta duplicada abierta o confirmada'),
+('screening_hit', 40, 'Por cada coincidencia de sanciones/PEP pendiente o confirmada'),
+('failed_login', 3, 'Por cada intento de inicio de sesión fallido (máximo 10)'),
+('kyc_rejection', 10, 'Por cada rechazo de documento KYC')
+ON CONFLICT (factor) DO NOTHING;
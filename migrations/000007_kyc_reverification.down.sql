@@ -0,0 +1,18 @@
+-- Rollback de vencimiento y re-verificación KYC
+DROP TABLE IF EXISTS kyc_expiry_reminders;
+
+ALTER TABLE users DROP COLUMN IF EXISTS kyc_verified_at;
+
+DROP INDEX IF EXISTS idx_kyc_documents_expires_at;
+ALTER TABLE kyc_documents DROP COLUMN IF EXISTS expires_at;
+
+UPDATE kyc_documents SET status = 'pending' WHERE status = 'expired';
+ALTER TABLE kyc_documents DROP CONSTRAINT IF EXISTS kyc_documents_status_check;
+ALTER TABLE kyc_documents ADD CONSTRAINT kyc_documents_status_check
+    CHECK (status IN ('pending', 'approved', 'rejected'));
+
+UPDATE users SET kyc_status = 'pending' WHERE kyc_status = 'reverification_required';
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_kyc_status_check;
+ALTER TABLE users ADD CONSTRAINT users_kyc_status_check
+    CHECK (kyc_status IN ('pending', 'approved', 'rejected'));
+ALTER TABLE users ALTER COLUMN kyc_status TYPE VARCHAR(20);
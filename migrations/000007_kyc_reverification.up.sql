@@ -0,0 +1,28 @@
+-- Nuevo estado de usuario que exige volver a verificar la identidad
+-- ('reverification_required' no cabe en VARCHAR(20))
+ALTER TABLE users ALTER COLUMN kyc_status TYPE VARCHAR(30);
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_kyc_status_check;
+ALTER TABLE users ADD CONSTRAINT users_kyc_status_check
+    CHECK (kyc_status IN ('pending', 'approved', 'rejected', 'reverification_required'));
+
+-- Los documentos vencidos o que deben renovarse quedan en estado 'expired'
+ALTER TABLE kyc_documents DROP CONSTRAINT IF EXISTS kyc_documents_status_check;
+ALTER TABLE kyc_documents ADD CONSTRAINT kyc_documents_status_check
+    CHECK (status IN ('pending', 'approved', 'rejected', 'expired'));
+
+-- Fecha de vencimiento capturada por el revisor
+ALTER TABLE kyc_documents ADD COLUMN IF NOT EXISTS expires_at DATE;
+CREATE INDEX IF NOT EXISTS idx_kyc_documents_expires_at ON kyc_documents(expires_at) WHERE expires_at IS NOT NULL;
+
+-- Fecha de la última verificación aprobada (para la re-verificación periódica)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS kyc_verified_at TIMESTAMP WITH TIME ZONE;
+UPDATE users SET kyc_verified_at = updated_at WHERE kyc_status = 'approved' AND kyc_verified_at IS NULL;
+
+-- Recordatorios de vencimiento ya enviados (evita duplicados)
+CREATE TABLE IF NOT EXISTS kyc_expiry_reminders (
+    document_id UUID NOT NULL REFERENCES kyc_documents(id) ON DELETE CASCADE,
+    expires_at DATE NOT NULL,
+    offset_days INTEGER NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (document_id, expires_at, offset_days)
+);
@@ -0,0 +1,11 @@
+-- Rollback de retención de archivos KYC
+DROP TABLE IF EXISTS kyc_purge_tombstones;
+
+DROP INDEX IF EXISTS idx_kyc_documents_purged_at;
+ALTER TABLE kyc_documents DROP COLUMN IF EXISTS purged_at;
+
+ALTER TABLE users DROP COLUMN IF EXISTS legal_hold_at;
+ALTER TABLE users DROP COLUMN IF EXISTS legal_hold_by;
+ALTER TABLE users DROP COLUMN IF EXISTS legal_hold_reason;
+ALTER TABLE users DROP COLUMN IF EXISTS legal_hold;
+ALTER TABLE users DROP COLUMN IF EXISTS closed_at;
@@ -0,0 +1,28 @@
+-- Cierre de cuenta y retención legal (legal hold) por usuario
+ALTER TABLE users ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS legal_hold BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS legal_hold_reason TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS legal_hold_by UUID REFERENCES users(id);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS legal_hold_at TIMESTAMP WITH TIME ZONE;
+
+-- Documentos cuyo archivo fue eliminado por la política de retención
+ALTER TABLE kyc_documents ADD COLUMN IF NOT EXISTS purged_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_kyc_documents_purged_at ON kyc_documents(purged_at) WHERE purged_at IS NOT NULL;
+
+-- Lápidas: constancia de cada archivo purgado (sin datos personales ni FK, sobrevive al borrado del usuario)
+CREATE TABLE IF NOT EXISTS kyc_purge_tombstones (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    document_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    document_type VARCHAR(20) NOT NULL,
+    document_status VARCHAR(20) NOT NULL,
+    policy VARCHAR(20) NOT NULL CHECK (policy IN ('rejected', 'abandoned', 'closed')),
+    file_size BIGINT NOT NULL,
+    file_sha256 VARCHAR(64),
+    uploaded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_activity_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    purged_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_purge_tombstones_user_id ON kyc_purge_tombstones(user_id);
+CREATE INDEX IF NOT EXISTS idx_kyc_purge_tombstones_purged_at ON kyc_purge_tombstones(purged_at);
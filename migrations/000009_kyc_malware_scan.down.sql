@@ -0,0 +1,8 @@
+-- Rollback del análisis antivirus de documentos KYC
+ALTER TABLE kyc_documents DROP COLUMN IF EXISTS scanned_at;
+ALTER TABLE kyc_documents DROP COLUMN IF EXISTS scan_signature;
+
+UPDATE kyc_documents SET status = 'rejected' WHERE status = 'quarantined';
+ALTER TABLE kyc_documents DROP CONSTRAINT IF EXISTS kyc_documents_status_check;
+ALTER TABLE kyc_documents ADD CONSTRAINT kyc_documents_status_check
+    CHECK (status IN ('pending', 'approved', 'rejected', 'expired'));
@@ -0,0 +1,8 @@
+-- Documentos aislados por el antivirus quedan en estado 'quarantined'
+ALTER TABLE kyc_documents DROP CONSTRAINT IF EXISTS kyc_documents_status_check;
+ALTER TABLE kyc_documents ADD CONSTRAINT kyc_documents_status_check
+    CHECK (status IN ('pending', 'approved', 'rejected', 'expired', 'quarantined'));
+
+-- Resultado del análisis antivirus
+ALTER TABLE kyc_documents ADD COLUMN IF NOT EXISTS scan_signature TEXT;
+ALTER TABLE kyc_documents ADD COLUMN IF NOT EXISTS scanned_at TIMESTAMP WITH TIME ZONE;
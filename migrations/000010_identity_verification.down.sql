@@ -0,0 +1,2 @@
+-- Rollback de verificación de identidad externa
+DROP TABLE IF EXISTS idv_verifications;
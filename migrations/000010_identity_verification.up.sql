@@ -0,0 +1,21 @@
+-- Envíos a proveedores externos de verificación de identidad (IDV) y sus veredictos
+CREATE TABLE IF NOT EXISTS idv_verifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    external_id VARCHAR(200),
+    document_ids UUID[] NOT NULL DEFAULT '{}', -- documentos enviados; el veredicto solo se aplica a estos
+    status VARCHAR(20) NOT NULL DEFAULT 'submitted' CHECK (status IN ('submitted', 'completed', 'failed')),
+    decision VARCHAR(20) CHECK (decision IN ('approved', 'rejected', 'review')),
+    confidence NUMERIC(5, 4),
+    reason_codes TEXT[],
+    outcome VARCHAR(20) CHECK (outcome IN ('auto_approved', 'auto_rejected', 'manual_review')),
+    error TEXT,
+    payload JSONB,
+    submitted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (provider, external_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idv_verifications_user_id ON idv_verifications(user_id);
+CREATE INDEX IF NOT EXISTS idx_idv_verifications_status ON idv_verifications(status);
@@ -0,0 +1,9 @@
+-- Rollback de niveles KYC
+ALTER TABLE users DROP COLUMN IF EXISTS kyc_level;
+
+DROP TABLE IF EXISTS kyc_tiers;
+
+DELETE FROM kyc_documents WHERE document_type = 'proof_of_address';
+ALTER TABLE kyc_documents DROP CONSTRAINT IF EXISTS kyc_documents_document_type_check;
+ALTER TABLE kyc_documents ADD CONSTRAINT kyc_documents_document_type_check
+    CHECK (document_type IN ('cedula_front', 'cedula_back', 'face_photo'));
@@ -0,0 +1,38 @@
+-- Comprobante de domicilio como nuevo tipo de documento
+ALTER TABLE kyc_documents DROP CONSTRAINT IF EXISTS kyc_documents_document_type_check;
+ALTER TABLE kyc_documents ADD CONSTRAINT kyc_documents_document_type_check
+    CHECK (document_type IN ('cedula_front', 'cedula_back', 'face_photo', 'proof_of_address'));
+
+-- Niveles KYC: cada nivel exige todos sus documentos aprobados (y los de los niveles anteriores)
+CREATE TABLE IF NOT EXISTS kyc_tiers (
+    level INTEGER PRIMARY KEY CHECK (level >= 0),
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    required_document_types TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_kyc_tiers_updated_at BEFORE UPDATE ON kyc_tiers
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+INSERT INTO kyc_tiers (level, name, description, required_document_types) VALUES
+    (0, 'Básico', 'Cuenta registrada con email', '{}'),
+    (1, 'Identidad', 'Documento de identidad verificado', '{cedula_front,cedula_back}'),
+    (2, 'Completo', 'Identidad, comprobante de domicilio y selfie verificados', '{cedula_front,cedula_back,proof_of_address,face_photo}')
+ON CONFLICT (level) DO NOTHING;
+
+-- Nivel KYC alcanzado por cada usuario
+ALTER TABLE users ADD COLUMN IF NOT EXISTS kyc_level INTEGER NOT NULL DEFAULT 0;
+
+UPDATE users u SET kyc_level = COALESCE((
+    SELECT MAX(t.level) FROM kyc_tiers t
+    WHERE NOT EXISTS (
+        SELECT 1 FROM kyc_tiers prev, unnest(prev.required_document_types) AS required(document_type)
+        WHERE prev.level <= t.level
+          AND NOT EXISTS (
+              SELECT 1 FROM kyc_documents d
+              WHERE d.user_id = u.id AND d.document_type = required.document_type AND d.status = 'approved'
+          )
+    )
+), 0);
@@ -0,0 +1,3 @@
+-- Rollback de cargas reanudables KYC
+DROP TRIGGER IF EXISTS update_kyc_upload_sessions_updated_at ON kyc_upload_sessions;
+DROP TABLE IF EXISTS kyc_upload_sessions;
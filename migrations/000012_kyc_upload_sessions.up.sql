@@ -0,0 +1,22 @@
+-- Sesiones de carga reanudable de documentos KYC (protocolo tus)
+CREATE TABLE IF NOT EXISTS kyc_upload_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    document_type VARCHAR(20) NOT NULL,
+    original_name VARCHAR(200) NOT NULL, -- igual que kyc_documents.original_name
+    mime_type VARCHAR(100) NOT NULL,
+    upload_length BIGINT NOT NULL CHECK (upload_length > 0),
+    upload_offset BIGINT NOT NULL DEFAULT 0 CHECK (upload_offset >= 0 AND upload_offset <= upload_length),
+    file_path TEXT NOT NULL,
+    document_id UUID REFERENCES kyc_documents(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_upload_sessions_user_id ON kyc_upload_sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_kyc_upload_sessions_expires_at ON kyc_upload_sessions(expires_at);
+
+CREATE TRIGGER update_kyc_upload_sessions_updated_at BEFORE UPDATE ON kyc_upload_sessions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
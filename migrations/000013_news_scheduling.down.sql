@@ -0,0 +1,4 @@
+-- Rollback de publicación programada de noticias
+DROP INDEX IF EXISTS idx_market_news_expire_at;
+ALTER TABLE market_news DROP CONSTRAINT IF EXISTS market_news_publication_window_check;
+ALTER TABLE market_news DROP COLUMN IF EXISTS expire_at;
@@ -0,0 +1,8 @@
+-- Publicación programada y expiración de noticias: published_at puede estar en el futuro
+ALTER TABLE market_news ADD COLUMN IF NOT EXISTS expire_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE market_news DROP CONSTRAINT IF EXISTS market_news_publication_window_check;
+ALTER TABLE market_news ADD CONSTRAINT market_news_publication_window_check
+    CHECK (expire_at IS NULL OR expire_at > published_at);
+
+CREATE INDEX IF NOT EXISTS idx_market_news_expire_at ON market_news(expire_at) WHERE expire_at IS NOT NULL;
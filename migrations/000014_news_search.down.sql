@@ -0,0 +1,4 @@
+-- Rollback de búsqueda de texto completo en noticias
+DROP INDEX IF EXISTS idx_market_news_search;
+ALTER TABLE market_news DROP COLUMN IF EXISTS search_vector;
+DROP TEXT SEARCH CONFIGURATION IF EXISTS es_unaccent;
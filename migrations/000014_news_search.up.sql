@@ -0,0 +1,22 @@
+-- Búsqueda de texto completo en noticias: configuración en español que ignora acentos
+CREATE EXTENSION IF NOT EXISTS "unaccent";
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'es_unaccent') THEN
+        CREATE TEXT SEARCH CONFIGURATION es_unaccent (COPY = spanish);
+        ALTER TEXT SEARCH CONFIGURATION es_unaccent
+            ALTER MAPPING FOR hword, hword_part, word WITH unaccent, spanish_stem;
+    END IF;
+END
+$$;
+
+-- Título con más peso que el resumen, y éste más que el contenido
+ALTER TABLE market_news ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('es_unaccent', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('es_unaccent', coalesce(summary, '')), 'B') ||
+        setweight(to_tsvector('es_unaccent', coalesce(content, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_market_news_search ON market_news USING GIN(search_vector);
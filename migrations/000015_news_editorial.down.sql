@@ -0,0 +1,7 @@
+-- Rollback del flujo editorial de noticias
+DROP TABLE IF EXISTS market_news_revisions;
+DROP INDEX IF EXISTS idx_market_news_status;
+ALTER TABLE market_news DROP COLUMN IF EXISTS review_note;
+ALTER TABLE market_news DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE market_news DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE market_news DROP COLUMN IF EXISTS status;
@@ -0,0 +1,33 @@
+-- Flujo editorial de noticias: borrador, revisión, publicada y archivada.
+-- Las noticias existentes quedan publicadas; las nuevas nacen como borrador.
+ALTER TABLE market_news ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'in_review', 'published', 'archived'));
+ALTER TABLE market_news ALTER COLUMN status SET DEFAULT 'draft';
+
+ALTER TABLE market_news ADD COLUMN IF NOT EXISTS reviewed_by UUID REFERENCES users(id);
+ALTER TABLE market_news ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE market_news ADD COLUMN IF NOT EXISTS review_note TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_market_news_status ON market_news(status);
+
+-- Versiones del contenido de cada noticia, una por creación o edición
+CREATE TABLE IF NOT EXISTS market_news_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    news_id UUID NOT NULL REFERENCES market_news(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    summary VARCHAR(500),
+    image_url VARCHAR(500),
+    category VARCHAR(100),
+    priority INTEGER,
+    edited_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (news_id, revision)
+);
+
+-- Versión inicial de las noticias existentes
+INSERT INTO market_news_revisions (news_id, revision, title, content, summary, image_url, category, priority, edited_by, created_at)
+SELECT id, 1, title, content, summary, image_url, category, priority, created_by, updated_at
+FROM market_news
+ON CONFLICT (news_id, revision) DO NOTHING;
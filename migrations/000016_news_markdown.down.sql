@@ -0,0 +1,3 @@
+-- Rollback de contenido Markdown en noticias
+ALTER TABLE market_news DROP COLUMN IF EXISTS summary_generated;
+ALTER TABLE market_news DROP COLUMN IF EXISTS content_html;
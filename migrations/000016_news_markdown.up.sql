@@ -0,0 +1,6 @@
+-- Contenido de noticias en Markdown: content guarda la fuente y content_html el HTML sanitizado.
+-- Las noticias existentes se renderizan al leerlas hasta su próxima edición.
+ALTER TABLE market_news ADD COLUMN IF NOT EXISTS content_html TEXT;
+
+-- El resumen generado automáticamente se actualiza con el contenido hasta que se escriba uno propio
+ALTER TABLE market_news ADD COLUMN IF NOT EXISTS summary_generated BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,5 @@
+-- Rollback de la biblioteca de imágenes
+DROP INDEX IF EXISTS idx_market_news_image_media_id;
+ALTER TABLE market_news_revisions DROP COLUMN IF EXISTS image_media_id;
+ALTER TABLE market_news DROP COLUMN IF EXISTS image_media_id;
+DROP TABLE IF EXISTS media;
@@ -0,0 +1,21 @@
+-- Biblioteca de imágenes subidas por administradores, con sus versiones redimensionadas
+CREATE TABLE IF NOT EXISTS media (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    original_name VARCHAR(255) NOT NULL,
+    mime_type VARCHAR(100) NOT NULL,
+    file_size BIGINT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    alt_text VARCHAR(255),
+    renditions JSONB NOT NULL,
+    uploaded_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_created_at ON media(created_at DESC);
+
+-- Las noticias referencian imágenes de la biblioteca en lugar de URLs externas
+ALTER TABLE market_news ADD COLUMN IF NOT EXISTS image_media_id UUID REFERENCES media(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_market_news_image_media_id ON market_news(image_media_id);
+
+ALTER TABLE market_news_revisions ADD COLUMN IF NOT EXISTS image_media_id UUID REFERENCES media(id) ON DELETE SET NULL;
@@ -0,0 +1,8 @@
+-- Rollback de la importación de noticias externas
+DROP INDEX IF EXISTS idx_market_news_source_url;
+DROP INDEX IF EXISTS idx_market_news_source_guid;
+ALTER TABLE market_news DROP COLUMN IF EXISTS source_url;
+ALTER TABLE market_news DROP COLUMN IF EXISTS source_guid;
+ALTER TABLE market_news DROP COLUMN IF EXISTS source_id;
+DROP TRIGGER IF EXISTS update_news_sources_updated_at ON news_sources;
+DROP TABLE IF EXISTS news_sources;
@@ -0,0 +1,26 @@
+-- Fuentes externas RSS/Atom desde las que se importan noticias como borradores
+CREATE TABLE IF NOT EXISTS news_sources (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    feed_url VARCHAR(500) NOT NULL UNIQUE,
+    category VARCHAR(100) NOT NULL DEFAULT 'general',
+    category_map JSONB NOT NULL DEFAULT '{}',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    etag VARCHAR(255),
+    last_modified VARCHAR(100),
+    last_polled_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TRIGGER update_news_sources_updated_at BEFORE UPDATE ON news_sources FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Origen de las noticias importadas, para no importar dos veces el mismo artículo
+ALTER TABLE market_news ADD COLUMN IF NOT EXISTS source_id UUID REFERENCES news_sources(id) ON DELETE SET NULL;
+ALTER TABLE market_news ADD COLUMN IF NOT EXISTS source_guid VARCHAR(500);
+ALTER TABLE market_news ADD COLUMN IF NOT EXISTS source_url VARCHAR(500);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_market_news_source_guid ON market_news(source_id, source_guid) WHERE source_guid IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_market_news_source_url ON market_news(source_url) WHERE source_url IS NOT NULL;
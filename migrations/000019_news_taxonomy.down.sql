@@ -0,0 +1,8 @@
+-- Rollback de categorías administrables y etiquetas
+DROP TABLE IF EXISTS market_news_tags;
+DROP TABLE IF EXISTS news_tags;
+ALTER TABLE news_sources DROP CONSTRAINT IF EXISTS news_sources_category_fkey;
+ALTER TABLE market_news DROP CONSTRAINT IF EXISTS market_news_category_fkey;
+ALTER TABLE market_news ALTER COLUMN category DROP NOT NULL;
+DROP TRIGGER IF EXISTS update_news_categories_updated_at ON news_categories;
+DROP TABLE IF EXISTS news_categories;
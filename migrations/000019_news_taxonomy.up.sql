@@ -0,0 +1,50 @@
+-- Categorías de noticias administrables y etiquetas libres
+CREATE TABLE IF NOT EXISTS news_categories (
+    slug VARCHAR(100) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TRIGGER update_news_categories_updated_at BEFORE UPDATE ON news_categories FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Categorías que antes estaban fijas en la validación
+INSERT INTO news_categories (slug, name, sort_order) VALUES
+    ('general', 'General', 1),
+    ('markets', 'Mercados', 2),
+    ('crypto', 'Criptomonedas', 3),
+    ('analysis', 'Análisis', 4),
+    ('regulation', 'Regulación', 5)
+ON CONFLICT (slug) DO NOTHING;
+
+-- Cualquier otra categoría ya usada por noticias o fuentes se conserva
+INSERT INTO news_categories (slug, name, sort_order)
+SELECT DISTINCT category, category, 100 FROM market_news WHERE category IS NOT NULL
+UNION
+SELECT DISTINCT category, category, 100 FROM news_sources
+ON CONFLICT (slug) DO NOTHING;
+
+UPDATE market_news SET category = 'general' WHERE category IS NULL;
+ALTER TABLE market_news ALTER COLUMN category SET NOT NULL;
+ALTER TABLE market_news ADD CONSTRAINT market_news_category_fkey
+    FOREIGN KEY (category) REFERENCES news_categories(slug) ON UPDATE CASCADE;
+ALTER TABLE news_sources ADD CONSTRAINT news_sources_category_fkey
+    FOREIGN KEY (category) REFERENCES news_categories(slug) ON UPDATE CASCADE;
+
+CREATE TABLE IF NOT EXISTS news_tags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(50) NOT NULL,
+    slug VARCHAR(60) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS market_news_tags (
+    news_id UUID NOT NULL REFERENCES market_news(id) ON DELETE CASCADE,
+    tag_id UUID NOT NULL REFERENCES news_tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (news_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_market_news_tags_tag_id ON market_news_tags(tag_id);
@@ -0,0 +1,3 @@
+-- Rollback de visitas y lecturas de noticias
+DROP TABLE IF EXISTS news_reads;
+DROP TABLE IF EXISTS news_views;
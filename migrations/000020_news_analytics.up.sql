@@ -0,0 +1,24 @@
+-- Visitas y lecturas de noticias por usuario
+CREATE TABLE IF NOT EXISTS news_views (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    news_id UUID NOT NULL REFERENCES market_news(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    viewed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_news_views_news_id ON news_views(news_id, viewed_at);
+CREATE INDEX IF NOT EXISTS idx_news_views_viewed_at ON news_views(viewed_at);
+
+-- Estado de lectura de cada usuario por noticia: read_at indica que leyó la noticia completa
+CREATE TABLE IF NOT EXISTS news_reads (
+    news_id UUID NOT NULL REFERENCES market_news(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    first_viewed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_viewed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    view_count INTEGER NOT NULL DEFAULT 0,
+    read_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (news_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_news_reads_user_id ON news_reads(user_id);
+CREATE INDEX IF NOT EXISTS idx_news_reads_read_at ON news_reads(read_at) WHERE read_at IS NOT NULL;
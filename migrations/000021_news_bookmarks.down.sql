@@ -0,0 +1,2 @@
+-- Rollback de noticias guardadas
+DROP TABLE IF EXISTS news_bookmarks;
@@ -0,0 +1,10 @@
+-- Noticias guardadas por los usuarios para leer más tarde
+CREATE TABLE IF NOT EXISTS news_bookmarks (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    news_id UUID NOT NULL REFERENCES market_news(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, news_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_news_bookmarks_user_created ON news_bookmarks(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_news_bookmarks_news_id ON news_bookmarks(news_id);
@@ -0,0 +1,5 @@
+-- Rollback de reacciones, comentarios y usuarios silenciados
+DROP TABLE IF EXISTS news_muted_users;
+DROP TRIGGER IF EXISTS update_news_comments_updated_at ON news_comments;
+DROP TABLE IF EXISTS news_comments;
+DROP TABLE IF EXISTS news_reactions;
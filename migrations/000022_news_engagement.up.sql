@@ -0,0 +1,44 @@
+-- Reacciones a noticias: una por usuario y noticia
+CREATE TABLE IF NOT EXISTS news_reactions (
+    news_id UUID NOT NULL REFERENCES market_news(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reaction VARCHAR(10) NOT NULL CHECK (reaction IN ('like', 'bullish', 'bearish')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (news_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_news_reactions_news_id ON news_reactions(news_id, reaction);
+
+-- Comentarios en hilos: thread_id es el comentario raíz (el propio id en los de primer nivel)
+CREATE TABLE IF NOT EXISTS news_comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    news_id UUID NOT NULL REFERENCES market_news(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES news_comments(id) ON DELETE CASCADE,
+    thread_id UUID NOT NULL,
+    depth INTEGER NOT NULL DEFAULT 0,
+    content TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'deleted')),
+    flag_reasons TEXT[] NOT NULL DEFAULT '{}',
+    moderated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    moderated_at TIMESTAMP WITH TIME ZONE,
+    moderation_note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_news_comments_news_id ON news_comments(news_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_news_comments_thread_id ON news_comments(thread_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_news_comments_pending ON news_comments(created_at) WHERE status = 'pending';
+
+CREATE TRIGGER update_news_comments_updated_at BEFORE UPDATE ON news_comments
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Usuarios silenciados: no pueden comentar mientras el silencio esté vigente (muted_until NULL = indefinido)
+CREATE TABLE IF NOT EXISTS news_muted_users (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT,
+    muted_until TIMESTAMP WITH TIME ZONE,
+    muted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
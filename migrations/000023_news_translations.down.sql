@@ -0,0 +1,5 @@
+-- Rollback de traducciones de noticias e idioma preferido
+ALTER TABLE users DROP COLUMN IF EXISTS locale;
+DROP TABLE IF EXISTS market_news_translation_revisions;
+DROP TRIGGER IF EXISTS update_market_news_translations_updated_at ON market_news_translations;
+DROP TABLE IF EXISTS market_news_translations;
@@ -0,0 +1,35 @@
+-- Traducciones de noticias: el contenido original está en el idioma por defecto (es)
+CREATE TABLE IF NOT EXISTS market_news_translations (
+    news_id UUID NOT NULL REFERENCES market_news(id) ON DELETE CASCADE,
+    language VARCHAR(10) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    summary TEXT,
+    summary_generated BOOLEAN NOT NULL DEFAULT false,
+    content TEXT NOT NULL,
+    content_html TEXT NOT NULL,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (news_id, language)
+);
+
+CREATE TRIGGER update_market_news_translations_updated_at BEFORE UPDATE ON market_news_translations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Versiones de cada traducción; como las de la noticia, cuentan para decidir quién puede aprobarla
+CREATE TABLE IF NOT EXISTS market_news_translation_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    news_id UUID NOT NULL REFERENCES market_news(id) ON DELETE CASCADE,
+    language VARCHAR(10) NOT NULL,
+    revision INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    summary TEXT,
+    content TEXT NOT NULL,
+    deleted BOOLEAN NOT NULL DEFAULT false, -- la traducción se eliminó; guarda el último contenido
+    edited_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (news_id, language, revision)
+);
+
+-- Idioma preferido de cada usuario (NULL = según Accept-Language)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(10);
@@ -0,0 +1,5 @@
+-- Rollback de alertas de noticias
+DROP INDEX IF EXISTS idx_notifications_news_alerts;
+DROP INDEX IF EXISTS idx_market_news_alert_pending;
+ALTER TABLE market_news DROP COLUMN IF EXISTS alerted_at;
+DROP TABLE IF EXISTS news_category_subscriptions;
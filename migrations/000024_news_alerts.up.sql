@@ -0,0 +1,22 @@
+-- Suscripciones de usuarios a categorías de noticias para recibir alertas
+CREATE TABLE IF NOT EXISTS news_category_subscriptions (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category VARCHAR(100) NOT NULL REFERENCES news_categories(slug) ON UPDATE CASCADE ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, category)
+);
+
+CREATE INDEX IF NOT EXISTS idx_news_category_subscriptions_category ON news_category_subscriptions(category);
+
+-- Momento en que se evaluó la noticia para alertar a los suscriptores (NULL = pendiente)
+ALTER TABLE market_news ADD COLUMN IF NOT EXISTS alerted_at TIMESTAMP WITH TIME ZONE;
+
+-- Las noticias ya visibles no generan alertas retroactivas
+UPDATE market_news SET alerted_at = NOW()
+WHERE status = 'published' AND published_at <= NOW() AND alerted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_market_news_alert_pending ON market_news(published_at) WHERE alerted_at IS NULL;
+
+-- Alertas recientes por usuario para el límite de envíos
+CREATE INDEX IF NOT EXISTS idx_notifications_news_alerts ON notifications(user_id, created_at)
+    WHERE category = 'market' AND data->>'event' = 'news_published';